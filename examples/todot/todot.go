@@ -1,14 +1,13 @@
 package main
 
 import (
-	"../../src/graph/_obj/graph"
-	
 	"fmt"
 	"flag"
 	"os"
 	"path"
 
-	"github.com/StepLg/go-erx/src/erx"
+	"github.com/StepLg/go-graph/src/erx"
+	"github.com/StepLg/go-graph/src/graph"
 )
 
 func main() {
@@ -46,8 +45,8 @@ replacing it's extension to ".dot."`)
 	
 	infile := os.Stdin
 	if *flag_inputFile!="" {
-		var err os.Error
-		infile, err = os.Open(*flag_inputFile, os.O_RDONLY, 0000)
+		var err error
+		infile, err = os.Open(*flag_inputFile)
 		if err!=nil {
 			erxErr := erx.NewSequent("Can't open input file.", err)
 			erxErr.AddV("file name", *flag_inputFile)
@@ -78,8 +77,8 @@ replacing it's extension to ".dot."`)
 	
 	outfile := os.Stdout
 	if *flag_outputFile!="" {
-		var err os.Error
-		outfile, err = os.Open(*flag_outputFile, os.O_WRONLY | os.O_CREAT | os.O_TRUNC, 0644)
+		var err error
+		outfile, err = os.OpenFile(*flag_outputFile, os.O_WRONLY | os.O_CREATE | os.O_TRUNC, 0644)
 		if err!=nil {
 			erxErr := erx.NewSequent("Can't open output file.", err)
 			erxErr.AddV("file name", *flag_outputFile)
@@ -0,0 +1,3 @@
+module github.com/StepLg/go-graph
+
+go 1.23
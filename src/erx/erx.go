@@ -0,0 +1,177 @@
+// Extended errors with call location, variables and sequent (nested) errors.
+//
+// Errors are usually created with NewError at the place where something goes
+// wrong and then wrapped with NewSequent while panic unwinds the stack, so the
+// final error contains the whole chain of contexts with values, attached by
+// AddV on each level.
+package erx
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Named value, attached to error.
+type Variable struct {
+	Name  string
+	Value interface{}
+}
+
+// Extended error interface.
+type Error interface {
+	error
+
+	// Error message without nested errors and variables.
+	Message() string
+	// File, where error was created.
+	File() string
+	// Line in file, where error was created.
+	Line() int
+	// Function name, where error was created.
+	Func() string
+	// All variables attached to error in order they were added.
+	Variables() []Variable
+	// All nested errors. Each element is either Error, error or any other
+	// value, recovered from panic.
+	Errors() []interface{}
+
+	// Attach variable to error.
+	AddV(name string, value interface{})
+	// Attach nested error.
+	AddE(err interface{})
+}
+
+type errorImpl struct {
+	message   string
+	file      string
+	line      int
+	funcName  string
+	variables []Variable
+	errors    []interface{}
+}
+
+// Create new error with message.
+func NewError(msg string) Error {
+	return newErrorLevel(msg, 2)
+}
+
+// Create new error with message and one nested error.
+//
+// err could be any value, for example recovered from panic.
+func NewSequent(msg string, err interface{}) Error {
+	res := newErrorLevel(msg, 2)
+	res.AddE(err)
+	return res
+}
+
+// Create new error with message and one nested error.
+//
+// level is a number of additional stack frames to skip while detecting
+// error location. It's useful for helper functions, which create errors
+// for their callers.
+func NewSequentLevel(msg string, err interface{}, level int) Error {
+	res := newErrorLevel(msg, level+2)
+	res.AddE(err)
+	return res
+}
+
+func newErrorLevel(msg string, skip int) *errorImpl {
+	err := &errorImpl{message: msg}
+	if pc, file, line, ok := runtime.Caller(skip); ok {
+		err.file = cutPath(file)
+		err.line = line
+		if f := runtime.FuncForPC(pc); f != nil {
+			err.funcName = f.Name()
+		}
+	}
+	return err
+}
+
+func (e *errorImpl) Message() string {
+	return e.message
+}
+
+func (e *errorImpl) File() string {
+	return e.file
+}
+
+func (e *errorImpl) Line() int {
+	return e.line
+}
+
+func (e *errorImpl) Func() string {
+	return e.funcName
+}
+
+func (e *errorImpl) Variables() []Variable {
+	return e.variables
+}
+
+func (e *errorImpl) Errors() []interface{} {
+	return e.errors
+}
+
+func (e *errorImpl) AddV(name string, value interface{}) {
+	e.variables = append(e.variables, Variable{Name: name, Value: value})
+}
+
+func (e *errorImpl) AddE(err interface{}) {
+	if err != nil {
+		e.errors = append(e.errors, err)
+	}
+}
+
+// Error message with messages of all nested errors.
+func (e *errorImpl) Error() string {
+	chunks := make([]string, 0, 1+len(e.errors))
+	if e.message != "" {
+		chunks = append(chunks, e.message)
+	}
+	for _, nested := range e.errors {
+		switch nested := nested.(type) {
+		case error:
+			chunks = append(chunks, nested.Error())
+		default:
+			chunks = append(chunks, fmt.Sprint(nested))
+		}
+	}
+	return strings.Join(chunks, ": ")
+}
+
+// Nested errors, which implement error interface.
+//
+// Used by errors.Is and errors.As to walk through the chain.
+func (e *errorImpl) Unwrap() []error {
+	res := make([]error, 0, len(e.errors))
+	for _, nested := range e.errors {
+		if nestedErr, ok := nested.(error); ok {
+			res = append(res, nestedErr)
+		}
+	}
+	return res
+}
+
+var (
+	pathCutsLock sync.RWMutex
+	pathCuts     []string
+)
+
+// Add directory prefix to cut from file names in errors.
+func AddPathCut(path string) {
+	pathCutsLock.Lock()
+	defer pathCutsLock.Unlock()
+	pathCuts = append(pathCuts, path)
+}
+
+func cutPath(file string) string {
+	pathCutsLock.RLock()
+	defer pathCutsLock.RUnlock()
+	for _, prefix := range pathCuts {
+		if strings.HasPrefix(file, prefix) {
+			return file[len(prefix):]
+		}
+	}
+	return file
+}
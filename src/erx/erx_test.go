@@ -0,0 +1,38 @@
+package erx
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSequentError(t *testing.T) {
+	err := NewSequent("Reading file.", io.EOF)
+	err.AddV("file", "test.dgr")
+
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("nested error isn't found with errors.Is")
+	}
+	if err.Error() != "Reading file.: EOF" {
+		t.Errorf("unexpected error message: %v", err.Error())
+	}
+	if err.Line() == 0 || !strings.HasSuffix(err.File(), "erx_test.go") {
+		t.Errorf("wrong error location: %v:%v", err.File(), err.Line())
+	}
+
+	text := NewStringFormatter("  ").Format(err)
+	if !strings.Contains(text, "file: test.dgr") || !strings.Contains(text, "  EOF") {
+		t.Errorf("unexpected formatted error:\n%v", text)
+	}
+}
+
+func TestSequentLevel(t *testing.T) {
+	makeError := func() Error {
+		return NewSequentLevel("Helper error.", "cause", 1)
+	}
+	err := makeError()
+	if err.Func() != "github.com/StepLg/go-graph/src/erx.TestSequentLevel" {
+		t.Errorf("wrong error function: %v", err.Func())
+	}
+}
@@ -0,0 +1,49 @@
+package erx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Errors formatter.
+type Formatter interface {
+	Format(err Error) string
+}
+
+// Multiline text formatter.
+//
+// Each nested error is shifted with indent string.
+type StringFormatter struct {
+	indent string
+}
+
+// Create new text formatter with indent for nested errors.
+func NewStringFormatter(indent string) *StringFormatter {
+	return &StringFormatter{indent: indent}
+}
+
+func (f *StringFormatter) Format(err Error) string {
+	var b strings.Builder
+	f.format(&b, err, "")
+	return b.String()
+}
+
+func (f *StringFormatter) format(b *strings.Builder, err interface{}, prefix string) {
+	switch err := err.(type) {
+	case Error:
+		fmt.Fprintf(b, "%s%s\n", prefix, err.Message())
+		if err.File() != "" {
+			fmt.Fprintf(b, "%s%s%s:%d %s\n", prefix, f.indent, err.File(), err.Line(), err.Func())
+		}
+		for _, v := range err.Variables() {
+			fmt.Fprintf(b, "%s%s%s: %v\n", prefix, f.indent, v.Name, v.Value)
+		}
+		for _, nested := range err.Errors() {
+			f.format(b, nested, prefix+f.indent)
+		}
+	case error:
+		fmt.Fprintf(b, "%s%s\n", prefix, err.Error())
+	default:
+		fmt.Fprintf(b, "%s%v\n", prefix, err)
+	}
+}
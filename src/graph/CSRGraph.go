@@ -0,0 +1,492 @@
+package graph
+
+import (
+	"iter"
+	"math"
+	"sort"
+
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Graph with dense vertexes index.
+//
+// Vertexes are numbered from 0 to Order()-1, so algorithms could store
+// vertexes marks in slices instead of maps.
+type DenseIndexed interface {
+	// Getting nodes count in graph
+	Order() int
+	// Getting dense index of node
+	VertexIndex(node VertexId) (int, bool)
+	// Getting node by it's dense index
+	VertexAt(index int) VertexId
+}
+
+// Dense indexed graph behind neighbours extractor, or nil.
+func denseIndexOf(gr OutNeighboursExtractor) DenseIndexed {
+	var source interface{} = gr
+	switch e := gr.(type) {
+		case *dgraphOutNeighboursExtractor:
+			source = e.dgraph
+		case *ugraphOutNeighboursExtractor:
+			source = e.ugraph
+		case *mgraphOutNeighboursExtractor:
+			source = e.mgraph
+	}
+	dense, _ := source.(DenseIndexed)
+	return dense
+}
+
+// Vertexes marks for algorithms.
+//
+// Marks are stored in slice, if graph is dense indexed, and in map
+// otherwise (or for vertexes, which are out of dense index).
+type vertexesMarks[T any] struct {
+	dense DenseIndexed
+	values []T
+	marked []bool
+	other map[VertexId]T
+}
+
+func newVertexesMarks[T any](gr OutNeighboursExtractor) *vertexesMarks[T] {
+	m := &vertexesMarks[T]{
+		dense: denseIndexOf(gr),
+		other: make(map[VertexId]T),
+	}
+	if m.dense!=nil {
+		m.values = make([]T, m.dense.Order())
+		m.marked = make([]bool, m.dense.Order())
+	}
+	return m
+}
+
+func (m *vertexesMarks[T]) get(node VertexId) (value T, ok bool) {
+	if m.dense!=nil {
+		if index, isIndexed := m.dense.VertexIndex(node); isIndexed {
+			return m.values[index], m.marked[index]
+		}
+	}
+	value, ok = m.other[node]
+	return
+}
+
+func (m *vertexesMarks[T]) set(node VertexId, value T) {
+	if m.dense!=nil {
+		if index, isIndexed := m.dense.VertexIndex(node); isIndexed {
+			m.values[index], m.marked[index] = value, true
+			return
+		}
+	}
+	m.other[node] = value
+}
+
+// Checking if node is marked
+func (m *vertexesMarks[T]) has(node VertexId) bool {
+	_, ok := m.get(node)
+	return ok
+}
+
+// Compressed sparse row: connections of row i are
+// targets[offsets[i]:offsets[i+1]], sorted by target index.
+type csrAdjacency struct {
+	offsets []int
+	targets []int32
+}
+
+// Building adjacency from connections list, given by dense indexes.
+func newCsrAdjacency(order int, tails, heads []int32) csrAdjacency {
+	adj := csrAdjacency{
+		offsets: make([]int, order+1),
+		targets: make([]int32, len(heads)),
+	}
+	for _, tail := range tails {
+		adj.offsets[tail+1]++
+	}
+	for i:=0; i<order; i++ {
+		adj.offsets[i+1] += adj.offsets[i]
+	}
+	fill := make([]int, order)
+	copy(fill, adj.offsets[:order])
+	for i, tail := range tails {
+		adj.targets[fill[tail]] = heads[i]
+		fill[tail]++
+	}
+	for i:=0; i<order; i++ {
+		row := adj.row(i)
+		sort.Slice(row, func(a, b int) bool { return row[a]<row[b] })
+	}
+	return adj
+}
+
+func (adj csrAdjacency) row(index int) []int32 {
+	return adj.targets[adj.offsets[index]:adj.offsets[index+1]]
+}
+
+// Binary search of target in row.
+func (adj csrAdjacency) contains(index int, target int32) bool {
+	row := adj.row(index)
+	pos := sort.Search(len(row), func(i int) bool { return row[i]>=target })
+	return pos<len(row) && row[pos]==target
+}
+
+// Sorted vertexes list, used as dense index.
+type csrVertexes []VertexId
+
+// Panics with errMessage, if vertexes don't fit into int32 dense indexes.
+func newCsrVertexes(gr VertexesIterable, errMessage string) csrVertexes {
+	vertexes := csrVertexes(CollectVertexes(gr))
+	if len(vertexes)>math.MaxInt32 {
+		err := erx.NewSequent(errMessage, erx.NewError("Too many nodes for 32-bit dense indexes."))
+		err.AddV("nodes count", len(vertexes))
+		panic(err)
+	}
+	sort.Slice(vertexes, func(i, j int) bool { return vertexes[i]<vertexes[j] })
+	return vertexes
+}
+
+func (vertexes csrVertexes) index(node VertexId) (int, bool) {
+	pos := sort.Search(len(vertexes), func(i int) bool { return vertexes[i]>=node })
+	return pos, pos<len(vertexes) && vertexes[pos]==node
+}
+
+func (vertexes csrVertexes) seq() iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
+		for _, node := range vertexes {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+// Vertexes iterable over dense indexes list.
+func (vertexes csrVertexes) iterable(indexes []int32) VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for _, index := range indexes {
+			if !yield(vertexes[index]) {
+				return
+			}
+		}
+	}
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Immutable directed graph in compressed sparse row format.
+//
+// Arcs are stored twice: by tails (CSR) and by heads (CSC), so both accessors
+// and predecessors are contiguous slices. CheckArc takes O(log d) time, where
+// d is node out degree. Graph uses about 8 bytes per arc and 24 bytes per
+// node. Create it with FreezeDirected.
+type DirectedCSR struct {
+	vertexes csrVertexes
+	out csrAdjacency
+	in csrAdjacency
+}
+
+// Creating immutable copy of directed graph.
+//
+// Panics, if graph has more than math.MaxInt32 nodes.
+func FreezeDirected(gr DirectedGraphReader) *DirectedCSR {
+	g := &DirectedCSR{
+		vertexes: newCsrVertexes(gr, "Freeze directed graph."),
+	}
+	tails := make([]int32, 0, gr.ArcsCnt())
+	heads := make([]int32, 0, gr.ArcsCnt())
+	for arc := range ArcsSeqOf(gr) {
+		tail, _ := g.vertexes.index(arc.Tail)
+		head, _ := g.vertexes.index(arc.Head)
+		tails = append(tails, int32(tail))
+		heads = append(heads, int32(head))
+	}
+	g.out = newCsrAdjacency(len(g.vertexes), tails, heads)
+	g.in = newCsrAdjacency(len(g.vertexes), heads, tails)
+	return g
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DenseIndexed
+
+// Getting dense index of node
+func (g *DirectedCSR) VertexIndex(node VertexId) (int, bool) {
+	return g.vertexes.index(node)
+}
+
+// Getting node by it's dense index
+func (g *DirectedCSR) VertexAt(index int) VertexId {
+	return g.vertexes[index]
+}
+
+// Dense indexes of node accessors, sorted.
+//
+// Result is an internal slice, it must not be modified.
+func (g *DirectedCSR) AccessorsIndexes(index int) []int32 {
+	return g.out.row(index)
+}
+
+// Dense indexes of node predecessors, sorted.
+//
+// Result is an internal slice, it must not be modified.
+func (g *DirectedCSR) PredecessorsIndexes(index int) []int32 {
+	return g.in.row(index)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// VertexesIterable
+
+func (g *DirectedCSR) VertexesIter() <-chan VertexId {
+	return seqToChan(g.VertexesSeq())
+}
+
+func (g *DirectedCSR) VertexesSeq() iter.Seq[VertexId] {
+	return g.vertexes.seq()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// ConnectionsIterable
+
+func (g *DirectedCSR) ConnectionsIter() <-chan Connection {
+	return g.ArcsIter()
+}
+
+func (g *DirectedCSR) ConnectionsSeq() iter.Seq[Connection] {
+	return g.ArcsSeq()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesReader
+
+func (g *DirectedCSR) CheckNode(node VertexId) bool {
+	_, exists := g.vertexes.index(node)
+	return exists
+}
+
+func (g *DirectedCSR) Order() int {
+	return len(g.vertexes)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DirectedGraphArcsReader
+
+func (g *DirectedCSR) ArcsCnt() int {
+	return len(g.out.targets)
+}
+
+func (g *DirectedCSR) ArcsIter() <-chan Connection {
+	return seqToChan(g.ArcsSeq())
+}
+
+func (g *DirectedCSR) ArcsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for tail, node := range g.vertexes {
+			for _, head := range g.out.row(tail) {
+				if !yield(Connection{node, g.vertexes[head]}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Getting all graph sources.
+func (g *DirectedCSR) GetSources() VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for index, node := range g.vertexes {
+			if len(g.in.row(index))==0 {
+				if !yield(node) {
+					return
+				}
+			}
+		}
+	}
+
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Getting all graph sinks.
+func (g *DirectedCSR) GetSinks() VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for index, node := range g.vertexes {
+			if len(g.out.row(index))==0 {
+				if !yield(node) {
+					return
+				}
+			}
+		}
+	}
+
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Getting node accessors
+func (g *DirectedCSR) GetAccessors(node VertexId) VertexesIterable {
+	return g.vertexes.iterable(g.out.row(g.mustIndex(node, "Get node accessors in directed graph.")))
+}
+
+// Getting node predecessors
+func (g *DirectedCSR) GetPredecessors(node VertexId) VertexesIterable {
+	return g.vertexes.iterable(g.in.row(g.mustIndex(node, "Get node predecessors in directed graph.")))
+}
+
+// Checking arrow existance between node1 and node2
+//
+// node1 and node2 must exist in graph or error will be returned
+func (g *DirectedCSR) CheckArc(from, to VertexId) bool {
+	fromIndex := g.mustIndex(from, "Checking arc existance in graph.")
+	toIndex := g.mustIndex(to, "Checking arc existance in graph.")
+	return g.out.contains(fromIndex, int32(toIndex))
+}
+
+// Dense index of node, panic with errorMsg if node doesn't exist.
+func (g *DirectedCSR) mustIndex(node VertexId, errorMsg string) int {
+	index, ok := g.vertexes.index(node)
+	if !ok {
+		err := erx.NewSequentLevel(errorMsg, &VertexError{Op: "get index", Node: node, Err: ErrNodeNotFound}, 2)
+		err.AddV("node", node)
+		panic(err)
+	}
+	return index
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Immutable undirected graph in compressed sparse row format.
+//
+// Each edge is stored in rows of both nodes (loop is stored once), so
+// neighbours are contiguous slice. CheckEdge takes O(log d) time, where d is
+// node degree. Create it with FreezeUndirected.
+type UndirectedCSR struct {
+	vertexes csrVertexes
+	adj csrAdjacency
+	edgesCnt int
+}
+
+// Creating immutable copy of undirected graph.
+//
+// Panics, if graph has more than math.MaxInt32 nodes.
+func FreezeUndirected(gr UndirectedGraphReader) *UndirectedCSR {
+	g := &UndirectedCSR{
+		vertexes: newCsrVertexes(gr, "Freeze undirected graph."),
+	}
+	tails := make([]int32, 0, 2*gr.EdgesCnt())
+	heads := make([]int32, 0, 2*gr.EdgesCnt())
+	for edge := range EdgesSeqOf(gr) {
+		tail, _ := g.vertexes.index(edge.Tail)
+		head, _ := g.vertexes.index(edge.Head)
+		tails = append(tails, int32(tail))
+		heads = append(heads, int32(head))
+		if tail!=head {
+			tails = append(tails, int32(head))
+			heads = append(heads, int32(tail))
+		}
+		g.edgesCnt++
+	}
+	g.adj = newCsrAdjacency(len(g.vertexes), tails, heads)
+	return g
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DenseIndexed
+
+// Getting dense index of node
+func (g *UndirectedCSR) VertexIndex(node VertexId) (int, bool) {
+	return g.vertexes.index(node)
+}
+
+// Getting node by it's dense index
+func (g *UndirectedCSR) VertexAt(index int) VertexId {
+	return g.vertexes[index]
+}
+
+// Dense indexes of node neighbours, sorted.
+//
+// Result is an internal slice, it must not be modified.
+func (g *UndirectedCSR) NeighboursIndexes(index int) []int32 {
+	return g.adj.row(index)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// VertexesIterable
+
+func (g *UndirectedCSR) VertexesIter() <-chan VertexId {
+	return seqToChan(g.VertexesSeq())
+}
+
+func (g *UndirectedCSR) VertexesSeq() iter.Seq[VertexId] {
+	return g.vertexes.seq()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// ConnectionsIterable
+
+func (g *UndirectedCSR) ConnectionsIter() <-chan Connection {
+	return g.EdgesIter()
+}
+
+func (g *UndirectedCSR) ConnectionsSeq() iter.Seq[Connection] {
+	return g.EdgesSeq()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesReader
+
+func (g *UndirectedCSR) CheckNode(node VertexId) bool {
+	_, exists := g.vertexes.index(node)
+	return exists
+}
+
+func (g *UndirectedCSR) Order() int {
+	return len(g.vertexes)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// UndirectedGraphEdgesReader
+
+func (g *UndirectedCSR) EdgesCnt() int {
+	return g.edgesCnt
+}
+
+func (g *UndirectedCSR) EdgesIter() <-chan Connection {
+	return seqToChan(g.EdgesSeq())
+}
+
+func (g *UndirectedCSR) EdgesSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for index, node := range g.vertexes {
+			for _, neighbour := range g.adj.row(index) {
+				if int(neighbour)<index {
+					// each edge is stored twice, except loops
+					continue
+				}
+				if !yield(Connection{node, g.vertexes[neighbour]}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Checking edge existance between node1 and node2
+//
+// node1 and node2 must exist in graph or error will be returned
+func (g *UndirectedCSR) CheckEdge(node1, node2 VertexId) bool {
+	index1 := g.mustIndex(node1, "Checking edge existance in graph.")
+	index2 := g.mustIndex(node2, "Checking edge existance in graph.")
+	return g.adj.contains(index1, int32(index2))
+}
+
+// Getting all nodes, connected to given one
+func (g *UndirectedCSR) GetNeighbours(node VertexId) VertexesIterable {
+	return g.vertexes.iterable(g.adj.row(g.mustIndex(node, "Get node neighbours in undirected graph.")))
+}
+
+// Dense index of node, panic with errorMsg if node doesn't exist.
+func (g *UndirectedCSR) mustIndex(node VertexId, errorMsg string) int {
+	index, ok := g.vertexes.index(node)
+	if !ok {
+		err := erx.NewSequentLevel(errorMsg, &VertexError{Op: "get index", Node: node, Err: ErrNodeNotFound}, 2)
+		err.AddV("node", node)
+		panic(err)
+	}
+	return index
+}
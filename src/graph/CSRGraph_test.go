@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestDirectedCSR(t *testing.T) {
+	src := generateDirectedGraph1()
+	src.AddArc(3, 2)
+	src.AddArc(5, 5)
+	src.AddNode(100)
+	gr := FreezeDirected(src)
+
+	t.Run("same graph", func(t *testing.T) {
+		expectTrue(t, DirectedGraphsEquals(gr, src), "frozen graph equals to source")
+		expectEquals(t, gr.Order(), src.Order())
+		expectEquals(t, gr.ArcsCnt(), src.ArcsCnt())
+		expectVertexesExactly(t, CollectVertexes(gr.GetSources()), 1, 100)
+		expectVertexesExactly(t, CollectVertexes(gr.GetSinks()), 6, 100)
+		expectVertexesExactly(t, CollectVertexes(gr.GetAccessors(2)), 3, 4, 6)
+		expectVertexesExactly(t, CollectVertexes(gr.GetPredecessors(4)), 2, 3)
+	})
+
+	t.Run("check arc", func(t *testing.T) {
+		for arc := range ArcsSeqOf(src) {
+			expectTrue(t, gr.CheckArc(arc.Tail, arc.Head), "arc exists")
+		}
+		expectTrue(t, gr.CheckArc(5, 5), "loop")
+		expectFalse(t, gr.CheckArc(6, 1), "arc 6->1")
+		expectFalse(t, gr.CheckArc(1, 100), "arc 1->100")
+		expectPanic(t, "unknown node", func() { gr.CheckArc(1, 7) })
+		expectPanic(t, "unknown node accessors", func() { CollectVertexes(gr.GetAccessors(7)) })
+	})
+
+	t.Run("dense index", func(t *testing.T) {
+		for i:=0; i<gr.Order(); i++ {
+			index, ok := gr.VertexIndex(gr.VertexAt(i))
+			expectTrue(t, ok, "vertex index exists")
+			expectEquals(t, index, i)
+		}
+		_, ok := gr.VertexIndex(7)
+		expectFalse(t, ok, "unknown vertex index exists")
+
+		index2, _ := gr.VertexIndex(2)
+		accessors := make([]VertexId, 0)
+		for _, index := range gr.AccessorsIndexes(index2) {
+			accessors = append(accessors, gr.VertexAt(int(index)))
+		}
+		expectPath(t, accessors, 3, 4, 6)
+	})
+
+	t.Run("algorithms", func(t *testing.T) {
+		marks := BellmanFordSingleSource(gr, 1, SimpleWeightFunc)
+		expectPath(t, PathFromMarks(marks, 5), 1, 2, 4, 5)
+		_, hasCycles := TopologicalSort(gr)
+		expectTrue(t, hasCycles, "graph has cycles")
+	})
+
+	t.Run("dense marks", func(t *testing.T) {
+		extractor := NewDgraphOutNeighboursExtractor(gr)
+		srcExtractor := NewDgraphOutNeighboursExtractor(src)
+		expectTrue(t, denseIndexOf(extractor)!=nil, "frozen graph is dense indexed")
+		expectTrue(t, denseIndexOf(srcExtractor)==nil, "map graph is dense indexed")
+
+		classes := make(map[EdgeClass]int)
+		DepthFirst(extractor, Vertexes{1, 100}, Visitor{
+			ClassifiedEdge: func(tail, head VertexId, class EdgeClass) bool {
+				classes[class]++
+				return true
+			},
+		})
+		expectEquals(t, classes[EC_TREE], 5)
+		expectEquals(t, classes[EC_TREE]+classes[EC_BACK]+classes[EC_FORWARD]+classes[EC_CROSS], gr.ArcsCnt())
+		expectTrue(t, classes[EC_BACK]>=2, "loop and arc 3->2 are back connections")
+
+		reached := make(Vertexes, 0)
+		BreadthFirst(extractor, Vertexes{3}, Visitor{
+			DiscoverVertex: func(node VertexId) bool {
+				reached = append(reached, node)
+				return true
+			},
+		})
+		expectVertexesExactly(t, reached, 2, 3, 4, 5, 6)
+
+		// vertexes out of dense index are marked in map
+		discovered := newVertexesMarks[bool](extractor)
+		discovered.set(7, true)
+		discovered.set(2, true)
+		expectTrue(t, discovered.has(7) && discovered.has(2), "vertexes are marked")
+		expectFalse(t, discovered.has(3), "vertex 3 is marked")
+		expectEquals(t, len(discovered.other), 1)
+
+		marks := DijkstraSingleSource(extractor, 1, SimpleWeightFunc)
+		srcMarks := DijkstraSingleSource(srcExtractor, 1, SimpleWeightFunc)
+		expectEquals(t, len(marks), len(srcMarks))
+		for node, mark := range srcMarks {
+			expectEquals(t, marks[node].Weight, mark.Weight)
+		}
+		weight, exists := CheckPathDijkstra(extractor, 1, 6, nil, SimpleWeightFunc)
+		expectTrue(t, exists, "path exists")
+		expectEquals(t, weight, srcMarks[6].Weight)
+	})
+}
+
+func TestUndirectedCSR(t *testing.T) {
+	src := NewUndirectedMatrix(0)
+	ReadUgraphLine(src, "1-2-3-4-1")
+	ReadUgraphLine(src, "2-5")
+	src.AddNode(10)
+	gr := FreezeUndirected(src)
+
+	expectTrue(t, UndirectedGraphsEquals(gr, src), "frozen graph equals to source")
+	expectEquals(t, gr.EdgesCnt(), 5)
+	expectVertexesExactly(t, CollectVertexes(gr.GetNeighbours(2)), 1, 3, 5)
+	expectEquals(t, len(CollectVertexes(gr.GetNeighbours(10))), 0)
+	expectTrue(t, gr.CheckEdge(4, 1), "edge 4-1")
+	expectFalse(t, gr.CheckEdge(1, 3), "edge 1-3")
+	expectPanic(t, "unknown node", func() { gr.CheckEdge(1, 7) })
+
+	weight, exists := CheckPathDijkstra(NewUgraphOutNeighboursExtractor(gr), 5, 4, nil, SimpleWeightFunc)
+	expectTrue(t, exists, "path exists")
+	expectEquals(t, weight, 3.0)
+
+	t.Run("Loop", func(t *testing.T) {
+		src := NewUndirectedMultiMap()
+		src.AddEdge(1, 1)
+		src.AddEdge(1, 2)
+		gr := FreezeUndirected(src)
+		expectEquals(t, gr.EdgesCnt(), 2)
+		edgesCnt := 0
+		for _ = range gr.EdgesSeq() {
+			edgesCnt++
+		}
+		expectEquals(t, edgesCnt, 2)
+		expectTrue(t, gr.CheckEdge(1, 1), "loop 1-1")
+		expectVertexesExactly(t, CollectVertexes(gr.GetNeighbours(1)), 1, 2)
+		expectTrue(t, UndirectedGraphsEquals(gr, src), "frozen graph equals to source")
+	})
+}
@@ -4,9 +4,7 @@ import (
 	"testing"
 	"runtime"
 	"strings"
-	"github.com/StepLg/go-erx/src/erx"
-	"github.com/orfjackal/gospec/src/gospec"
-	. "github.com/orfjackal/gospec/src/gospec"
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 func init() {
@@ -17,91 +15,89 @@ func init() {
 	erx.AddPathCut(prevDirName)
 }
 
-func DirectedGraphSpec(c gospec.Context, graphCreator func() DirectedGraph) {
-	gr := graphCreator()
-	
-	c.Specify("Empty directed graph", func() {
-		c.Specify("contain no nodes", func() {
-			c.Expect(gr.Order(), Equals, 0)
+func DirectedGraphSpec(t *testing.T, graphCreator func() DirectedGraph) {
+	t.Run("Empty directed graph", func(t *testing.T) {
+		gr := graphCreator()
+		t.Run("contain no nodes", func(t *testing.T) {
+			expectEquals(t, gr.Order(), 0)
 		})
-		c.Specify("contain no edges", func() {
-			c.Expect(gr.ArcsCnt(), Equals, 0)
+		t.Run("contain no edges", func(t *testing.T) {
+			expectEquals(t, gr.ArcsCnt(), 0)
 		})
 	})
-	
-	c.Specify("New node in empty graph", func() {
-		VertexId := VertexId(1)
-		gr.AddNode(VertexId)
-				
-		c.Specify("changing nodes count", func() {
-			c.Expect(gr.Order(), Equals, 1)
-		})
-		
-		c.Specify("doesn't change arrows count", func() {
-			c.Expect(gr.ArcsCnt(), Equals, 0)
-		})
-		
-		c.Specify("no accessors", func() {
-			accessors := CollectVertexes(gr.GetAccessors(VertexId))
-			c.Expect(len(accessors), Equals, 0)
-		})
-		
-		c.Specify("no predecessors", func() {
-			c.Expect(len(CollectVertexes(gr.GetPredecessors(VertexId))), Equals, 0)
-		})
-		
-		c.Specify("node becomes a source", func() {
-			sources := CollectVertexes(gr.GetSources())
-			c.Expect(sources, ContainsExactly, Values(VertexId))
+
+	t.Run("New node in empty graph", func(t *testing.T) {
+		gr := graphCreator()
+		vertexId := VertexId(1)
+		gr.AddNode(vertexId)
+
+		t.Run("changing nodes count", func(t *testing.T) {
+			expectEquals(t, gr.Order(), 1)
 		})
 
-		c.Specify("node becomes a sink", func() {
-			sinks := CollectVertexes(gr.GetSinks())
-			c.Expect(sinks, ContainsExactly, Values(VertexId))
+		t.Run("doesn't change arrows count", func(t *testing.T) {
+			expectEquals(t, gr.ArcsCnt(), 0)
+		})
+
+		t.Run("no accessors", func(t *testing.T) {
+			expectEquals(t, len(CollectVertexes(gr.GetAccessors(vertexId))), 0)
 		})
 
+		t.Run("no predecessors", func(t *testing.T) {
+			expectEquals(t, len(CollectVertexes(gr.GetPredecessors(vertexId))), 0)
+		})
+
+		t.Run("node becomes a source", func(t *testing.T) {
+			expectVertexesExactly(t, CollectVertexes(gr.GetSources()), vertexId)
+		})
+
+		t.Run("node becomes a sink", func(t *testing.T) {
+			expectVertexesExactly(t, CollectVertexes(gr.GetSinks()), vertexId)
+		})
 	})
-	
-	c.Specify("New arrow in empty graph", func() {
+
+	t.Run("New arrow in empty graph", func(t *testing.T) {
+		gr := graphCreator()
 		vertexId := VertexId(1)
 		anotherVertexId := VertexId(2)
 		gr.AddArc(vertexId, anotherVertexId)
-		c.Expect(gr.CheckArc(vertexId, anotherVertexId), Equals, true)
+		expectTrue(t, gr.CheckArc(vertexId, anotherVertexId), "arc exists")
 
-		c.Specify("changing nodes count", func() {
-			c.Expect(gr.Order(), Equals, 2)
+		t.Run("changing nodes count", func(t *testing.T) {
+			expectEquals(t, gr.Order(), 2)
 		})
-		
-		c.Specify("changing arrows count", func() {
-			c.Expect(gr.ArcsCnt(), Equals, 1)
+
+		t.Run("changing arrows count", func(t *testing.T) {
+			expectEquals(t, gr.ArcsCnt(), 1)
 		})
-		
-		c.Specify("correct accessors in arrow start", func() {
-			c.Expect(CollectVertexes(gr.GetAccessors(vertexId)), ContainsExactly, Values(anotherVertexId))
+
+		t.Run("correct accessors in arrow start", func(t *testing.T) {
+			expectVertexesExactly(t, CollectVertexes(gr.GetAccessors(vertexId)), anotherVertexId)
 		})
 
-		c.Specify("correct predecessors in arrow start", func() {
-			c.Expect(len(CollectVertexes(gr.GetPredecessors(vertexId))), Equals, 0)
+		t.Run("correct predecessors in arrow start", func(t *testing.T) {
+			expectEquals(t, len(CollectVertexes(gr.GetPredecessors(vertexId))), 0)
 		})
 
-		c.Specify("correct accessors in arrow end", func() {
-			c.Expect(len(CollectVertexes(gr.GetAccessors(anotherVertexId))), Equals, 0)
+		t.Run("correct accessors in arrow end", func(t *testing.T) {
+			expectEquals(t, len(CollectVertexes(gr.GetAccessors(anotherVertexId))), 0)
 		})
 
-		c.Specify("correct predecessors in arrow end", func() {
-			c.Expect(CollectVertexes(gr.GetPredecessors(anotherVertexId)), ContainsExactly, Values(vertexId))
+		t.Run("correct predecessors in arrow end", func(t *testing.T) {
+			expectVertexesExactly(t, CollectVertexes(gr.GetPredecessors(anotherVertexId)), vertexId)
 		})
-		
-		c.Specify("arrow start becomes a source", func() {
-			c.Expect(CollectVertexes(gr.GetSources()), ContainsExactly, Values(vertexId))
+
+		t.Run("arrow start becomes a source", func(t *testing.T) {
+			expectVertexesExactly(t, CollectVertexes(gr.GetSources()), vertexId)
 		})
 
-		c.Specify("arrow end becomes a sink", func() {
-			c.Expect(CollectVertexes(gr.GetSinks()), ContainsExactly, Values(anotherVertexId))
+		t.Run("arrow end becomes a sink", func(t *testing.T) {
+			expectVertexesExactly(t, CollectVertexes(gr.GetSinks()), anotherVertexId)
 		})
 	})
-	
-	c.Specify("A bit more complex example", func() {
+
+	t.Run("A bit more complex example", func(t *testing.T) {
+		gr := graphCreator()
 		gr.AddArc(1, 2)
 		gr.AddArc(2, 3)
 		gr.AddArc(3, 1)
@@ -109,80 +105,182 @@ func DirectedGraphSpec(c gospec.Context, graphCreator func() DirectedGraph) {
 		gr.AddArc(4, 5)
 		gr.AddArc(6, 2)
 		gr.AddArc(1, 7)
-		
-		c.Specify("checking nodes count", func() {
-			c.Expect(gr.Order(), Equals, 7)
+
+		t.Run("checking nodes count", func(t *testing.T) {
+			expectEquals(t, gr.Order(), 7)
 		})
-		
-		c.Specify("checking arrows count", func() {
-			c.Expect(gr.ArcsCnt(), Equals, 7)
+
+		t.Run("checking arrows count", func(t *testing.T) {
+			expectEquals(t, gr.ArcsCnt(), 7)
 		})
-		
-		c.Specify("checking sources", func() {
+
+		t.Run("checking sources", func(t *testing.T) {
 			sources := CollectVertexes(gr.GetSources())
-			c.Expect(sources, ContainsExactly, Values(VertexId(4), VertexId(6)))
-			
-			c.Specify("every source hasn't any predecessors", func() {
+			expectVertexesExactly(t, sources, 4, 6)
+
+			t.Run("every source hasn't any predecessors", func(t *testing.T) {
 				for _, vertexId := range sources {
-					c.Expect(len(CollectVertexes(gr.GetPredecessors(vertexId))), Equals, 0)
+					expectEquals(t, len(CollectVertexes(gr.GetPredecessors(vertexId))), 0)
 				}
 			})
 		})
-		
-		c.Specify("checking sinks", func() {
+
+		t.Run("checking sinks", func(t *testing.T) {
 			sinks := CollectVertexes(gr.GetSinks())
-			c.Expect(sinks, ContainsExactly, Values(VertexId(5), VertexId(7)))
+			expectVertexesExactly(t, sinks, 5, 7)
 
-			c.Specify("every sink hasn't any accessors", func() {
+			t.Run("every sink hasn't any accessors", func(t *testing.T) {
 				for _, vertexId := range sinks {
-					c.Expect(len(CollectVertexes(gr.GetAccessors(vertexId))), Equals, 0)
+					expectEquals(t, len(CollectVertexes(gr.GetAccessors(vertexId))), 0)
 				}
 			})
 		})
-		
-		c.Specify("checking accessors in intermediate node", func() {
+
+		t.Run("checking accessors in intermediate node", func(t *testing.T) {
 			accessors := CollectVertexes(gr.GetAccessors(1))
-			c.Expect(accessors, ContainsExactly, Values(VertexId(2), VertexId(5), VertexId(7)))
-			
-			c.Specify("every accessor has this node in predecessors", func() {
+			expectVertexesExactly(t, accessors, 2, 5, 7)
+
+			t.Run("every accessor has this node in predecessors", func(t *testing.T) {
 				for _, vertexId := range accessors {
-					c.Expect(CollectVertexes(gr.GetPredecessors(vertexId)), Contains, VertexId(1))
+					expectContains(t, CollectVertexes(gr.GetPredecessors(vertexId)), 1)
 				}
 			})
 		})
 
-		c.Specify("checking predecessors in intermediate node", func() {
+		t.Run("checking predecessors in intermediate node", func(t *testing.T) {
 			predecessors := CollectVertexes(gr.GetPredecessors(5))
-			c.Expect(predecessors, ContainsExactly, Values(VertexId(1), VertexId(4)))
-			
-			c.Specify("every predecessor has this node in accessors", func() {
+			expectVertexesExactly(t, predecessors, 1, 4)
+
+			t.Run("every predecessor has this node in accessors", func(t *testing.T) {
 				for _, vertexId := range predecessors {
-					c.Expect(CollectVertexes(gr.GetAccessors(vertexId)), Contains, VertexId(5))
+					expectContains(t, CollectVertexes(gr.GetAccessors(vertexId)), 5)
 				}
 			})
 		})
-
 	})
 }
 
 func TestDirectedGraphSpec(t *testing.T) {
-	r := gospec.NewRunner()
+	t.Run("DirectedGraph(DirectedMap)", func(t *testing.T) {
+		DirectedGraphSpec(t, func() DirectedGraph {
+			return DirectedGraph(NewDirectedMap())
+		})
+	})
+	t.Run("DirectedGraph(MixedMatrix)", func(t *testing.T) {
+		DirectedGraphSpec(t, func() DirectedGraph {
+			return DirectedGraph(NewMixedMatrix(10))
+		})
+	})
+	t.Run("DirectedGraph(MixedMap)", func(t *testing.T) {
+		DirectedGraphSpec(t, func() DirectedGraph {
+			return DirectedGraph(NewMixedMap())
+		})
+	})
+	t.Run("DirectedGraph(DirectedMatrix)", func(t *testing.T) {
+		DirectedGraphSpec(t, func() DirectedGraph {
+			return DirectedGraph(NewDirectedMatrix(10))
+		})
+	})
+	t.Run("DirectedGraph(DirectedMatrixBitPacked)", func(t *testing.T) {
+		DirectedGraphSpec(t, func() DirectedGraph {
+			return DirectedGraph(NewDirectedMatrixBitPacked(10))
+		})
+	})
+	t.Run("DirectedGraph(DirectedMultiMap)", func(t *testing.T) {
+		DirectedGraphSpec(t, func() DirectedGraph {
+			return DirectedGraph(NewDirectedMultiMap())
+		})
+	})
+}
+
+func TestDirectedMatrix(t *testing.T) {
+	creators := map[string]func(size int) *DirectedMatrix{
+		"bytes": NewDirectedMatrix,
+		"bits": NewDirectedMatrixBitPacked,
+	}
+	for name, creator := range creators {
+		t.Run(name, func(t *testing.T) {
+			t.Run("arcs in both directions", func(t *testing.T) {
+				gr := creator(3)
+				gr.AddArc(1, 2)
+				gr.AddArc(2, 1)
+				expectEquals(t, gr.ArcsCnt(), 2)
+				expectTrue(t, gr.CheckArc(1, 2), "arc 1->2")
+				expectTrue(t, gr.CheckArc(2, 1), "arc 2->1")
+				gr.RemoveArc(1, 2)
+				expectFalse(t, gr.CheckArc(1, 2), "arc 1->2")
+				expectTrue(t, gr.CheckArc(2, 1), "arc 2->1")
+			})
 
-	// paramenerized test creator
-	cr := func(graphCreator func() DirectedGraph) func (c gospec.Context) {
-		return func(c gospec.Context){
-			DirectedGraphSpec(c, graphCreator)
-		}
+			t.Run("loops", func(t *testing.T) {
+				gr := creator(3)
+				gr.AddArc(1, 1)
+				gr.AddArc(1, 2)
+				expectTrue(t, gr.CheckArc(1, 1), "loop")
+				expectVertexesExactly(t, CollectVertexes(gr.GetAccessors(1)), 1, 2)
+				gr.RemoveNode(1)
+				expectEquals(t, gr.ArcsCnt(), 0)
+			})
+
+			t.Run("growing", func(t *testing.T) {
+				gr := creator(2)
+				gr.AddArc(1, 2)
+				gr.AddArc(2, 1)
+				expectErrorIs(t, gr.TryAddArc(1, 3), nil)
+				expectErrorIs(t, gr.TryAddNode(4), nil)
+				expectErrorIs(t, gr.TryAddArc(1, 2), ErrDuplicateConnection)
+				expectEquals(t, gr.Order(), 4)
+				expectEquals(t, gr.GetCapacity(), 4)
+				expectTrue(t, gr.CheckArc(1, 2), "arc 1->2")
+				expectTrue(t, gr.CheckArc(2, 1), "arc 2->1")
+				expectTrue(t, gr.CheckArc(1, 3), "arc 1->3")
+				expectEquals(t, gr.ArcsCnt(), 3)
+			})
+
+			t.Run("compact", func(t *testing.T) {
+				gr := creator(0)
+				for i:=0; i<10; i++ {
+					gr.AddArc(VertexId(i), VertexId((i+1)%10))
+				}
+				gr.AddArc(3, 3)
+				for i:=0; i<10; i+=2 {
+					gr.RemoveNode(VertexId(i))
+				}
+				gr.Compact()
+				expectEquals(t, gr.GetCapacity(), 5)
+				expectEquals(t, gr.ArcsCnt(), 1)
+				expectTrue(t, gr.CheckArc(3, 3), "loop")
+				expectVertexesExactly(t, CollectVertexes(gr), 1, 3, 5, 7, 9)
+				gr.AddArc(1, 3)
+				expectVertexesExactly(t, CollectVertexes(gr.GetAccessors(1)), 3)
+			})
+
+			t.Run("removed node place is reused", func(t *testing.T) {
+				gr := creator(3)
+				gr.AddArc(1, 2)
+				gr.AddArc(2, 3)
+				gr.AddArc(3, 1)
+				gr.RemoveNode(2)
+				expectEquals(t, gr.ArcsCnt(), 1)
+				gr.AddArc(4, 1)
+				expectEquals(t, gr.Order(), 3)
+				expectVertexesExactly(t, CollectVertexes(gr.GetPredecessors(1)), 3, 4)
+				expectVertexesExactly(t, CollectVertexes(gr.GetAccessors(4)), 1)
+				expectVertexesExactly(t, CollectVertexes(gr.GetSources()), 3, 4)
+				expectErrorIs(t, gr.TryRemoveNode(2), ErrNodeNotFound)
+			})
+
+			t.Run("big matrix", func(t *testing.T) {
+				gr := creator(100)
+				for i:=0; i<100; i++ {
+					gr.AddArc(VertexId(i), VertexId((i+1)%100))
+				}
+				expectEquals(t, gr.ArcsCnt(), 100)
+				for i:=0; i<100; i++ {
+					expectTrue(t, gr.CheckArc(VertexId(i), VertexId((i+1)%100)), "arc in cycle")
+					expectFalse(t, gr.CheckArc(VertexId((i+1)%100), VertexId(i)), "reversed arc in cycle")
+				}
+			})
+		})
 	}
-	
-	r.AddNamedSpec("DirectedGraph(DirectedMap)", cr(func() DirectedGraph {
-		return DirectedGraph(NewDirectedMap())
-	}))
-	r.AddNamedSpec("DirectedGraph(MixedMatrix)", cr(func() DirectedGraph {
-		return DirectedGraph(NewMixedMatrix(10))
-	}))
-	r.AddNamedSpec("DirectedGraph(MixedMap)", cr(func() DirectedGraph {
-		return DirectedGraph(NewMixedMap())
-	}))
-	gospec.MainGoTest(r, t)
 }
@@ -1,7 +1,9 @@
 package graph
 
 import (
-	"github.com/StepLg/go-erx/src/erx"
+	"iter"
+
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 type DirectedMap struct {
@@ -25,25 +27,34 @@ func (g *DirectedMap) ConnectionsIter() <-chan Connection {
 	return g.ArcsIter()
 }
 
+func (g *DirectedMap) ConnectionsSeq() iter.Seq[Connection] {
+	return g.ArcsSeq()
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // VertexesIterable
 
 func (g *DirectedMap) VertexesIter() <-chan VertexId {
-	ch := make(chan VertexId)
-	go func() {
+	return seqToChan(g.VertexesSeq())
+}
+
+func (g *DirectedMap) VertexesSeq() iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
 		for from, _ := range g.directArcs {
-			ch <- from
+			if !yield(from) {
+				return
+			}
 		}
 		
 		for to, _ := range g.reversedArcs {
 			// need to prevent duplicating node ids
 			if _, ok := g.directArcs[to]; !ok {
-				ch <- to
+				if !yield(to) {
+					return
+				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -59,47 +70,62 @@ func (g *DirectedMap) CheckNode(node VertexId) (exists bool) {
 
 // Adding single node to graph
 func (g *DirectedMap) AddNode(node VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Add node to graph.", err, 1)
-		res.AddV("node id", node)
-		return
+	if err := g.TryAddNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Add node to graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
 	}
+}
 
+// Adding single node to graph
+//
+// Returns ErrNodeExists if node is already in graph.
+func (g *DirectedMap) TryAddNode(node VertexId) error {
 	if _, ok := g.directArcs[node]; ok {
-		panic(makeError(erx.NewError("Node already exists.")))
+		return &VertexError{Op: "add node", Node: node, Err: ErrNodeExists}
 	}
 	
 	g.directArcs[node] = make(map[VertexId]bool)
 	g.reversedArcs[node] = make(map[VertexId]bool)
 
-	return	
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // GraphVertexesRemover
 
 func (g *DirectedMap) RemoveNode(node VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Remove node from graph.", err, 1)
-		res.AddV("node id", node)
-		return
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
 	}
+}
 
-	_, okDirect := g.directArcs[node]
-	_, okReversed := g.reversedArcs[node]
+// Removing node with all it's arcs from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *DirectedMap) TryRemoveNode(node VertexId) error {
+	accessors, okDirect := g.directArcs[node]
+	predecessors, okReversed := g.reversedArcs[node]
 	if !okDirect && !okReversed {
-		panic(makeError(erx.NewError("Node doesn't exist.")))
+		return &VertexError{Op: "remove node", Node: node, Err: ErrNodeNotFound}
 	}
 	
-	g.directArcs[node] = nil, false
-	g.reversedArcs[node] = nil, false
+	g.arcsCnt -= len(accessors) + len(predecessors)
+	if _, isLoop := accessors[node]; isLoop {
+		// loop was counted twice
+		g.arcsCnt++
+	}
+	delete(g.directArcs, node)
+	delete(g.reversedArcs, node)
 	for _, connectedVertexes := range g.directArcs {
-		connectedVertexes[node] = false, false
+		delete(connectedVertexes, node)
 	}
 	for _, connectedVertexes := range g.reversedArcs {
-		connectedVertexes[node] = false, false
+		delete(connectedVertexes, node)
 	}
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -114,24 +140,30 @@ func (g *DirectedMap) touchNode(node VertexId) {
 
 // Adding arrow to graph.
 func (g *DirectedMap) AddArc(from, to VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Add arc to graph.", err, 1)
-		res.AddV("tail", from)
-		res.AddV("head", to)
-		return
+	if err := g.TryAddArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Add arc to graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
 	}
+}
 
-	g.touchNode(from)
-	g.touchNode(to)
-	
+// Adding arrow to graph.
+//
+// Nodes are created if they don't exist. Returns ErrDuplicateConnection if
+// arc already exists.
+func (g *DirectedMap) TryAddArc(from, to VertexId) error {
 	if direction, ok := g.directArcs[from][to]; ok && direction {
-		panic(makeError(erx.NewError("Duplicate arrow.")))
+		return &ConnectionError{Op: "add arc", Tail: from, Head: to, Err: ErrDuplicateConnection}
 	}
 	
+	g.touchNode(from)
+	g.touchNode(to)
+	
 	g.directArcs[from][to] = true
 	g.reversedArcs[to][from] = true
 	g.arcsCnt++
-	return	
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -139,27 +171,37 @@ func (g *DirectedMap) AddArc(from, to VertexId) {
 
 // Removing arrow  'from' and 'to' nodes
 func (g *DirectedMap) RemoveArc(from, to VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Remove arc from graph.", err, 1)
-		res.AddV("tail", from)
-		res.AddV("head", to)
-		return
+	if err := g.TryRemoveArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove arc from graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
 	}
+}
 
+// Removing arrow  'from' and 'to' nodes
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such arc.
+func (g *DirectedMap) TryRemoveArc(from, to VertexId) error {
 	connectedVertexes, ok := g.directArcs[from]
 	if !ok {
-		panic(makeError(erx.NewError("Tail node doesn't exist.")))
+		return &VertexError{Op: "remove arc", Node: from, Err: ErrNodeNotFound}
+	}
+	
+	if _, ok = g.directArcs[to]; !ok {
+		return &VertexError{Op: "remove arc", Node: to, Err: ErrNodeNotFound}
 	}
 	
-	if _, ok = connectedVertexes[to]; ok {
-		panic(makeError(erx.NewError("Head node doesn't exist.")))
+	if _, ok = connectedVertexes[to]; !ok {
+		return &ConnectionError{Op: "remove arc", Tail: from, Head: to, Err: ErrConnectionNotFound}
 	}
 	
-	g.directArcs[from][to] = false, false
-	g.reversedArcs[to][from] = false, false
+	delete(g.directArcs[from], to)
+	delete(g.reversedArcs[to], from)
 	g.arcsCnt--
 	
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -175,98 +217,72 @@ func (g *DirectedMap) ArcsCnt() int {
 
 // Getting all graph sources.
 func (g *DirectedMap) GetSources() VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		go func() {
-			for VertexId, predecessors := range g.reversedArcs {
-				if len(predecessors)==0 {
-					ch <- VertexId
+	iterator := func(yield func(VertexId) bool) {
+		for VertexId, predecessors := range g.reversedArcs {
+			if len(predecessors)==0 {
+				if !yield(VertexId) {
+					return
 				}
 			}
-			close(ch)
-		}()
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Getting all graph sinks.
 func (g *DirectedMap) GetSinks() VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		go func() {
-			for VertexId, accessors := range g.directArcs {
-				if len(accessors)==0 {
-					ch <- VertexId
+	iterator := func(yield func(VertexId) bool) {
+		for VertexId, accessors := range g.directArcs {
+			if len(accessors)==0 {
+				if !yield(VertexId) {
+					return
 				}
 			}
-			close(ch)
-		}()
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Getting node accessors
 func (g *DirectedMap) GetAccessors(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
+	iterator := func(yield func(VertexId) bool) {
+		accessorsMap, ok := g.directArcs[node]
+		if !ok {
+			err := erx.NewSequent("Get node accessors in directed graph.", erx.NewError("Node doesn't exists."))
+			err.AddV("node", node)
+			panic(err)
+		}
 		
-		go func() {
-			defer func() {
-				if e := recover(); e!=nil {
-					err := erx.NewSequent("Get node accessors in mixed graph.", e)
-					err.AddV("node", node)
-					panic(err)
-				}
-			}()
-			accessorsMap, ok := g.directArcs[node]
-			if !ok {
-				panic(erx.NewError("Node doesn't exists."))
-			}
-			
-			for VertexId, _ := range accessorsMap {
-				ch <- VertexId
+		for VertexId, _ := range accessorsMap {
+			if !yield(VertexId) {
+				return
 			}
-			close(ch)
-		}()
-			
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Getting node predecessors
 func (g *DirectedMap) GetPredecessors(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
+	iterator := func(yield func(VertexId) bool) {
+		predecessorsMap, ok := g.reversedArcs[node]
+		if !ok {
+			err := erx.NewSequent("Get node predecessors in directed graph.", erx.NewError("Node doesn't exists."))
+			err.AddV("node", node)
+			panic(err)
+		}
 		
-		go func() {
-			defer func() {
-				if e := recover(); e!=nil {
-					err := erx.NewSequent("Get node accessors in mixed graph.", e)
-					err.AddV("node", node)
-					panic(err)
-				}
-			}()
-			accessorsMap, ok := g.reversedArcs[node]
-			if !ok {
-				panic(erx.NewError("Node doesn't exists."))
+		for VertexId, _ := range predecessorsMap {
+			if !yield(VertexId) {
+				return
 			}
-			
-			for VertexId, _ := range accessorsMap {
-				ch <- VertexId
-			}
-			close(ch)
-		}()
-			
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 func (g *DirectedMap) CheckArc(from, to VertexId) (isExist bool) {
@@ -292,14 +308,17 @@ func (g *DirectedMap) CheckArc(from, to VertexId) (isExist bool) {
 }
 
 func (g *DirectedMap) ArcsIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
+	return seqToChan(g.ArcsSeq())
+}
+
+func (g *DirectedMap) ArcsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
 		for from, connectedVertexes := range g.directArcs {
 			for to, _ := range connectedVertexes {
-				ch <- Connection{from, to}
+				if !yield(Connection{from, to}) {
+					return
+				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
@@ -0,0 +1,423 @@
+package graph
+
+import (
+	"iter"
+
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Arcs storage for DirectedMatrix: flags for size*size matrix cells.
+type matrixCells interface {
+	get(id int) bool
+	set(id int, value bool)
+	// Getting storage with at least cellsCnt cells, keeping existing values.
+	grow(cellsCnt int) matrixCells
+}
+
+// One byte per cell.
+type matrixCellsBytes []bool
+
+func (cells matrixCellsBytes) get(id int) bool {
+	return cells[id]
+}
+
+func (cells matrixCellsBytes) set(id int, value bool) {
+	cells[id] = value
+}
+
+func (cells matrixCellsBytes) grow(cellsCnt int) matrixCells {
+	return append(cells, make(matrixCellsBytes, cellsCnt-len(cells))...)
+}
+
+// One bit per cell.
+type matrixCellsBits []uint64
+
+func (cells matrixCellsBits) get(id int) bool {
+	return cells[id/64] & (1 << uint(id%64)) != 0
+}
+
+func (cells matrixCellsBits) set(id int, value bool) {
+	if value {
+		cells[id/64] |= 1 << uint(id%64)
+	} else {
+		cells[id/64] &^= 1 << uint(id%64)
+	}
+}
+
+func (cells matrixCellsBits) grow(cellsCnt int) matrixCells {
+	return append(cells, make(matrixCellsBits, (cellsCnt+63)/64-len(cells))...)
+}
+
+// Directed graph with matrix as a internal representation.
+//
+// Doesn't allow duplicate arcs, but allows loops and arcs in both directions
+// between two nodes. Matrix grows when there is no space for new vertex,
+// places of removed vertexes are reused. DirectedMatrix use over size^2 bytes,
+// or size^2/8 bytes if it's created with NewDirectedMatrixBitPacked, where
+// size is current capacity.
+type DirectedMatrix struct {
+	matrixIds
+	cells matrixCells
+	arcsCnt int
+}
+
+// Creating new directed graph with matrix storage.
+//
+// size means initial number of nodes, which graph can handle without
+// reallocation.
+func NewDirectedMatrix(size int) *DirectedMatrix {
+	if size<0 {
+		panic(erx.NewError("Trying to create directed matrix graph with negative size"))
+	}
+	return newDirectedMatrix(size, make(matrixCellsBytes, size*size))
+}
+
+// Creating new directed graph with bit-packed matrix storage.
+//
+// Uses 8 times less memory than NewDirectedMatrix, but a bit slower.
+func NewDirectedMatrixBitPacked(size int) *DirectedMatrix {
+	if size<0 {
+		panic(erx.NewError("Trying to create directed matrix graph with negative size"))
+	}
+	return newDirectedMatrix(size, make(matrixCellsBits, (size*size+63)/64))
+}
+
+func newDirectedMatrix(size int, cells matrixCells) *DirectedMatrix {
+	g := new(DirectedMatrix)
+	g.matrixIds = newMatrixIds(size)
+	g.cells = cells
+	return g
+}
+
+// Current graph capacity
+//
+// Maximum nodes count graph can handle without reallocation
+func (g *DirectedMatrix) GetCapacity() int {
+	return g.size
+}
+
+// Shrinking matrix to current nodes count.
+//
+// Internal ids of nodes are changed, so VertexIds shouldn't be used across
+// Compact call.
+func (g *DirectedMatrix) Compact() {
+	oldCells := g.cells
+	mapping := g.compactIds()
+	switch oldCells.(type) {
+		case matrixCellsBits:
+			g.cells = make(matrixCellsBits, (g.size*g.size+63)/64)
+		default:
+			g.cells = make(matrixCellsBytes, g.size*g.size)
+	}
+	for oldFrom, newFrom := range mapping {
+		for oldTo, newTo := range mapping {
+			if oldCells.get(matrixSquareIndex(oldFrom, oldTo)) {
+				g.cells.set(matrixSquareIndex(newFrom, newTo), true)
+			}
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// ConnectionsIterable
+
+func (g *DirectedMatrix) ConnectionsIter() <-chan Connection {
+	return g.ArcsIter()
+}
+
+func (g *DirectedMatrix) ConnectionsSeq() iter.Seq[Connection] {
+	return g.ArcsSeq()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// VertexesIterable
+
+func (g *DirectedMatrix) VertexesIter() <-chan VertexId {
+	return seqToChan(g.VertexesSeq())
+}
+
+func (g *DirectedMatrix) VertexesSeq() iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
+		for node, _ := range g.VertexIds {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// VertexesChecker
+
+func (g *DirectedMatrix) CheckNode(node VertexId) (exists bool) {
+	_, exists = g.VertexIds[node]
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesWriter
+
+// Adding single node to graph
+func (g *DirectedMatrix) AddNode(node VertexId) {
+	if err := g.TryAddNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Add node to graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Adding single node to graph
+//
+// Returns ErrNodeExists if node is already in graph.
+func (g *DirectedMatrix) TryAddNode(node VertexId) error {
+	if _, ok := g.VertexIds[node]; ok {
+		return &VertexError{Op: "add node", Node: node, Err: ErrNodeExists}
+	}
+
+	g.touchNode(node)
+	return nil
+}
+
+// Getting internal node id, creating node if it doesn't exist.
+func (g *DirectedMatrix) touchNode(node VertexId) int {
+	id, grown := g.allocId(node)
+	if grown {
+		g.cells = g.cells.grow(g.size*g.size)
+	}
+	return id
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesRemover
+
+// Removing node with all it's arcs from graph
+func (g *DirectedMatrix) RemoveNode(node VertexId) {
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Removing node with all it's arcs from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *DirectedMatrix) TryRemoveNode(node VertexId) error {
+	id, ok := g.VertexIds[node]
+	if !ok {
+		return &VertexError{Op: "remove node", Node: node, Err: ErrNodeNotFound}
+	}
+
+	for _, otherId := range g.VertexIds {
+		if g.cells.get(matrixSquareIndex(id, otherId)) {
+			g.cells.set(matrixSquareIndex(id, otherId), false)
+			g.arcsCnt--
+		}
+		if g.cells.get(matrixSquareIndex(otherId, id)) {
+			g.cells.set(matrixSquareIndex(otherId, id), false)
+			g.arcsCnt--
+		}
+	}
+	g.releaseId(node)
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DirectedGraphArcsWriter
+
+// Adding arrow to graph.
+func (g *DirectedMatrix) AddArc(from, to VertexId) {
+	if err := g.TryAddArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Add arc to graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+}
+
+// Adding arrow to graph.
+//
+// Nodes are created if they don't exist. Returns ErrDuplicateConnection if
+// arc already exists.
+func (g *DirectedMatrix) TryAddArc(from, to VertexId) error {
+	conn := matrixSquareIndex(g.touchNode(from), g.touchNode(to))
+	if g.cells.get(conn) {
+		return &ConnectionError{Op: "add arc", Tail: from, Head: to, Err: ErrDuplicateConnection}
+	}
+	g.cells.set(conn, true)
+	g.arcsCnt++
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DirectedGraphArcsRemover
+
+// Removing arrow  'from' and 'to' nodes
+func (g *DirectedMatrix) RemoveArc(from, to VertexId) {
+	if err := g.TryRemoveArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove arc from graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+}
+
+// Removing arrow  'from' and 'to' nodes
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such arc.
+func (g *DirectedMatrix) TryRemoveArc(from, to VertexId) error {
+	conn, err := g.tryGetConnectionId("remove arc", from, to)
+	if err!=nil {
+		return err
+	}
+
+	if !g.cells.get(conn) {
+		return &ConnectionError{Op: "remove arc", Tail: from, Head: to, Err: ErrConnectionNotFound}
+	}
+	g.cells.set(conn, false)
+	g.arcsCnt--
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DirectedGraphReader
+
+// Getting nodes count in graph
+func (g *DirectedMatrix) Order() int {
+	return len(g.VertexIds)
+}
+
+// Getting arcs count in graph
+func (g *DirectedMatrix) ArcsCnt() int {
+	return g.arcsCnt
+}
+
+// Getting all graph sources.
+func (g *DirectedMatrix) GetSources() VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for node, id := range g.VertexIds {
+			if !g.hasConnectedNode(id, false) {
+				if !yield(node) {
+					return
+				}
+			}
+		}
+	}
+
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Getting all graph sinks.
+func (g *DirectedMatrix) GetSinks() VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for node, id := range g.VertexIds {
+			if !g.hasConnectedNode(id, true) {
+				if !yield(node) {
+					return
+				}
+			}
+		}
+	}
+
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Getting node accessors
+func (g *DirectedMatrix) GetAccessors(node VertexId) VertexesIterable {
+	return g.connectedVertexes(node, true, "Get node accessors in directed graph.")
+}
+
+// Getting node predecessors
+func (g *DirectedMatrix) GetPredecessors(node VertexId) VertexesIterable {
+	return g.connectedVertexes(node, false, "Get node predecessors in directed graph.")
+}
+
+// Checking arrow existance between node1 and node2
+//
+// node1 and node2 must exist in graph or error will be returned
+func (g *DirectedMatrix) CheckArc(from, to VertexId) bool {
+	conn, err := g.tryGetConnectionId("check arc", from, to)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Checking arc existance in graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+	return g.cells.get(conn)
+}
+
+func (g *DirectedMatrix) ArcsIter() <-chan Connection {
+	return seqToChan(g.ArcsSeq())
+}
+
+func (g *DirectedMatrix) ArcsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for from, fromId := range g.VertexIds {
+			for to, toId := range g.VertexIds {
+				if g.cells.get(matrixSquareIndex(fromId, toId)) {
+					if !yield(Connection{from, to}) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// Accessors (if direct is true) or predecessors of node.
+func (g *DirectedMatrix) connectedVertexes(node VertexId, direct bool, errorMsg string) VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		id, ok := g.VertexIds[node]
+		if !ok {
+			err := erx.NewSequent(errorMsg, erx.NewError("Node doesn't exists."))
+			err.AddV("node", node)
+			panic(err)
+		}
+
+		for otherNode, otherId := range g.VertexIds {
+			if g.cells.get(g.cellId(id, otherId, direct)) {
+				if !yield(otherNode) {
+					return
+				}
+			}
+		}
+	}
+
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Check if node with internal id has accessors (if direct is true) or
+// predecessors.
+func (g *DirectedMatrix) hasConnectedNode(id int, direct bool) bool {
+	for _, otherId := range g.VertexIds {
+		if g.cells.get(g.cellId(id, otherId, direct)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cell of arc from id to otherId if direct is true, or from otherId to id
+// otherwise.
+func (g *DirectedMatrix) cellId(id, otherId int, direct bool) int {
+	if direct {
+		return matrixSquareIndex(id, otherId)
+	}
+	return matrixSquareIndex(otherId, id)
+}
+
+// Calculating connection id for existing nodes.
+//
+// op is an operation name for returned errors.
+func (g *DirectedMatrix) tryGetConnectionId(op string, from, to VertexId) (int, error) {
+	fromId, ok := g.VertexIds[from]
+	if !ok {
+		return 0, &VertexError{Op: op, Node: from, Err: ErrNodeNotFound}
+	}
+	toId, ok := g.VertexIds[to]
+	if !ok {
+		return 0, &VertexError{Op: op, Node: to, Err: ErrNodeNotFound}
+	}
+	return matrixSquareIndex(fromId, toId), nil
+}
@@ -2,74 +2,143 @@ package graph
 
 import (
 	"testing"
-	"github.com/orfjackal/gospec/src/gospec"
-	. "github.com/orfjackal/gospec/src/gospec"
 )
 
-func MixedGraphSpec(c gospec.Context, graphCreator func() MixedGraph) {
-	gr := graphCreator()
-	c.Specify("After adding new edge", func() {
+func MixedGraphSpec(t *testing.T, graphCreator func() MixedGraph) {
+	t.Run("After adding new edge", func(t *testing.T) {
+		gr := graphCreator()
 		tail := VertexId(1)
 		head := VertexId(2)
 		gr.AddEdge(tail, head)
-		c.Specify("contain exactly two nodes", func() {
-			c.Expect(gr.Order(), Equals, 2)
+		t.Run("contain exactly two nodes", func(t *testing.T) {
+			expectEquals(t, gr.Order(), 2)
 		})
-		c.Specify("contain single edge", func() {
-			c.Expect(gr.EdgesCnt(), Equals, 1)
+		t.Run("contain single edge", func(t *testing.T) {
+			expectEquals(t, gr.EdgesCnt(), 1)
 		})
-		c.Specify("contain no arcs", func() {
-			c.Expect(gr.ArcsCnt(), Equals, 0)
+		t.Run("contain no arcs", func(t *testing.T) {
+			expectEquals(t, gr.ArcsCnt(), 0)
 		})
-		c.Specify("has one connection with type 'undirected'", func() {
-			c.Expect(gr.CheckEdge(tail, head), IsTrue)
-			c.Expect(gr.CheckEdge(head, tail), IsTrue)
-			c.Expect(gr.CheckArc(tail, head), IsFalse)
-			c.Expect(gr.CheckArc(head, tail), IsFalse)
-			c.Expect(gr.CheckEdgeType(tail, head), Equals, CT_UNDIRECTED)
-			c.Expect(gr.CheckEdgeType(head, tail), Equals, CT_UNDIRECTED)
+		t.Run("has one connection with type 'undirected'", func(t *testing.T) {
+			expectTrue(t, gr.CheckEdge(tail, head), "edge tail-head")
+			expectTrue(t, gr.CheckEdge(head, tail), "edge head-tail")
+			expectFalse(t, gr.CheckArc(tail, head), "arc tail->head")
+			expectFalse(t, gr.CheckArc(head, tail), "arc head->tail")
+			expectEquals(t, gr.CheckEdgeType(tail, head), CT_UNDIRECTED)
+			expectEquals(t, gr.CheckEdgeType(head, tail), CT_UNDIRECTED)
 		})
 	})
-	c.Specify("After adding new arc", func() {
+	t.Run("After adding new arc", func(t *testing.T) {
+		gr := graphCreator()
 		tail := VertexId(1)
 		head := VertexId(2)
 		gr.AddArc(tail, head)
-		c.Specify("contain exactly two nodes", func() {
-			c.Expect(gr.Order(), Equals, 2)
+		t.Run("contain exactly two nodes", func(t *testing.T) {
+			expectEquals(t, gr.Order(), 2)
 		})
-		c.Specify("contain no edges", func() {
-			c.Expect(gr.EdgesCnt(), Equals, 0)
+		t.Run("contain no edges", func(t *testing.T) {
+			expectEquals(t, gr.EdgesCnt(), 0)
 		})
-		c.Specify("contain single arc", func() {
-			c.Expect(gr.ArcsCnt(), Equals, 1)
+		t.Run("contain single arc", func(t *testing.T) {
+			expectEquals(t, gr.ArcsCnt(), 1)
 		})
-		c.Specify("has one connection with type 'directed'", func() {
-			c.Expect(gr.CheckEdge(tail, head), IsFalse)
-			c.Expect(gr.CheckEdge(head, tail), IsFalse)
-			c.Expect(gr.CheckArc(tail, head), IsTrue)
-			c.Expect(gr.CheckArc(head, tail), IsFalse)
-			c.Expect(gr.CheckEdgeType(tail, head), Equals, CT_DIRECTED)
-			c.Expect(gr.CheckEdgeType(head, tail), Equals, CT_DIRECTED_REVERSED)
+		t.Run("has one connection with type 'directed'", func(t *testing.T) {
+			expectFalse(t, gr.CheckEdge(tail, head), "edge tail-head")
+			expectFalse(t, gr.CheckEdge(head, tail), "edge head-tail")
+			expectTrue(t, gr.CheckArc(tail, head), "arc tail->head")
+			expectFalse(t, gr.CheckArc(head, tail), "arc head->tail")
+			expectEquals(t, gr.CheckEdgeType(tail, head), CT_DIRECTED)
+			expectEquals(t, gr.CheckEdgeType(head, tail), CT_DIRECTED_REVERSED)
 		})
 	})
 }
 
 func TestMixedGraphSpec(t *testing.T) {
-	r := gospec.NewRunner()
-	
-	// paramenerized test creator
-	cr := func(graphCreator func() MixedGraph) func (c gospec.Context) {
-		return func(c gospec.Context){
-			MixedGraphSpec(c, graphCreator)
+	t.Run("MixedGraph(MixedMap)", func(t *testing.T) {
+		MixedGraphSpec(t, func() MixedGraph {
+			return MixedGraph(NewMixedMap())
+		})
+	})
+	t.Run("MixedGraph(MixedMatrix)", func(t *testing.T) {
+		MixedGraphSpec(t, func() MixedGraph {
+			return MixedGraph(NewMixedMatrix(10))
+		})
+	})
+	t.Run("MixedGraph(MixedMultiMap)", func(t *testing.T) {
+		MixedGraphSpec(t, func() MixedGraph {
+			return MixedGraph(NewMixedMultiMap())
+		})
+	})
+}
+
+func TestMixedMatrix(t *testing.T) {
+	t.Run("growing", func(t *testing.T) {
+		gr := NewMixedMatrix(1)
+		gr.AddArc(1, 2)
+		gr.AddEdge(2, 3)
+		gr.AddArc(4, 1)
+		expectEquals(t, gr.Order(), 4)
+		expectEquals(t, gr.GetCapacity(), 4)
+		expectTrue(t, gr.CheckArc(1, 2), "arc 1->2")
+		expectTrue(t, gr.CheckEdge(3, 2), "edge 3-2")
+		expectTrue(t, gr.CheckArc(4, 1), "arc 4->1")
+	})
+
+	t.Run("remove node", func(t *testing.T) {
+		gr := NewMixedMatrix(3)
+		gr.AddArc(1, 2)
+		gr.AddEdge(2, 3)
+		gr.AddArc(3, 1)
+		gr.RemoveNode(2)
+		expectEquals(t, gr.Order(), 2)
+		expectEquals(t, gr.ArcsCnt(), 1)
+		expectEquals(t, gr.EdgesCnt(), 0)
+		expectErrorIs(t, gr.TryRemoveNode(2), ErrNodeNotFound)
+
+		// place of removed node is reused and has no connections
+		gr.AddNode(4)
+		expectEquals(t, gr.GetCapacity(), 3)
+		expectEquals(t, len(CollectVertexes(gr.GetAccessors(4))), 0)
+		expectEquals(t, len(CollectVertexes(gr.GetNeighbours(4))), 0)
+		expectEquals(t, len(CollectVertexes(gr.GetPredecessors(4))), 0)
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		gr := NewMixedMatrix(0)
+		for i:=0; i<10; i++ {
+			if i%3==0 {
+				gr.AddEdge(VertexId(i), VertexId(i+1))
+			} else {
+				gr.AddArc(VertexId(i+1), VertexId(i))
+			}
+		}
+		for i:=0; i<5; i++ {
+			gr.RemoveNode(VertexId(i))
 		}
+		expectEquals(t, gr.GetCapacity(), 16)
+		grcopy := NewMixedMap()
+		CopyMixedGraph(gr, grcopy)
+		gr.Compact()
+		expectEquals(t, gr.GetCapacity(), 6)
+		expectTrue(t, MixedGraphsEquals(gr, grcopy), "graph is the same after compact")
+	})
+}
+
+func TestUndirectedMatrix(t *testing.T) {
+	gr := NewUndirectedMatrix(0)
+	for i:=0; i<10; i++ {
+		gr.AddEdge(VertexId(i), VertexId(i+1))
 	}
-	
-	r.AddNamedSpec("MixedGraph(MixedMap)", cr(func() MixedGraph {
-		return MixedGraph(NewMixedMap())
-	}))
-	r.AddNamedSpec("MixedGraph(MixedMatrix)", cr(func() MixedGraph {
-		return MixedGraph(NewMixedMatrix(10))
-	}))
-	
-	gospec.MainGoTest(r, t)
+	expectEquals(t, gr.EdgesCnt(), 10)
+	gr.RemoveNode(5)
+	expectEquals(t, gr.EdgesCnt(), 8)
+	expectFalse(t, gr.CheckNode(5), "removed node exists")
+	gr.AddEdge(11, 4)
+	expectVertexesExactly(t, CollectVertexes(gr.GetNeighbours(11)), 4)
+
+	grcopy := NewUndirectedMap()
+	CopyUndirectedGraph(gr, grcopy)
+	gr.Compact()
+	expectEquals(t, gr.GetCapacity(), 11)
+	expectTrue(t, UndirectedGraphsEquals(gr, grcopy), "graph is the same after compact")
 }
@@ -1,7 +1,9 @@
 package graph
 
 import (
-	"github.com/StepLg/go-erx/src/erx"
+	"iter"
+
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 // Mixed graph with map as a internal representation.
@@ -26,23 +28,34 @@ func NewMixedMap() *MixedMap {
 // ConnectionsIterable
 
 func (g *MixedMap) ConnectionsIter() <-chan Connection {
-	ch := make(chan Connection)
-	panic(erx.NewError("Function doesn't implemented yet"))
-	return ch
+	return seqToChan(g.ConnectionsSeq())
+}
+
+func (g *MixedMap) ConnectionsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for conn := range g.TypedConnectionsSeq() {
+			if !yield(conn.Connection) {
+				return
+			}
+		}
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // VertexesIterable
 
 func (g *MixedMap) VertexesIter() <-chan VertexId {
-	ch := make(chan VertexId)
-	go func() {
+	return seqToChan(g.VertexesSeq())
+}
+
+func (g *MixedMap) VertexesSeq() iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
 		for from, _ := range g.connections {
-			ch <- from
+			if !yield(from) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -58,45 +71,58 @@ func (g *MixedMap) CheckNode(node VertexId) (exists bool) {
 
 // Adding single node to graph
 func (g *MixedMap) AddNode(node VertexId) {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Add node to graph.", e)
-			err.AddV("node id", node)
-			panic(err)
-		}
-	}()
+	if err := g.TryAddNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Add node to graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
 
+// Adding single node to graph
+//
+// Returns ErrNodeExists if node is already in graph.
+func (g *MixedMap) TryAddNode(node VertexId) error {
 	if _, ok := g.connections[node]; ok {
-		panic(erx.NewError("Node already exists."))
+		return &VertexError{Op: "add node", Node: node, Err: ErrNodeExists}
 	}
 	
 	g.connections[node] = make(map[VertexId]MixedConnectionType)
 
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // GraphVertexesRemover
 
 func (g *MixedMap) RemoveNode(node VertexId) {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Remove node from graph.", e)
-			err.AddV("node id", node)
-			panic(err)
-		}
-	}()
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
 
-	_, ok := g.connections[node]
+// Removing node with all it's connections from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *MixedMap) TryRemoveNode(node VertexId) error {
+	connectedVertexes, ok := g.connections[node]
 	if !ok {
-		panic(erx.NewError("Node doesn't exist."))
+		return &VertexError{Op: "remove node", Node: node, Err: ErrNodeNotFound}
 	}
 	
-	g.connections[node] = nil, false
+	for _, connType := range connectedVertexes {
+		if connType==CT_UNDIRECTED {
+			g.edgesCnt--
+		} else {
+			g.arcsCnt--
+		}
+	}
+	delete(g.connections, node)
 	for _, connectedVertexes := range g.connections {
-		connectedVertexes[node] = CT_NONE, false
+		delete(connectedVertexes, node)
 	}
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -110,28 +136,34 @@ func (g *MixedMap) touchNode(node VertexId) {
 
 // Adding arrow to graph.
 func (g *MixedMap) AddArc(from, to VertexId) {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Add arc to graph.", e)
-			err.AddV("tail", from)
-			err.AddV("head", to)
-			panic(err)
-		}
-	}()
+	if err := g.TryAddArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Add arc to graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+}
 
-	g.touchNode(from)
-	g.touchNode(to)
+// Adding arrow to graph.
+//
+// Nodes are created if they don't exist. Returns ErrLoop if from==to and
+// ErrDuplicateConnection if nodes are already connected.
+func (g *MixedMap) TryAddArc(from, to VertexId) error {
+	if from==to {
+		return &ConnectionError{Op: "add arc", Tail: from, Head: to, Err: ErrLoop}
+	}
 	
-	if direction, ok := g.connections[from][to]; ok {
-		err := erx.NewError("Duplicate connection.")
-		err.AddV("connection type", direction)
-		panic(err)
+	if _, ok := g.connections[from][to]; ok {
+		return &ConnectionError{Op: "add arc", Tail: from, Head: to, Err: ErrDuplicateConnection}
 	}
 	
+	g.touchNode(from)
+	g.touchNode(to)
+	
 	g.connections[from][to] = CT_DIRECTED
 	g.connections[to][from] = CT_DIRECTED_REVERSED
 	g.arcsCnt++
-	return	
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -139,32 +171,36 @@ func (g *MixedMap) AddArc(from, to VertexId) {
 
 // Removing arrow  'from' and 'to' nodes
 func (g *MixedMap) RemoveArc(from, to VertexId) {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Remove arc from graph.", e)
-			err.AddV("tail", from)
-			err.AddV("head", to)
-			panic(err)
-		}
-	}()
+	if err := g.TryRemoveArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove arc from graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+}
 
-	if _, ok := g.connections[from]; ok {
-		panic(erx.NewError("Tail node doesn't exist."))
+// Removing arrow  'from' and 'to' nodes
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such arc.
+func (g *MixedMap) TryRemoveArc(from, to VertexId) error {
+	if _, ok := g.connections[from]; !ok {
+		return &VertexError{Op: "remove arc", Node: from, Err: ErrNodeNotFound}
 	}
 	
-	if _, ok := g.connections[to]; ok {
-		panic(erx.NewError("Head node doesn't exist."))
+	if _, ok := g.connections[to]; !ok {
+		return &VertexError{Op: "remove arc", Node: to, Err: ErrNodeNotFound}
 	}
 	
 	if dir, ok := g.connections[from][to]; !ok || dir!=CT_DIRECTED {
-		panic(erx.NewError("Arc doesn't exist."))
+		return &ConnectionError{Op: "remove arc", Tail: from, Head: to, Err: ErrConnectionNotFound}
 	}
 	
-	g.connections[from][to] = CT_NONE, false
-	g.connections[to][from] = CT_NONE, false
+	delete(g.connections[from], to)
+	delete(g.connections[to], from)
 	g.arcsCnt--
 	
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -180,126 +216,78 @@ func (g *MixedMap) ArcsCnt() int {
 
 // Getting all graph sources.
 func (g *MixedMap) GetSources() VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		
-		go func() {
-			for VertexId, connections := range g.connections {
-				isSource := true
-				for _, connType := range connections {
-					if connType==CT_DIRECTED_REVERSED {
-						isSource = false
-						break
-					}
+	iterator := func(yield func(VertexId) bool) {
+		for VertexId, connections := range g.connections {
+			isSource := true
+			for _, connType := range connections {
+				if connType==CT_DIRECTED_REVERSED {
+					isSource = false
+					break
 				}
-				if isSource {
-					ch <- VertexId
+			}
+			if isSource {
+				if !yield(VertexId) {
+					return
 				}
 			}
-
-			close(ch)
-		}()
-		
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Getting all graph sinks.
 func (g *MixedMap) GetSinks() VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		
-		go func() {
-			for VertexId, connections := range g.connections {
-				isSink := true
-				for _, connType := range connections {
-					if connType==CT_DIRECTED {
-						isSink = false
-						break
-					}
+	iterator := func(yield func(VertexId) bool) {
+		for VertexId, connections := range g.connections {
+			isSink := true
+			for _, connType := range connections {
+				if connType==CT_DIRECTED {
+					isSink = false
+					break
 				}
-				if isSink {
-					ch <- VertexId
+			}
+			if isSink {
+				if !yield(VertexId) {
+					return
 				}
 			}
-
-			close(ch)
-		}()
-		
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
-// Getting node accessors
-func (g *MixedMap) GetAccessors(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
+// Iterate over node connections of given type.
+func (g *MixedMap) connectedVertexesSeq(node VertexId, connType MixedConnectionType, errorMsg string) iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
+		connectedMap, ok := g.connections[node]
+		if !ok {
+			err := erx.NewSequent(errorMsg, erx.NewError("Node doesn't exists."))
+			err.AddV("node id", node)
+			panic(err)
+		}
 		
-		go func() {
-			defer func() {
-				if e := recover(); e!=nil {
-					err := erx.NewSequent("Getting node accessors.", e)
-					err.AddV("node id", node)
-					panic(err)
+		for VertexId, curConnType := range connectedMap {
+			if curConnType==connType {
+				if !yield(VertexId) {
+					return
 				}
-			}()
-		
-			accessorsMap, ok := g.connections[node]
-			if !ok {
-				panic(erx.NewError("Node doesn't exists."))
 			}
-			
-			for VertexId, connType := range accessorsMap {
-				if connType==CT_DIRECTED {
-					ch <- VertexId
-				}
-			}
-			
-			close(ch)
-		}()
-		
-		return ch
+		}
 	}
-	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+}
+
+// Getting node accessors
+func (g *MixedMap) GetAccessors(node VertexId) VertexesIterable {
+	iterator := g.connectedVertexesSeq(node, CT_DIRECTED, "Getting node accessors.")
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Getting node predecessors
 func (g *MixedMap) GetPredecessors(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		
-		go func() {
-			defer func() {
-				if e := recover(); e!=nil {
-					err := erx.NewSequent("Getting node predecessors.", e)
-					err.AddV("node id", node)
-					panic(err)
-				}
-			}()
-		
-			accessorsMap, ok := g.connections[node]
-			if !ok {
-				panic(erx.NewError("Node doesn't exists."))
-			}
-			
-			for VertexId, connType := range accessorsMap {
-				if connType==CT_DIRECTED_REVERSED {
-					ch <- VertexId
-				}
-			}
-	
-			close(ch)
-		}()
-		
-		return ch
-	}
-	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator}) 
+	iterator := g.connectedVertexesSeq(node, CT_DIRECTED_REVERSED, "Getting node predecessors.")
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator}) 
 }
 
 func (g *MixedMap) CheckArc(from, to VertexId) (isExist bool) {
@@ -327,18 +315,21 @@ func (g *MixedMap) CheckArc(from, to VertexId) (isExist bool) {
 }
 
 func (g *MixedMap) ArcsIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
+	return seqToChan(g.ArcsSeq())
+}
+
+func (g *MixedMap) ArcsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
 		for from, connectedVertexes := range g.connections {
 			for to, connType := range connectedVertexes {
-				if connType!=CT_UNDIRECTED {
-					ch <- Connection{from, to}
+				if connType==CT_DIRECTED {
+					if !yield(Connection{from, to}) {
+						return
+					}
 				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -346,29 +337,35 @@ func (g *MixedMap) ArcsIter() <-chan Connection {
 
 // Adding edge to graph.
 func (g *MixedMap) AddEdge(from, to VertexId) {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Add edge to graph.", e)
-			err.AddV("node 1", from)
-			err.AddV("node 2", to)
-			panic(err)
-		}
-	}()
+	if err := g.TryAddEdge(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Add edge to graph.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		panic(erxErr)
+	}
+}
 
-	g.touchNode(from)
-	g.touchNode(to)
+// Adding edge to graph.
+//
+// Nodes are created if they don't exist. Returns ErrLoop if from==to and
+// ErrDuplicateConnection if nodes are already connected.
+func (g *MixedMap) TryAddEdge(from, to VertexId) error {
+	if from==to {
+		return &ConnectionError{Op: "add edge", Tail: from, Head: to, Err: ErrLoop}
+	}
 	
-	if direction, ok := g.connections[from][to]; ok {
-		err := erx.NewError("Duplicate connection.")
-		err.AddV("connection type", direction)
-		panic(err)
+	if _, ok := g.connections[from][to]; ok {
+		return &ConnectionError{Op: "add edge", Tail: from, Head: to, Err: ErrDuplicateConnection}
 	}
 	
+	g.touchNode(from)
+	g.touchNode(to)
+	
 	g.connections[from][to] = CT_UNDIRECTED
 	g.connections[to][from] = CT_UNDIRECTED
 	g.edgesCnt++
 
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -376,28 +373,36 @@ func (g *MixedMap) AddEdge(from, to VertexId) {
 
 // Removing arrow  'from' and 'to' nodes
 func (g *MixedMap) RemoveEdge(from, to VertexId) {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Removing edge from graph.", e)
-			err.AddV("node 1", from)
-			err.AddV("node 2", to)
-			panic(err)
-		}
-	}()
-	
+	if err := g.TryRemoveEdge(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Removing edge from graph.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		panic(erxErr)
+	}
+}
+
+// Removing edge between 'from' and 'to' nodes
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such edge.
+func (g *MixedMap) TryRemoveEdge(from, to VertexId) error {
 	if _, ok := g.connections[from]; !ok {
-		panic(erx.NewError("First node doesn't exists"))
+		return &VertexError{Op: "remove edge", Node: from, Err: ErrNodeNotFound}
 	}
 	
 	if _, ok := g.connections[to]; !ok {
-		panic(erx.NewError("Second node doesn't exists"))
+		return &VertexError{Op: "remove edge", Node: to, Err: ErrNodeNotFound}
+	}
+	
+	if dir, ok := g.connections[from][to]; !ok || dir!=CT_UNDIRECTED {
+		return &ConnectionError{Op: "remove edge", Tail: from, Head: to, Err: ErrConnectionNotFound}
 	}
 	
-	g.connections[from][to] = CT_NONE, false
-	g.connections[to][from] = CT_NONE, false
+	delete(g.connections[from], to)
+	delete(g.connections[to], from)
 	g.edgesCnt--
 
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -407,37 +412,10 @@ func (g *MixedMap) EdgesCnt() int {
 	return g.edgesCnt
 }
 
-// Getting node predecessors
+// Getting node neighbours
 func (g *MixedMap) GetNeighbours(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		
-		go func() {
-			defer func() {
-				if e:=recover(); e!=nil {
-					err := erx.NewSequent("Get node neighbours.", e)
-					err.AddV("node id", node)
-					panic(err)
-				}
-			}()
-			
-			if connectedMap, ok := g.connections[node]; ok {
-				for VertexId, connType := range connectedMap {
-					if connType==CT_UNDIRECTED {
-						ch <- VertexId
-					}
-				}
-			} else {
-				panic(erx.NewError("Node doesn't exists."))
-			}
-
-			close(ch)
-		}()
-		
-		return ch
-	}
-	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator}) 
+	iterator := g.connectedVertexesSeq(node, CT_UNDIRECTED, "Get node neighbours.")
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator}) 
 }
 
 func (g *MixedMap) CheckEdge(from, to VertexId) bool {
@@ -465,20 +443,23 @@ func (g *MixedMap) CheckEdge(from, to VertexId) bool {
 }
 
 func (g *MixedMap) EdgesIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
+	return seqToChan(g.EdgesSeq())
+}
+
+func (g *MixedMap) EdgesSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
 		for from, connectedVertexes := range g.connections {
 			for to, connType := range connectedVertexes {
 				if from<to && connType==CT_UNDIRECTED {
 					// each edge has a duplicate, so we need to 
-					// push only one edge to channel
-					ch <- Connection{from, to}
+					// yield only one edge
+					if !yield(Connection{from, to}) {
+						return
+					}
 				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -516,18 +497,25 @@ func (g *MixedMap) ConnectionsCnt() int {
 }
 
 func (g *MixedMap) TypedConnectionsIter() <-chan TypedConnection {
-	ch := make(chan TypedConnection)
-	go func() {
+	return seqToChan(g.TypedConnectionsSeq())
+}
+
+func (g *MixedMap) TypedConnectionsSeq() iter.Seq[TypedConnection] {
+	return func(yield func(TypedConnection) bool) {
 		for from, connectedVertexes := range g.connections {
 			for to, connType := range connectedVertexes {
 				switch connType {
 					case CT_NONE:
 					case CT_UNDIRECTED:
 						if from<to {
-							ch <- TypedConnection{Connection:Connection{Tail: from, Head:to}, Type:CT_UNDIRECTED}
+							if !yield(TypedConnection{Connection:Connection{Tail: from, Head:to}, Type:CT_UNDIRECTED}) {
+								return
+							}
 						} 
 					case CT_DIRECTED:
-						ch <- TypedConnection{Connection:Connection{Tail: from, Head:to}, Type:CT_DIRECTED}
+						if !yield(TypedConnection{Connection:Connection{Tail: from, Head:to}, Type:CT_DIRECTED}) {
+							return
+						}
 					case CT_DIRECTED_REVERSED:
 					default:
 						err := erx.NewError("Internal error: wrong connection type in mixed graph matrix")
@@ -538,7 +526,5 @@ func (g *MixedMap) TypedConnectionsIter() <-chan TypedConnection {
 				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
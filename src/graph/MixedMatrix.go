@@ -1,55 +1,94 @@
 package graph
 
 import (
-	"github.com/StepLg/go-erx/src/erx"
+	"iter"
+
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 // Mixed graph with matrix as a internal representation.
 //
 // Doesn't allow duplicate edges and arcs, loops and reversed arcs.
-// Graph can't have more than size vertexes, where size set during initialization.
-// MixedMatrix use over (size^2/2) * sizeof(MixedConnectionType) bytes.
+// Matrix grows when there is no space for new vertex, places of removed
+// vertexes are reused. MixedMatrix use over
+// (size^2/2) * sizeof(MixedConnectionType) bytes, where size is current
+// capacity.
 type MixedMatrix struct {
+	matrixIds
 	nodes []MixedConnectionType
-	size int
-	VertexIds map[VertexId]int // internal node ids, used in nodes array
 	edgesCnt int
 	arcsCnt int
 }
 
+// Creating new mixed graph with matrix storage.
+//
+// size means initial number of nodes, which graph can handle without
+// reallocation.
 func NewMixedMatrix(size int) *MixedMatrix {
-	if size<=0 {
-		panic(erx.NewError("Trying to create mixed matrix graph with zero size"))
+	if size<0 {
+		panic(erx.NewError("Trying to create mixed matrix graph with negative size"))
 	}
 	g := new(MixedMatrix)
+	g.matrixIds = newMatrixIds(size)
 	g.nodes = make([]MixedConnectionType, size*(size-1)/2)
-	g.size = size
-	g.VertexIds = make(map[VertexId]int)
 	return g
 }
 
+// Current graph capacity
+//
+// Maximum nodes count graph can handle without reallocation
+func (gr *MixedMatrix) GetCapacity() int {
+	return gr.size
+}
+
+// Shrinking matrix to current nodes count.
+//
+// Internal ids of nodes are changed, so VertexIds shouldn't be used across
+// Compact call.
+func (gr *MixedMatrix) Compact() {
+	oldNodes := gr.nodes
+	mapping := gr.compactIds()
+	gr.nodes = make([]MixedConnectionType, gr.size*(gr.size-1)/2)
+	for oldId1, newId1 := range mapping {
+		for oldId2, newId2 := range mapping {
+			if oldId1<oldId2 {
+				gr.nodes[matrixTriangleIndex(newId1, newId2)] = oldNodes[matrixTriangleIndex(oldId1, oldId2)]
+			}
+		}
+	}
+}
+
+// Getting internal node id, creating node if it doesn't exist.
+func (gr *MixedMatrix) touchNode(node VertexId) int {
+	id, grown := gr.allocId(node)
+	if grown {
+		gr.nodes = append(gr.nodes, make([]MixedConnectionType, gr.size*(gr.size-1)/2 - len(gr.nodes))...)
+	}
+	return id
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // GraphVertexesWriter
 
 // Adding single node to graph
 func (gr *MixedMatrix) AddNode(node VertexId) {
-	defer func() {
-		if e := recover(); e!=nil {
-			err := erx.NewSequent("Add node to graph.", e)
-			err.AddV("node id", node)
-			panic(err)
-		}
-	}()
-	
-	if _, ok := gr.VertexIds[node]; ok {
-		panic(erx.NewError("Node already exists."))
+	if err := gr.TryAddNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Add node to graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
 	}
-	
-	if len(gr.VertexIds) == gr.size {
-		panic(erx.NewError("Not enough space to add new node"))
+}
+
+// Adding single node to graph
+//
+// Returns ErrNodeExists if node is already in graph.
+func (gr *MixedMatrix) TryAddNode(node VertexId) error {
+	if _, ok := gr.VertexIds[node]; ok {
+		return &VertexError{Op: "add node", Node: node, Err: ErrNodeExists}
 	}
 	
-	gr.VertexIds[node] = len(gr.VertexIds)
+	gr.touchNode(node)
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -63,16 +102,49 @@ func (gr *MixedMatrix) Order() int {
 ///////////////////////////////////////////////////////////////////////////////
 // GraphVertexesRemover
 
-// Removing node from graph
+// Removing node with all it's connections from graph
 func (gr *MixedMatrix) RemoveNode(node VertexId) {
-	panic("Function RemoveNode doesn't implement in MixedMatrix graph yet.")
+	if err := gr.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Removing node with all it's connections from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (gr *MixedMatrix) TryRemoveNode(node VertexId) error {
+	id, ok := gr.VertexIds[node]
+	if !ok {
+		return &VertexError{Op: "remove node", Node: node, Err: ErrNodeNotFound}
+	}
+	
+	for _, otherId := range gr.VertexIds {
+		if otherId==id {
+			continue
+		}
+		conn := matrixTriangleIndex(id, otherId)
+		switch gr.nodes[conn] {
+			case CT_UNDIRECTED:
+				gr.edgesCnt--
+			case CT_DIRECTED, CT_DIRECTED_REVERSED:
+				gr.arcsCnt--
+		}
+		gr.nodes[conn] = CT_NONE
+	}
+	gr.releaseId(node)
+	return nil
 }
 	
 ///////////////////////////////////////////////////////////////////////////////
 // ConnectionsIterable
 func (gr *MixedMatrix) ConnectionsIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
+	return seqToChan(gr.ConnectionsSeq())
+}
+
+func (gr *MixedMatrix) ConnectionsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
 		for from, _ := range gr.VertexIds {
 			for to, _ := range gr.VertexIds {
 				if from>=to {
@@ -81,26 +153,29 @@ func (gr *MixedMatrix) ConnectionsIter() <-chan Connection {
 				
 				conn := gr.getConnectionId(from, to, false)
 				if gr.nodes[conn]!=CT_NONE {
-					ch <- Connection{from, to}
+					if !yield(Connection{from, to}) {
+						return
+					}
 				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // VertexesIterable
 func (gr *MixedMatrix) VertexesIter() <-chan VertexId {
-	ch := make(chan VertexId)
-	go func() {
+	return seqToChan(gr.VertexesSeq())
+}
+
+func (gr *MixedMatrix) VertexesSeq() iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
 		for VertexId, _ := range gr.VertexIds {
-			ch <- VertexId
+			if !yield(VertexId) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -116,25 +191,30 @@ func (g *MixedMatrix) CheckNode(node VertexId) (exists bool) {
 
 // Adding new edge to graph
 func (gr *MixedMatrix) AddEdge(node1, node2 VertexId) {
-	defer func() {
-		if e := recover(); e!=nil {
-			err := erx.NewSequent("Add edge to mixed graph.", e)
-			err.AddV("node 1", node1)
-			err.AddV("node 2", node2)
-			panic(err)
-		}
-	}()
+	if err := gr.TryAddEdge(node1, node2); err!=nil {
+		erxErr := erx.NewSequentLevel("Add edge to mixed graph.", err, 1)
+		erxErr.AddV("node 1", node1)
+		erxErr.AddV("node 2", node2)
+		panic(erxErr)
+	}
+}
 
-	conn := gr.getConnectionId(node1, node2, true)
+// Adding new edge to graph
+//
+// Nodes are created if they don't exist. Returns ErrLoop if node1==node2 and
+// ErrDuplicateConnection if nodes are already connected.
+func (gr *MixedMatrix) TryAddEdge(node1, node2 VertexId) error {
+	conn, err := gr.tryGetConnectionId("add edge", node1, node2, true)
+	if err!=nil {
+		return err
+	}
 	if gr.nodes[conn]!=CT_NONE {
-		err := erx.NewError("Duplicate edge.")
-		err.AddV("connection id", conn)
-		err.AddV("type", gr.nodes[conn]) 
-		panic(err)
+		return &ConnectionError{Op: "add edge", Tail: node1, Head: node2, Err: ErrDuplicateConnection}
 	}
 	
 	gr.nodes[conn] = CT_UNDIRECTED
 	gr.edgesCnt++
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -142,25 +222,30 @@ func (gr *MixedMatrix) AddEdge(node1, node2 VertexId) {
 
 // Removing edge, connecting node1 and node2
 func (gr *MixedMatrix) RemoveEdge(node1, node2 VertexId) {
-	defer func() {
-		if e := recover(); e!=nil {
-			err := erx.NewSequent("Remove edge from mixed graph.", e)
-			err.AddV("node 1", node1)
-			err.AddV("node 2", node2)
-			panic(err)
-		}
-	}()
+	if err := gr.TryRemoveEdge(node1, node2); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove edge from mixed graph.", err, 1)
+		erxErr.AddV("node 1", node1)
+		erxErr.AddV("node 2", node2)
+		panic(erxErr)
+	}
+}
 
-	conn := gr.getConnectionId(node1, node2, true)
+// Removing edge, connecting node1 and node2
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such edge.
+func (gr *MixedMatrix) TryRemoveEdge(node1, node2 VertexId) error {
+	conn, err := gr.tryGetConnectionId("remove edge", node1, node2, false)
+	if err!=nil {
+		return err
+	}
 	if gr.nodes[conn]!=CT_UNDIRECTED {
-		err := erx.NewError("Edge doesn't exists.")
-		err.AddV("connection id", conn)
-		err.AddV("type", gr.nodes[conn])
-		panic(err)
+		return &ConnectionError{Op: "remove edge", Tail: node1, Head: node2, Err: ErrConnectionNotFound}
 	}
 	
 	gr.nodes[conn] = CT_NONE
 	gr.edgesCnt--
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -193,36 +278,33 @@ func (gr *MixedMatrix) CheckEdge(node1, node2 VertexId) bool {
 
 // Getting all nodes, connected to given one
 func (gr *MixedMatrix) GetNeighbours(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		
-		go func() {
-			defer func() {
-				if e := recover(); e!=nil {
-					err := erx.NewSequent("Get node neighbours in mixed graph.", e)
-					err.AddV("node", node)
-					panic(err)
-				}
-			}()
-			
-			for neighbour, _ := range gr.VertexIds {
-				if node==neighbour {
-					// skipping loops
-					continue
-				}
-	
-				connId := gr.getConnectionId(node, neighbour, false)			
-				if gr.nodes[connId]==CT_UNDIRECTED {
-					ch <- neighbour
+	iterator := func(yield func(VertexId) bool) {
+		gr.checkNodeExists(node, "Get node neighbours in mixed graph.")
+		for neighbour, _ := range gr.VertexIds {
+			if node==neighbour {
+				// skipping loops
+				continue
+			}
+
+			connId := gr.getConnectionId(node, neighbour, false)			
+			if gr.nodes[connId]==CT_UNDIRECTED {
+				if !yield(neighbour) {
+					return
 				}
 			}
-			close(ch)
-		}()
-			
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Panic with errorMsg if node doesn't exist in graph.
+func (gr *MixedMatrix) checkNodeExists(node VertexId, errorMsg string) {
+	if _, ok := gr.VertexIds[node]; !ok {
+		err := erx.NewSequentLevel(errorMsg, erx.NewError("Node doesn't exists."), 1)
+		err.AddV("node", node)
+		panic(err)
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -230,21 +312,25 @@ func (gr *MixedMatrix) GetNeighbours(node VertexId) VertexesIterable {
 
 // Adding directed arc to graph
 func (gr *MixedMatrix) AddArc(tail, head VertexId) {
-	defer func() {
-		if e := recover(); e!=nil {
-			err := erx.NewSequent("Add arc to mixed graph.", e)
-			err.AddV("tail", tail)
-			err.AddV("head", head)
-			panic(err)
-		}
-	}()
+	if err := gr.TryAddArc(tail, head); err!=nil {
+		erxErr := erx.NewSequentLevel("Add arc to mixed graph.", err, 1)
+		erxErr.AddV("tail", tail)
+		erxErr.AddV("head", head)
+		panic(erxErr)
+	}
+}
 
-	conn := gr.getConnectionId(tail, head, true)
+// Adding directed arc to graph
+//
+// Nodes are created if they don't exist. Returns ErrLoop if tail==head and
+// ErrDuplicateConnection if nodes are already connected.
+func (gr *MixedMatrix) TryAddArc(tail, head VertexId) error {
+	conn, err := gr.tryGetConnectionId("add arc", tail, head, true)
+	if err!=nil {
+		return err
+	}
 	if gr.nodes[conn]!=CT_NONE {
-		err := erx.NewError("Duplicate edge.")
-		err.AddV("connection id", conn)
-		err.AddV("type", gr.nodes[conn]) 
-		panic(err)
+		return &ConnectionError{Op: "add arc", Tail: tail, Head: head, Err: ErrDuplicateConnection}
 	}
 	
 	if tail<head {
@@ -254,6 +340,7 @@ func (gr *MixedMatrix) AddArc(tail, head VertexId) {
 	}
 	
 	gr.arcsCnt++
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -261,16 +348,23 @@ func (gr *MixedMatrix) AddArc(tail, head VertexId) {
 
 // Removding directed arc
 func (gr *MixedMatrix) RemoveArc(tail, head VertexId) {
-	defer func() {
-		if e := recover(); e!=nil {
-			err := erx.NewSequent("Remove arc from mixed graph.", e)
-			err.AddV("tail", tail)
-			err.AddV("head", head)
-			panic(err)
-		}
-	}()
+	if err := gr.TryRemoveArc(tail, head); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove arc from mixed graph.", err, 1)
+		erxErr.AddV("tail", tail)
+		erxErr.AddV("head", head)
+		panic(erxErr)
+	}
+}
 
-	conn := gr.getConnectionId(tail, head, true)
+// Removding directed arc
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such arc.
+func (gr *MixedMatrix) TryRemoveArc(tail, head VertexId) error {
+	conn, err := gr.tryGetConnectionId("remove arc", tail, head, false)
+	if err!=nil {
+		return err
+	}
 	expectedType := CT_NONE
 	if tail<head {
 		expectedType = CT_DIRECTED
@@ -279,14 +373,12 @@ func (gr *MixedMatrix) RemoveArc(tail, head VertexId) {
 	}
 	
 	if gr.nodes[conn]!=expectedType {
-		err := erx.NewError("Arc doesn't exists.")
-		err.AddV("connection id", conn)
-		err.AddV("type", gr.nodes[conn])
-		panic(err)
+		return &ConnectionError{Op: "remove arc", Tail: tail, Head: head, Err: ErrConnectionNotFound}
 	}
 	
 	gr.nodes[conn] = CT_NONE
 	gr.arcsCnt--
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -299,162 +391,132 @@ func (gr *MixedMatrix) ArcsCnt() int {
 
 // Getting all graph sources.
 func (gr *MixedMatrix) GetSources() VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		go func() {
-			for tailNode, _ := range gr.VertexIds {
-				hasPredecessors := false
-				for headNode, _ := range gr.VertexIds {
-					if tailNode==headNode {
-						continue
-					}
-					
-					checkingType := CT_NONE
-					if tailNode < headNode {
-						checkingType = CT_DIRECTED_REVERSED
-					} else {
-						checkingType = CT_DIRECTED
-					}
+	iterator := func(yield func(VertexId) bool) {
+		for tailNode, _ := range gr.VertexIds {
+			hasPredecessors := false
+			for headNode, _ := range gr.VertexIds {
+				if tailNode==headNode {
+					continue
+				}
 				
-					connId := gr.getConnectionId(tailNode, headNode, false)
-	
-					if gr.nodes[connId]==checkingType {
-						hasPredecessors = true
-						break
-					}
+				checkingType := CT_NONE
+				if tailNode < headNode {
+					checkingType = CT_DIRECTED_REVERSED
+				} else {
+					checkingType = CT_DIRECTED
 				}
-				if !hasPredecessors {
-					ch <- tailNode
+			
+				connId := gr.getConnectionId(tailNode, headNode, false)
+
+				if gr.nodes[connId]==checkingType {
+					hasPredecessors = true
+					break
 				}
 			}
-			close(ch)
-		}()
-		return ch
+			if !hasPredecessors {
+				if !yield(tailNode) {
+					return
+				}
+			}
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Getting all graph sinks.
 func (gr *MixedMatrix) GetSinks() VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		go func() {
-			for tailNode, _ := range gr.VertexIds {
-				hasPredecessors := false
-				for headNode, _ := range gr.VertexIds {
-					if tailNode==headNode {
-						continue
-					}
-					
-					checkingType := CT_NONE
-					if tailNode < headNode {
-						checkingType = CT_DIRECTED
-					} else {
-						checkingType = CT_DIRECTED_REVERSED
-					}
+	iterator := func(yield func(VertexId) bool) {
+		for tailNode, _ := range gr.VertexIds {
+			hasPredecessors := false
+			for headNode, _ := range gr.VertexIds {
+				if tailNode==headNode {
+					continue
+				}
 				
-					connId := gr.getConnectionId(tailNode, headNode, false)
-	
-					if gr.nodes[connId]==checkingType {
-						hasPredecessors = true
-						break
-					}
+				checkingType := CT_NONE
+				if tailNode < headNode {
+					checkingType = CT_DIRECTED
+				} else {
+					checkingType = CT_DIRECTED_REVERSED
 				}
-				if !hasPredecessors {
-					ch <- tailNode
+			
+				connId := gr.getConnectionId(tailNode, headNode, false)
+
+				if gr.nodes[connId]==checkingType {
+					hasPredecessors = true
+					break
 				}
 			}
-			close(ch)
-		}()
-		return ch
+			if !hasPredecessors {
+				if !yield(tailNode) {
+					return
+				}
+			}
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Getting node accessors
 func (gr *MixedMatrix) GetAccessors(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		
-		go func() {
-			defer func() {
-				if e := recover(); e!=nil {
-					err := erx.NewSequent("Get node accessors in mixed graph.", e)
-					err.AddV("node", node)
-					panic(err)
-				}
-			}()
+	iterator := func(yield func(VertexId) bool) {
+		gr.checkNodeExists(node, "Get node accessors in mixed graph.")
+		for headNode, _ := range gr.VertexIds {
+			if node==headNode {
+				// skipping loops
+				continue
+			}
+
+			checkingType := CT_NONE
+			if node < headNode {
+				checkingType = CT_DIRECTED
+			} else {
+				checkingType = CT_DIRECTED_REVERSED
+			}
+
+			connId := gr.getConnectionId(node, headNode, false)
 			
-			for headNode, _ := range gr.VertexIds {
-				if node==headNode {
-					// skipping loops
-					continue
-				}
-	
-				checkingType := CT_NONE
-				if node < headNode {
-					checkingType = CT_DIRECTED
-				} else {
-					checkingType = CT_DIRECTED_REVERSED
-				}
-	
-				connId := gr.getConnectionId(node, headNode, false)
-				
-				if gr.nodes[connId]==checkingType {
-					ch <- headNode
+			if gr.nodes[connId]==checkingType {
+				if !yield(headNode) {
+					return
 				}
 			}
-			close(ch)
-		}()
-			
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Getting node predecessors
 func (gr *MixedMatrix) GetPredecessors(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		
-		go func() {
-			defer func() {
-				if e := recover(); e!=nil {
-					err := erx.NewSequent("Get node predecessors in mixed graph.", e)
-					err.AddV("node", node)
-					panic(err)
-				}
-			}()
+	iterator := func(yield func(VertexId) bool) {
+		gr.checkNodeExists(node, "Get node predecessors in mixed graph.")
+		for tailNode, _ := range gr.VertexIds {
+			if node==tailNode {
+				// skipping loops
+				continue
+			}
+
+			checkingType := CT_NONE
+			if node < tailNode {
+				checkingType = CT_DIRECTED_REVERSED
+			} else {
+				checkingType = CT_DIRECTED
+			}
+
+			connId := gr.getConnectionId(node, tailNode, false)
 			
-			for tailNode, _ := range gr.VertexIds {
-				if node==tailNode {
-					// skipping loops
-					continue
-				}
-	
-				checkingType := CT_NONE
-				if node < tailNode {
-					checkingType = CT_DIRECTED_REVERSED
-				} else {
-					checkingType = CT_DIRECTED
-				}
-	
-				connId := gr.getConnectionId(node, tailNode, false)
-				
-				if gr.nodes[connId]==checkingType {
-					ch <- tailNode
+			if gr.nodes[connId]==checkingType {
+				if !yield(tailNode) {
+					return
 				}
 			}
-			close(ch)
-		}()
-			
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Checking arrow existance between node1 and node2
@@ -485,8 +547,12 @@ func (gr *MixedMatrix) CheckArc(tail, head VertexId) bool {
 
 // Iterate over only undirected edges
 func (gr *MixedMatrix) EdgesIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
+	return seqToChan(gr.EdgesSeq())
+}
+
+// Iterate over only undirected edges
+func (gr *MixedMatrix) EdgesSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
 		for from, _ := range gr.VertexIds {
 			for to, _ := range gr.VertexIds {
 				if from>=to {
@@ -494,19 +560,23 @@ func (gr *MixedMatrix) EdgesIter() <-chan Connection {
 				}
 				
 				if gr.nodes[gr.getConnectionId(from, to, false)]==CT_UNDIRECTED {
-					ch <- Connection{from, to}
+					if !yield(Connection{from, to}) {
+						return
+					}
 				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 	
 // Iterate over only directed arcs
 func (gr *MixedMatrix) ArcsIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
+	return seqToChan(gr.ArcsSeq())
+}
+
+// Iterate over only directed arcs
+func (gr *MixedMatrix) ArcsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
 		for from, _ := range gr.VertexIds {
 			for to, _ := range gr.VertexIds {
 				if from>=to {
@@ -515,16 +585,18 @@ func (gr *MixedMatrix) ArcsIter() <-chan Connection {
 				
 				conn := gr.getConnectionId(from, to, false)
 				if gr.nodes[conn]==CT_DIRECTED {
-					ch <- Connection{from, to}
+					if !yield(Connection{from, to}) {
+						return
+					}
 				}
 				if gr.nodes[conn]==CT_DIRECTED_REVERSED {
-					ch <- Connection{to, from}
+					if !yield(Connection{to, from}) {
+						return
+					}
 				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 func (gr *MixedMatrix) CheckEdgeType(tail VertexId, head VertexId) MixedConnectionType {
@@ -550,8 +622,11 @@ func (g *MixedMatrix) ConnectionsCnt() int {
 }
 
 func (gr *MixedMatrix) TypedConnectionsIter() <-chan TypedConnection {
-	ch := make(chan TypedConnection)
-	go func() {
+	return seqToChan(gr.TypedConnectionsSeq())
+}
+
+func (gr *MixedMatrix) TypedConnectionsSeq() iter.Seq[TypedConnection] {
+	return func(yield func(TypedConnection) bool) {
 		for from, _ := range gr.VertexIds {
 			for to, _ := range gr.VertexIds {
 				if from>=to {
@@ -559,14 +634,16 @@ func (gr *MixedMatrix) TypedConnectionsIter() <-chan TypedConnection {
 				}
 				
 				conn := gr.getConnectionId(from, to, false)
+				var typedConn TypedConnection
 				switch gr.nodes[conn] {
 					case CT_NONE:
+						continue
 					case CT_UNDIRECTED:
-						ch <- TypedConnection{Connection:Connection{Tail: from, Head:to}, Type:CT_UNDIRECTED} 
+						typedConn = TypedConnection{Connection:Connection{Tail: from, Head:to}, Type:CT_UNDIRECTED} 
 					case CT_DIRECTED:
-						ch <- TypedConnection{Connection:Connection{Tail: from, Head:to}, Type:CT_DIRECTED}
+						typedConn = TypedConnection{Connection:Connection{Tail: from, Head:to}, Type:CT_DIRECTED}
 					case CT_DIRECTED_REVERSED:
-						ch <- TypedConnection{Connection:Connection{Tail: to, Head:from}, Type:CT_DIRECTED}
+						typedConn = TypedConnection{Connection:Connection{Tail: to, Head:from}, Type:CT_DIRECTED}
 					default:
 						err := erx.NewError("Internal error: wrong connection type in mixed graph matrix")
 						err.AddV("connection type", gr.nodes[conn])
@@ -575,70 +652,42 @@ func (gr *MixedMatrix) TypedConnectionsIter() <-chan TypedConnection {
 						err.AddV("head node", to)
 						panic(err)
 				}
+				if !yield(typedConn) {
+					return
+				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 func (gr *MixedMatrix) getConnectionId(node1, node2 VertexId, create bool) int {
-	defer func() {
-		if e := recover(); e!=nil {
-			err := erx.NewSequent("Calculating connection id.", e)
-			err.AddV("node 1", node1)
-			err.AddV("node 2", node2)
-			panic(err)
-		}
-	}()
-	
-	var id1, id2 int
-	node1Exist := false
-	node2Exist := false
-	id1, node1Exist = gr.VertexIds[node1]
-	id2, node2Exist = gr.VertexIds[node2]
-	
-	// checking for errors
-	{
-		if node1==node2 {
-			panic(erx.NewError("Equal nodes."))
-		}
-		if !create {
-			if !node1Exist {
-				panic(erx.NewError("First node doesn't exist in graph"))
-			}
-			if !node2Exist {
-				panic(erx.NewError("Second node doesn't exist in graph"))
-			}
-		} else if !node1Exist || !node2Exist {
-			if node1Exist && node2Exist {
-				if gr.size - len(gr.VertexIds) < 2 {
-					panic(erx.NewError("Not enough space to create two new nodes."))
-				}
-			} else {
-				if gr.size - len(gr.VertexIds) < 1 {
-					panic(erx.NewError("Not enough space to create new node."))
-				}
-			}
-		}
-	}
-	
-	if !node1Exist {
-		id1 = int(len(gr.VertexIds))
-		gr.VertexIds[node1] = id1
+	connId, err := gr.tryGetConnectionId("get connection", node1, node2, create)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Calculating connection id.", err, 1)
+		erxErr.AddV("node 1", node1)
+		erxErr.AddV("node 2", node2)
+		panic(erxErr)
 	}
+	return connId
+}
 
-	if !node2Exist {
-		id2 = int(len(gr.VertexIds))
-		gr.VertexIds[node2] = id2
+// Calculating connection id.
+//
+// op is an operation name for returned errors. Nodes are created if create
+// flag is set.
+func (gr *MixedMatrix) tryGetConnectionId(op string, node1, node2 VertexId, create bool) (int, error) {
+	if node1==node2 {
+		return 0, &ConnectionError{Op: op, Tail: node1, Head: node2, Err: ErrLoop}
 	}
 	
-	// switching id1, id2 in order to id1 < id2
-	if id1>id2 {
-		id1, id2 = id2, id1
+	if !create {
+		if !gr.CheckNode(node1) {
+			return 0, &VertexError{Op: op, Node: node1, Err: ErrNodeNotFound}
+		}
+		if !gr.CheckNode(node2) {
+			return 0, &VertexError{Op: op, Node: node2, Err: ErrNodeNotFound}
+		}
 	}
 	
-	// id from upper triangle matrix, stored in vector
-	connId := id1*(gr.size-1) + id2 - 1 - id1*(id1+1)/2
-	return connId 
+	return matrixTriangleIndex(gr.touchNode(node1), gr.touchNode(node2)), nil
 }
@@ -0,0 +1,638 @@
+package graph
+
+import (
+	"iter"
+
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Common storage for multigraphs with map as a internal representation.
+//
+// Each connection has it's own id. Several arcs and edges could connect the
+// same nodes, loops are allowed.
+type multiMap struct {
+	// multiplicity of arcs by tail and head
+	accessors map[VertexId]map[VertexId]int
+	// multiplicity of arcs by head and tail
+	predecessors map[VertexId]map[VertexId]int
+	// multiplicity of edges in both directions, loops are stored once
+	neighbours map[VertexId]map[VertexId]int
+	connections map[ConnectionId]TypedConnection
+	// connections ids by arc or edge (edge tail is not greater than head)
+	ids map[TypedConnection][]ConnectionId
+	nextId ConnectionId
+	arcsCnt int
+	edgesCnt int
+}
+
+func newMultiMap() *multiMap {
+	return &multiMap{
+		accessors: make(map[VertexId]map[VertexId]int),
+		predecessors: make(map[VertexId]map[VertexId]int),
+		neighbours: make(map[VertexId]map[VertexId]int),
+		connections: make(map[ConnectionId]TypedConnection),
+		ids: make(map[TypedConnection][]ConnectionId),
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// ConnectionsIterable
+
+func (g *multiMap) ConnectionsIter() <-chan Connection {
+	return seqToChan(g.ConnectionsSeq())
+}
+
+func (g *multiMap) ConnectionsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for _, conn := range g.connections {
+			if !yield(conn.Connection) {
+				return
+			}
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// VertexesIterable
+
+func (g *multiMap) VertexesIter() <-chan VertexId {
+	return seqToChan(g.VertexesSeq())
+}
+
+func (g *multiMap) VertexesSeq() iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
+		for node, _ := range g.accessors {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesReader
+
+func (g *multiMap) CheckNode(node VertexId) (exists bool) {
+	_, exists = g.accessors[node]
+	return
+}
+
+// Getting nodes count in graph
+func (g *multiMap) Order() int {
+	return len(g.accessors)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesWriter
+
+// Adding single node to graph
+func (g *multiMap) AddNode(node VertexId) {
+	if err := g.TryAddNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Add node to graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Adding single node to graph
+//
+// Returns ErrNodeExists if node is already in graph.
+func (g *multiMap) TryAddNode(node VertexId) error {
+	if g.CheckNode(node) {
+		return &VertexError{Op: "add node", Node: node, Err: ErrNodeExists}
+	}
+	g.touchNode(node)
+	return nil
+}
+
+func (g *multiMap) touchNode(node VertexId) {
+	if _, ok := g.accessors[node]; !ok {
+		g.accessors[node] = make(map[VertexId]int)
+		g.predecessors[node] = make(map[VertexId]int)
+		g.neighbours[node] = make(map[VertexId]int)
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesRemover
+
+// Removing node with all it's connections from graph
+func (g *multiMap) RemoveNode(node VertexId) {
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Removing node with all it's connections from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *multiMap) TryRemoveNode(node VertexId) error {
+	if !g.CheckNode(node) {
+		return &VertexError{Op: "remove node", Node: node, Err: ErrNodeNotFound}
+	}
+
+	ids := make([]ConnectionId, 0)
+	for head, _ := range g.accessors[node] {
+		ids = append(ids, g.ids[NewDirectedConnection(node, head)]...)
+	}
+	for tail, _ := range g.predecessors[node] {
+		if tail!=node {
+			ids = append(ids, g.ids[NewDirectedConnection(tail, node)]...)
+		}
+	}
+	for neighbour, _ := range g.neighbours[node] {
+		ids = append(ids, g.ids[NewUndirectedConnection(node, neighbour)]...)
+	}
+	for _, id := range ids {
+		g.removeId(id)
+	}
+
+	delete(g.accessors, node)
+	delete(g.predecessors, node)
+	delete(g.neighbours, node)
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// MultiGraphConnectionsReader
+
+// Getting connection by id.
+//
+// Arcs have CT_DIRECTED type and edges have CT_UNDIRECTED type.
+func (g *multiMap) LookupConnection(id ConnectionId) (TypedConnection, bool) {
+	conn, ok := g.connections[id]
+	return conn, ok
+}
+
+// Iterating over all connections with their ids
+func (g *multiMap) IdentifiedConnectionsSeq() iter.Seq2[ConnectionId, TypedConnection] {
+	return func(yield func(ConnectionId, TypedConnection) bool) {
+		for id, conn := range g.connections {
+			if !yield(id, conn) {
+				return
+			}
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// MultiGraphConnectionsRemover
+
+// Removing connection by id
+func (g *multiMap) RemoveConnection(id ConnectionId) {
+	if err := g.TryRemoveConnection(id); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove connection from graph.", err, 1)
+		erxErr.AddV("connection id", id)
+		panic(erxErr)
+	}
+}
+
+// Removing connection by id
+//
+// Returns ErrConnectionNotFound if there is no connection with such id.
+func (g *multiMap) TryRemoveConnection(id ConnectionId) error {
+	if _, ok := g.connections[id]; !ok {
+		return &ConnectionIdError{Op: "remove connection", Id: id, Err: ErrConnectionNotFound}
+	}
+	g.removeId(id)
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// internal
+
+// Adding connection, nodes are created if they don't exist.
+//
+// conn must be normalized: edge tail is not greater than head.
+func (g *multiMap) addConnection(conn TypedConnection) ConnectionId {
+	g.touchNode(conn.Tail)
+	g.touchNode(conn.Head)
+
+	id := g.nextId
+	g.nextId++
+	g.connections[id] = conn
+	g.ids[conn] = append(g.ids[conn], id)
+
+	if conn.Type==CT_UNDIRECTED {
+		g.neighbours[conn.Tail][conn.Head]++
+		if conn.Tail!=conn.Head {
+			g.neighbours[conn.Head][conn.Tail]++
+		}
+		g.edgesCnt++
+	} else {
+		g.accessors[conn.Tail][conn.Head]++
+		g.predecessors[conn.Head][conn.Tail]++
+		g.arcsCnt++
+	}
+	return id
+}
+
+// Removing last added connection between nodes.
+//
+// conn must be normalized: edge tail is not greater than head.
+func (g *multiMap) tryRemoveLastConnection(op string, conn TypedConnection) error {
+	if !g.CheckNode(conn.Tail) {
+		return &VertexError{Op: op, Node: conn.Tail, Err: ErrNodeNotFound}
+	}
+	if !g.CheckNode(conn.Head) {
+		return &VertexError{Op: op, Node: conn.Head, Err: ErrNodeNotFound}
+	}
+	ids := g.ids[conn]
+	if len(ids)==0 {
+		return &ConnectionError{Op: op, Tail: conn.Tail, Head: conn.Head, Err: ErrConnectionNotFound}
+	}
+	g.removeId(ids[len(ids)-1])
+	return nil
+}
+
+// Removing existing connection by id.
+func (g *multiMap) removeId(id ConnectionId) {
+	conn := g.connections[id]
+	delete(g.connections, id)
+
+	ids := g.ids[conn]
+	for i, otherId := range ids {
+		if otherId==id {
+			ids = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+	if len(ids)==0 {
+		delete(g.ids, conn)
+	} else {
+		g.ids[conn] = ids
+	}
+
+	decrement := func(multiplicity map[VertexId]int, node VertexId) {
+		multiplicity[node]--
+		if multiplicity[node]==0 {
+			delete(multiplicity, node)
+		}
+	}
+	if conn.Type==CT_UNDIRECTED {
+		decrement(g.neighbours[conn.Tail], conn.Head)
+		if conn.Tail!=conn.Head {
+			decrement(g.neighbours[conn.Head], conn.Tail)
+		}
+		g.edgesCnt--
+	} else {
+		decrement(g.accessors[conn.Tail], conn.Head)
+		decrement(g.predecessors[conn.Head], conn.Tail)
+		g.arcsCnt--
+	}
+}
+
+// Panic if one of nodes doesn't exist in graph.
+func (g *multiMap) checkNodesExist(errorMsg string, node1, node2 VertexId) {
+	for _, node := range []VertexId{node1, node2} {
+		if !g.CheckNode(node) {
+			err := erx.NewSequentLevel(errorMsg, &VertexError{Op: "check", Node: node, Err: ErrNodeNotFound}, 2)
+			err.AddV("node 1", node1)
+			err.AddV("node 2", node2)
+			panic(err)
+		}
+	}
+}
+
+// Nodes from multiplicity map.
+func (g *multiMap) multiplicityKeys(multiplicity map[VertexId]map[VertexId]int, node VertexId, errorMsg string) VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		connected, ok := multiplicity[node]
+		if !ok {
+			err := erx.NewSequent(errorMsg, erx.NewError("Node doesn't exists."))
+			err.AddV("node", node)
+			panic(err)
+		}
+		for other, _ := range connected {
+			if !yield(other) {
+				return
+			}
+		}
+	}
+
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Copy of connections ids.
+func (g *multiMap) copyIds(conn TypedConnection) []ConnectionId {
+	ids := g.ids[conn]
+	res := make([]ConnectionId, len(ids))
+	copy(res, ids)
+	return res
+}
+
+// Iterating over connections of given type.
+func (g *multiMap) connectionsOfTypeSeq(connType MixedConnectionType) iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for _, conn := range g.connections {
+			if conn.Type==connType {
+				if !yield(conn.Connection) {
+					return
+				}
+			}
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Arcs part of multigraph.
+type multiMapArcs struct {
+	m *multiMap
+}
+
+// Adding new arc to graph.
+//
+// Arc is added even if nodes are already connected.
+func (a multiMapArcs) AddArc(from, to VertexId) {
+	a.m.addConnection(NewDirectedConnection(from, to))
+}
+
+// Adding new arc to graph.
+//
+// Never returns error, multigraph allows parallel arcs and loops.
+func (a multiMapArcs) TryAddArc(from, to VertexId) error {
+	a.m.addConnection(NewDirectedConnection(from, to))
+	return nil
+}
+
+// Adding new arc to graph and getting it's id.
+func (a multiMapArcs) InsertArc(from, to VertexId) ConnectionId {
+	return a.m.addConnection(NewDirectedConnection(from, to))
+}
+
+// Removing last added arc from 'from' to 'to' node
+func (a multiMapArcs) RemoveArc(from, to VertexId) {
+	if err := a.TryRemoveArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove arc from graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+}
+
+// Removing last added arc from 'from' to 'to' node
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such arc.
+func (a multiMapArcs) TryRemoveArc(from, to VertexId) error {
+	return a.m.tryRemoveLastConnection("remove arc", NewDirectedConnection(from, to))
+}
+
+// Getting arcs count in graph, parallel arcs are counted separately
+func (a multiMapArcs) ArcsCnt() int {
+	return a.m.arcsCnt
+}
+
+// Getting all graph sources.
+func (a multiMapArcs) GetSources() VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for node, predecessors := range a.m.predecessors {
+			if len(predecessors)==0 {
+				if !yield(node) {
+					return
+				}
+			}
+		}
+	}
+
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Getting all graph sinks.
+func (a multiMapArcs) GetSinks() VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for node, accessors := range a.m.accessors {
+			if len(accessors)==0 {
+				if !yield(node) {
+					return
+				}
+			}
+		}
+	}
+
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Getting node accessors, each of them only once
+func (a multiMapArcs) GetAccessors(node VertexId) VertexesIterable {
+	return a.m.multiplicityKeys(a.m.accessors, node, "Get node accessors in multigraph.")
+}
+
+// Getting node predecessors, each of them only once
+func (a multiMapArcs) GetPredecessors(node VertexId) VertexesIterable {
+	return a.m.multiplicityKeys(a.m.predecessors, node, "Get node predecessors in multigraph.")
+}
+
+// Checking arrow existance between node1 and node2
+//
+// node1 and node2 must exist in graph or error will be returned
+func (a multiMapArcs) CheckArc(from, to VertexId) bool {
+	return a.ArcMultiplicity(from, to)>0
+}
+
+// Getting number of arcs from 'from' to 'to' node
+//
+// Nodes must exist in graph or error will be returned
+func (a multiMapArcs) ArcMultiplicity(from, to VertexId) int {
+	a.m.checkNodesExist("Getting arcs multiplicity.", from, to)
+	return a.m.accessors[from][to]
+}
+
+// Getting ids of all arcs from 'from' to 'to' node in adding order
+func (a multiMapArcs) ArcIds(from, to VertexId) []ConnectionId {
+	return a.m.copyIds(NewDirectedConnection(from, to))
+}
+
+// Iterating over arcs, parallel arcs are yielded separately
+func (a multiMapArcs) ArcsIter() <-chan Connection {
+	return seqToChan(a.ArcsSeq())
+}
+
+// Iterating over arcs, parallel arcs are yielded separately
+func (a multiMapArcs) ArcsSeq() iter.Seq[Connection] {
+	return a.m.connectionsOfTypeSeq(CT_DIRECTED)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Edges part of multigraph.
+type multiMapEdges struct {
+	m *multiMap
+}
+
+// Adding new edge to graph.
+//
+// Edge is added even if nodes are already connected.
+func (e multiMapEdges) AddEdge(node1, node2 VertexId) {
+	e.m.addConnection(NewUndirectedConnection(node1, node2))
+}
+
+// Adding new edge to graph.
+//
+// Never returns error, multigraph allows parallel edges and loops.
+func (e multiMapEdges) TryAddEdge(node1, node2 VertexId) error {
+	e.m.addConnection(NewUndirectedConnection(node1, node2))
+	return nil
+}
+
+// Adding new edge to graph and getting it's id.
+func (e multiMapEdges) InsertEdge(node1, node2 VertexId) ConnectionId {
+	return e.m.addConnection(NewUndirectedConnection(node1, node2))
+}
+
+// Removing last added edge between node1 and node2
+func (e multiMapEdges) RemoveEdge(node1, node2 VertexId) {
+	if err := e.TryRemoveEdge(node1, node2); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove edge from graph.", err, 1)
+		erxErr.AddV("node 1", node1)
+		erxErr.AddV("node 2", node2)
+		panic(erxErr)
+	}
+}
+
+// Removing last added edge between node1 and node2
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such edge.
+func (e multiMapEdges) TryRemoveEdge(node1, node2 VertexId) error {
+	return e.m.tryRemoveLastConnection("remove edge", NewUndirectedConnection(node1, node2))
+}
+
+// Getting edges count in graph, parallel edges are counted separately
+func (e multiMapEdges) EdgesCnt() int {
+	return e.m.edgesCnt
+}
+
+// Getting all nodes, connected to given one, each of them only once
+func (e multiMapEdges) GetNeighbours(node VertexId) VertexesIterable {
+	return e.m.multiplicityKeys(e.m.neighbours, node, "Get node neighbours in multigraph.")
+}
+
+// Checking edge existance between node1 and node2
+//
+// node1 and node2 must exist in graph or error will be returned
+func (e multiMapEdges) CheckEdge(node1, node2 VertexId) bool {
+	return e.EdgeMultiplicity(node1, node2)>0
+}
+
+// Getting number of edges between node1 and node2
+//
+// Nodes must exist in graph or error will be returned
+func (e multiMapEdges) EdgeMultiplicity(node1, node2 VertexId) int {
+	e.m.checkNodesExist("Getting edges multiplicity.", node1, node2)
+	return e.m.neighbours[node1][node2]
+}
+
+// Getting ids of all edges between node1 and node2 in adding order
+func (e multiMapEdges) EdgeIds(node1, node2 VertexId) []ConnectionId {
+	return e.m.copyIds(NewUndirectedConnection(node1, node2))
+}
+
+// Iterating over edges, parallel edges are yielded separately
+func (e multiMapEdges) EdgesIter() <-chan Connection {
+	return seqToChan(e.EdgesSeq())
+}
+
+// Iterating over edges, parallel edges are yielded separately
+func (e multiMapEdges) EdgesSeq() iter.Seq[Connection] {
+	return e.m.connectionsOfTypeSeq(CT_UNDIRECTED)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Directed multigraph with map as a internal representation.
+//
+// Allows parallel arcs and loops. Each arc has it's own id.
+type DirectedMultiMap struct {
+	*multiMap
+	multiMapArcs
+}
+
+func NewDirectedMultiMap() *DirectedMultiMap {
+	m := newMultiMap()
+	return &DirectedMultiMap{
+		multiMap: m,
+		multiMapArcs: multiMapArcs{m},
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Undirected multigraph with map as a internal representation.
+//
+// Allows parallel edges and loops. Each edge has it's own id.
+type UndirectedMultiMap struct {
+	*multiMap
+	multiMapEdges
+}
+
+func NewUndirectedMultiMap() *UndirectedMultiMap {
+	m := newMultiMap()
+	return &UndirectedMultiMap{
+		multiMap: m,
+		multiMapEdges: multiMapEdges{m},
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Mixed multigraph with map as a internal representation.
+//
+// Allows parallel arcs and edges between the same nodes, arcs in both
+// directions and loops. Each connection has it's own id.
+type MixedMultiMap struct {
+	*multiMap
+	multiMapArcs
+	multiMapEdges
+}
+
+func NewMixedMultiMap() *MixedMultiMap {
+	m := newMultiMap()
+	return &MixedMultiMap{
+		multiMap: m,
+		multiMapArcs: multiMapArcs{m},
+		multiMapEdges: multiMapEdges{m},
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// MixedGraphSpecificReader
+
+// Getting connection type between tail and head.
+//
+// If nodes are connected with both edges and arcs, CT_UNDIRECTED is
+// returned. Then CT_DIRECTED if there is an arc from tail to head.
+func (g *MixedMultiMap) CheckEdgeType(tail, head VertexId) MixedConnectionType {
+	g.checkNodesExist("Check edge type in mixed multigraph.", tail, head)
+	switch {
+		case g.neighbours[tail][head]>0:
+			return CT_UNDIRECTED
+		case g.accessors[tail][head]>0:
+			return CT_DIRECTED
+		case g.predecessors[tail][head]>0:
+			return CT_DIRECTED_REVERSED
+	}
+	return CT_NONE
+}
+
+// Getting connections count, parallel connections are counted separately
+func (g *MixedMultiMap) ConnectionsCnt() int {
+	return g.arcsCnt + g.edgesCnt
+}
+
+func (g *MixedMultiMap) TypedConnectionsIter() <-chan TypedConnection {
+	return seqToChan(g.TypedConnectionsSeq())
+}
+
+func (g *MixedMultiMap) TypedConnectionsSeq() iter.Seq[TypedConnection] {
+	return func(yield func(TypedConnection) bool) {
+		for _, conn := range g.connections {
+			if !yield(conn) {
+				return
+			}
+		}
+	}
+}
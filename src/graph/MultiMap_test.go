@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"iter"
+	"testing"
+)
+
+func countConnections(seq iter.Seq[Connection]) int {
+	cnt := 0
+	for _ = range seq {
+		cnt++
+	}
+	return cnt
+}
+
+func TestDirectedMultiMap(t *testing.T) {
+	gr := NewDirectedMultiMap()
+	id1 := gr.InsertArc(1, 2)
+	id2 := gr.InsertArc(1, 2)
+	gr.AddArc(2, 1)
+	gr.AddArc(2, 2)
+	gr.AddArc(2, 2)
+
+	t.Run("multiplicity", func(t *testing.T) {
+		expectEquals(t, gr.ArcsCnt(), 5)
+		expectEquals(t, gr.ArcMultiplicity(1, 2), 2)
+		expectEquals(t, gr.ArcMultiplicity(2, 1), 1)
+		expectEquals(t, gr.ArcMultiplicity(2, 2), 2)
+		expectVertexesExactly(t, CollectVertexes(gr.GetAccessors(2)), 1, 2)
+		expectVertexesExactly(t, CollectVertexes(gr.GetPredecessors(2)), 1, 2)
+		expectEquals(t, countConnections(gr.ArcsSeq()), 5)
+		expectPanic(t, "unknown node", func() { gr.ArcMultiplicity(1, 3) })
+	})
+
+	t.Run("ids", func(t *testing.T) {
+		expectTrue(t, id1!=id2, "parallel arcs have different ids")
+		ids := gr.ArcIds(1, 2)
+		expectEquals(t, len(ids), 2)
+		expectEquals(t, ids[0], id1)
+		expectEquals(t, ids[1], id2)
+		conn, ok := gr.LookupConnection(id2)
+		expectTrue(t, ok, "connection exists")
+		expectEquals(t, conn, NewDirectedConnection(1, 2))
+
+		cnt := 0
+		for _, conn := range gr.IdentifiedConnectionsSeq() {
+			expectEquals(t, conn.Type, CT_DIRECTED)
+			cnt++
+		}
+		expectEquals(t, cnt, 5)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		gr := NewDirectedMultiMap()
+		id1 := gr.InsertArc(1, 2)
+		id2 := gr.InsertArc(1, 2)
+		gr.AddArc(3, 3)
+		gr.AddArc(3, 1)
+
+		gr.RemoveConnection(id1)
+		expectEquals(t, gr.ArcMultiplicity(1, 2), 1)
+		expectErrorIs(t, gr.TryRemoveConnection(id1), ErrConnectionNotFound)
+		_, ok := gr.LookupConnection(id1)
+		expectFalse(t, ok, "removed connection exists")
+
+		gr.RemoveArc(1, 2)
+		_, ok = gr.LookupConnection(id2)
+		expectFalse(t, ok, "removed connection exists")
+		expectFalse(t, gr.CheckArc(1, 2), "arc 1->2")
+		expectErrorIs(t, gr.TryRemoveArc(1, 2), ErrConnectionNotFound)
+		expectVertexesExactly(t, CollectVertexes(gr.GetSinks()), 1, 2)
+
+		gr.RemoveNode(3)
+		expectEquals(t, gr.ArcsCnt(), 0)
+		expectEquals(t, len(CollectVertexes(gr.GetPredecessors(1))), 0)
+	})
+}
+
+func TestUndirectedMultiMap(t *testing.T) {
+	gr := NewUndirectedMultiMap()
+	gr.AddEdge(1, 2)
+	gr.AddEdge(2, 1)
+	loop := gr.InsertEdge(3, 3)
+	gr.AddEdge(2, 3)
+
+	expectEquals(t, gr.EdgesCnt(), 4)
+	expectEquals(t, gr.EdgeMultiplicity(1, 2), 2)
+	expectEquals(t, gr.EdgeMultiplicity(2, 1), 2)
+	expectEquals(t, gr.EdgeMultiplicity(3, 3), 1)
+	expectEquals(t, len(gr.EdgeIds(2, 1)), 2)
+	expectVertexesExactly(t, CollectVertexes(gr.GetNeighbours(3)), 2, 3)
+	expectEquals(t, countConnections(gr.EdgesSeq()), 4)
+
+	gr.RemoveConnection(loop)
+	expectFalse(t, gr.CheckEdge(3, 3), "loop exists")
+	gr.RemoveNode(2)
+	expectEquals(t, gr.EdgesCnt(), 0)
+	expectEquals(t, gr.Order(), 2)
+}
+
+func TestMixedMultiMap(t *testing.T) {
+	gr := NewMixedMultiMap()
+	gr.AddArc(1, 2)
+	gr.AddArc(2, 1)
+	edge := gr.InsertEdge(1, 2)
+	gr.AddEdge(2, 3)
+
+	expectEquals(t, gr.ConnectionsCnt(), 4)
+	expectEquals(t, gr.CheckEdgeType(1, 2), CT_UNDIRECTED)
+	expectEquals(t, gr.CheckEdgeType(1, 3), CT_NONE)
+	expectEquals(t, countConnections(gr.ArcsSeq()), 2)
+	expectEquals(t, countConnections(gr.EdgesSeq()), 2)
+
+	gr.RemoveConnection(edge)
+	expectEquals(t, gr.CheckEdgeType(1, 2), CT_DIRECTED)
+	gr.RemoveArc(1, 2)
+	expectEquals(t, gr.CheckEdgeType(1, 2), CT_DIRECTED_REVERSED)
+	expectVertexesExactly(t, CollectVertexes(gr.GetSources()), 2, 3)
+
+	typed := 0
+	for conn := range gr.TypedConnectionsSeq() {
+		expectTrue(t, conn.Type==CT_DIRECTED || conn.Type==CT_UNDIRECTED, "connection type")
+		typed++
+	}
+	expectEquals(t, typed, 2)
+}
@@ -2,81 +2,82 @@ package graph
 
 import (
 	"testing"
-	"github.com/orfjackal/gospec/src/gospec"
-	. "github.com/orfjackal/gospec/src/gospec"
 )
 
-func UndirectedGraphSpec(c gospec.Context, graphCreator func() UndirectedGraph) {
-	gr := graphCreator()
-
-	c.Specify("Empty undirected graph", func() {
-		c.Specify("contain no nodes", func() {
-			c.Expect(gr.Order(), Equals, 0)
+func UndirectedGraphSpec(t *testing.T, graphCreator func() UndirectedGraph) {
+	t.Run("Empty undirected graph", func(t *testing.T) {
+		gr := graphCreator()
+		t.Run("contain no nodes", func(t *testing.T) {
+			expectEquals(t, gr.Order(), 0)
 		})
-		c.Specify("contain no edges", func() {
-			c.Expect(gr.EdgesCnt(), Equals, 0)
+		t.Run("contain no edges", func(t *testing.T) {
+			expectEquals(t, gr.EdgesCnt(), 0)
 		})
 	})
 
-	
-	c.Specify("New node in empty graph", func() {
-		VertexId := VertexId(1)
-		gr.AddNode(VertexId)
-				
-		c.Specify("changing nodes count", func() {
-			c.Expect(gr.Order(), Equals, 1)
+	t.Run("New node in empty graph", func(t *testing.T) {
+		gr := graphCreator()
+		vertexId := VertexId(1)
+		gr.AddNode(vertexId)
+
+		t.Run("changing nodes count", func(t *testing.T) {
+			expectEquals(t, gr.Order(), 1)
 		})
-		
-		c.Specify("doesn't change edges count", func() {
-			c.Expect(gr.EdgesCnt(), Equals, 0)
+
+		t.Run("doesn't change edges count", func(t *testing.T) {
+			expectEquals(t, gr.EdgesCnt(), 0)
 		})
-		
-		c.Specify("no neighbours", func() {
-			c.Expect(len(CollectVertexes(gr.GetNeighbours(VertexId))), Equals, 0)
+
+		t.Run("no neighbours", func(t *testing.T) {
+			expectEquals(t, len(CollectVertexes(gr.GetNeighbours(vertexId))), 0)
 		})
 	})
 
-	c.Specify("New edge in empty graph", func() {
+	t.Run("New edge in empty graph", func(t *testing.T) {
+		gr := graphCreator()
 		n1 := VertexId(1)
 		n2 := VertexId(2)
 		gr.AddEdge(n1, n2)
-				
-		c.Specify("changing nodes count", func() {
-			c.Expect(gr.Order(), Equals, 2)
+
+		t.Run("changing nodes count", func(t *testing.T) {
+			expectEquals(t, gr.Order(), 2)
 		})
-		
-		c.Specify("changing edges count", func() {
-			c.Expect(gr.EdgesCnt(), Equals, 1)
+
+		t.Run("changing edges count", func(t *testing.T) {
+			expectEquals(t, gr.EdgesCnt(), 1)
 		})
-		
-		c.Specify("neighbours", func() {
-			c.Expect(CollectVertexes(gr.GetNeighbours(n1)), ContainsExactly, Values(n2))
-			c.Expect(CollectVertexes(gr.GetNeighbours(n2)), ContainsExactly, Values(n1))
+
+		t.Run("neighbours", func(t *testing.T) {
+			expectVertexesExactly(t, CollectVertexes(gr.GetNeighbours(n1)), n2)
+			expectVertexesExactly(t, CollectVertexes(gr.GetNeighbours(n2)), n1)
 		})
 	})
 }
 
 func TestUndirectedGraphSpec(t *testing.T) {
-	r := gospec.NewRunner()
-	
-	// paramenerized test creator
-	cr := func(graphCreator func() UndirectedGraph) func (c gospec.Context) {
-		return func(c gospec.Context){
-			UndirectedGraphSpec(c, graphCreator)
-		}
-	}
-	
-	r.AddNamedSpec("UndirectedGraph(Map)", cr(func() UndirectedGraph {
-		return UndirectedGraph(NewUndirectedMap())
-	}))
-	r.AddNamedSpec("UndirectedGraph(Matrix)", cr(func() UndirectedGraph {
-		return UndirectedGraph(NewUndirectedMatrix(10))
-	}))
-	r.AddNamedSpec("UndirectedGraph(MixedMatrix)", cr(func() UndirectedGraph {
-		return UndirectedGraph(NewMixedMatrix(10))
-	}))
-	r.AddNamedSpec("UndirectedGraph(MixedMap)", cr(func() UndirectedGraph {
-		return UndirectedGraph(NewMixedMap())
-	}))
-	gospec.MainGoTest(r, t)
+	t.Run("UndirectedGraph(Map)", func(t *testing.T) {
+		UndirectedGraphSpec(t, func() UndirectedGraph {
+			return UndirectedGraph(NewUndirectedMap())
+		})
+	})
+	t.Run("UndirectedGraph(Matrix)", func(t *testing.T) {
+		UndirectedGraphSpec(t, func() UndirectedGraph {
+			return UndirectedGraph(NewUndirectedMatrix(10))
+		})
+	})
+	t.Run("UndirectedGraph(MixedMatrix)", func(t *testing.T) {
+		UndirectedGraphSpec(t, func() UndirectedGraph {
+			return UndirectedGraph(NewMixedMatrix(10))
+		})
+	})
+	t.Run("UndirectedGraph(MixedMap)", func(t *testing.T) {
+		UndirectedGraphSpec(t, func() UndirectedGraph {
+			return UndirectedGraph(NewMixedMap())
+		})
+	})
+	t.Run("UndirectedGraph(MultiMap)", func(t *testing.T) {
+		UndirectedGraphSpec(t, func() UndirectedGraph {
+			return UndirectedGraph(NewUndirectedMultiMap())
+		})
+	})
 }
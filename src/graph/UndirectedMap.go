@@ -1,7 +1,9 @@
 package graph
 
 import (
-	"github.com/StepLg/go-erx/src/erx"
+	"iter"
+
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 type UndirectedMap struct {
@@ -23,18 +25,25 @@ func (g *UndirectedMap) ConnectionsIter() <-chan Connection {
 	return g.EdgesIter()
 }
 
+func (g *UndirectedMap) ConnectionsSeq() iter.Seq[Connection] {
+	return g.EdgesSeq()
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // VertexesIterable
 
 func (g *UndirectedMap) VertexesIter() <-chan VertexId {
-	ch := make(chan VertexId)
-	go func() {
+	return seqToChan(g.VertexesSeq())
+}
+
+func (g *UndirectedMap) VertexesSeq() iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
 		for from, _ := range g.edges {
-			ch <- from
+			if !yield(from) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -50,41 +59,53 @@ func (g *UndirectedMap) CheckNode(node VertexId) (exists bool) {
 
 // Adding single node to graph
 func (g *UndirectedMap) AddNode(node VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Add node to graph.", err, 1)
-		res.AddV("node id", node)
-		return
+	if err := g.TryAddNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Add node to graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
 	}
+}
 
+// Adding single node to graph
+//
+// Returns ErrNodeExists if node is already in graph.
+func (g *UndirectedMap) TryAddNode(node VertexId) error {
 	if _, ok := g.edges[node]; ok {
-		panic(makeError(erx.NewError("Node already exists.")))
+		return &VertexError{Op: "add node", Node: node, Err: ErrNodeExists}
 	}
 	
 	g.edges[node] = make(map[VertexId]bool)
 
-	return	
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // GraphVertexesRemover
 
 func (g *UndirectedMap) RemoveNode(node VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Remove node from graph.", err, 1)
-		res.AddV("node id", node)
-		return
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
 	}
+}
 
-	if _, ok := g.edges[node]; !ok {
-		panic(makeError(erx.NewError("Node doesn't exist.")))
+// Removing node with all it's edges from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *UndirectedMap) TryRemoveNode(node VertexId) error {
+	neighbours, ok := g.edges[node]
+	if !ok {
+		return &VertexError{Op: "remove node", Node: node, Err: ErrNodeNotFound}
 	}
 	
-	g.edges[node] = nil, false
+	g.edgesCnt -= len(neighbours)
+	delete(g.edges, node)
 	for _, connectedVertexes := range g.edges {
-		connectedVertexes[node] = false, false
+		delete(connectedVertexes, node)
 	}
 	
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -98,25 +119,31 @@ func (g *UndirectedMap) touchNode(node VertexId) {
 
 // Adding arrow to graph.
 func (g *UndirectedMap) AddEdge(from, to VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Add edge to graph.", err, 1)
-		res.AddV("node 1", from)
-		res.AddV("node 2", to)
-		return
+	if err := g.TryAddEdge(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Add edge to graph.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		panic(erxErr)
 	}
+}
 
-	g.touchNode(from)
-	g.touchNode(to)
-	
+// Adding edge to graph.
+//
+// Nodes are created if they don't exist. Returns ErrDuplicateConnection if
+// edge already exists.
+func (g *UndirectedMap) TryAddEdge(from, to VertexId) error {
 	if direction, ok := g.edges[from][to]; ok && direction {
-		panic(makeError(erx.NewError("Duplicate arrow.")))
+		return &ConnectionError{Op: "add edge", Tail: from, Head: to, Err: ErrDuplicateConnection}
 	}
 	
+	g.touchNode(from)
+	g.touchNode(to)
+	
 	g.edges[from][to] = true
 	g.edges[to][from] = true
 	g.edgesCnt++	
 
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -124,26 +151,37 @@ func (g *UndirectedMap) AddEdge(from, to VertexId) {
 
 // Removing arrow  'from' and 'to' nodes
 func (g *UndirectedMap) RemoveEdge(from, to VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Remove edge from graph.", err, 1)
-		res.AddV("node 1", from)
-		res.AddV("node 2", to)
-		return
+	if err := g.TryRemoveEdge(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove edge from graph.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		panic(erxErr)
 	}
+}
+
+// Removing edge between 'from' and 'to' nodes
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such edge.
+func (g *UndirectedMap) TryRemoveEdge(from, to VertexId) error {
 	connectedVertexes, ok := g.edges[from]
 	if !ok {
-		panic(makeError(erx.NewError("First node doesn't exists")))
+		return &VertexError{Op: "remove edge", Node: from, Err: ErrNodeNotFound}
+	}
+	
+	if _, ok = g.edges[to]; !ok {
+		return &VertexError{Op: "remove edge", Node: to, Err: ErrNodeNotFound}
 	}
 	
-	if _, ok = connectedVertexes[to]; ok {
-		panic(makeError(erx.NewError("Second node doesn't exists")))
+	if _, ok = connectedVertexes[to]; !ok {
+		return &ConnectionError{Op: "remove edge", Tail: from, Head: to, Err: ErrConnectionNotFound}
 	}
 	
-	g.edges[from][to] = false, false
-	g.edges[to][from] = false, false
+	delete(g.edges[from], to)
+	delete(g.edges[to], from)
 	g.edgesCnt--
 
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -157,24 +195,23 @@ func (g *UndirectedMap) EdgesCnt() int {
 	return g.edgesCnt
 }
 
-// Getting node predecessors
+// Getting node neighbours
 func (g *UndirectedMap) GetNeighbours(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		go func() {
-			if connectedMap, ok := g.edges[node]; ok {
-				for VertexId, _ := range connectedMap {
-					ch <- VertexId
-				}
-			} else {
-				panic(erx.NewError("Node doesn't exists."))
+	iterator := func(yield func(VertexId) bool) {
+		connectedMap, ok := g.edges[node]
+		if !ok {
+			err := erx.NewSequent("Get node neighbours in undirected graph.", erx.NewError("Node doesn't exists."))
+			err.AddV("node", node)
+			panic(err)
+		}
+		for VertexId, _ := range connectedMap {
+			if !yield(VertexId) {
+				return
 			}
-			close(ch)
-		}()
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 func (g *UndirectedMap) CheckEdge(from, to VertexId) (isExist bool) {
@@ -200,18 +237,21 @@ func (g *UndirectedMap) CheckEdge(from, to VertexId) (isExist bool) {
 }
 
 func (g *UndirectedMap) EdgesIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
+	return seqToChan(g.EdgesSeq())
+}
+
+func (g *UndirectedMap) EdgesSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
 		for from, connectedVertexes := range g.edges {
 			for to, _ := range connectedVertexes {
 				if from<to {
 					// each edge has a duplicate, so we need to 
-					// push only one edge to channel
-					ch <- Connection{from, to}
+					// yield only one edge
+					if !yield(Connection{from, to}) {
+						return
+					}
 				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
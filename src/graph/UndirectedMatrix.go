@@ -1,44 +1,71 @@
 package graph
 
 import (
-	"github.com/StepLg/go-erx/src/erx"
+	"iter"
+
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 // Undirected graph with matrix as a internal representation.
 //
 // Doesn't allow duplicate edges and arcs, loops and reversed arcs.
-// Graph can't have more than size vertexes, where size set during initialization.
-// UndirectedMatrix use over (size^2/2) bytes.
+// Matrix grows when there is no space for new vertex, places of removed
+// vertexes are reused. UndirectedMatrix use over (size^2/2) bytes, where size
+// is current capacity.
 type UndirectedMatrix struct {
+	matrixIds
 	nodes []bool
-	size int
-	VertexIds map[VertexId]int // internal node ids, used in nodes array
 	edgesCnt int
 }
 
 // Creating new undirected graph with matrix storage.
 //
-// size means maximum number of nodes, used in graph. Trying to add
-// more nodes, than this size will cause an error. 
+// size means initial number of nodes, which graph can handle without
+// reallocation.
 func NewUndirectedMatrix(size int) *UndirectedMatrix {
-	if size<=0 {
+	if size<0 {
 		return nil
 	}
 	g := new(UndirectedMatrix)
+	g.matrixIds = newMatrixIds(size)
 	g.nodes = make([]bool, size*(size-1)/2)
-	g.size = size
-	g.VertexIds = make(map[VertexId]int)
 	g.edgesCnt = 0
 	return g
 }
 
-// Maximum graph capacity
+// Current graph capacity
 //
-// Maximum nodes count graph can handle
+// Maximum nodes count graph can handle without reallocation
 func (g *UndirectedMatrix) GetCapacity() int {
 	return int(g.size)
 }
 
+// Shrinking matrix to current nodes count.
+//
+// Internal ids of nodes are changed, so VertexIds shouldn't be used across
+// Compact call.
+func (g *UndirectedMatrix) Compact() {
+	oldNodes := g.nodes
+	mapping := g.compactIds()
+	g.nodes = make([]bool, g.size*(g.size-1)/2)
+	for oldId1, newId1 := range mapping {
+		for oldId2, newId2 := range mapping {
+			if oldId1<oldId2 && oldNodes[matrixTriangleIndex(oldId1, oldId2)] {
+				g.nodes[matrixTriangleIndex(newId1, newId2)] = true
+			}
+		}
+	}
+}
+
+// Getting internal node id, creating node if it doesn't exist.
+func (g *UndirectedMatrix) touchNode(node VertexId) int {
+	id, grown := g.allocId(node)
+	if grown {
+		g.nodes = append(g.nodes, make([]bool, g.size*(g.size-1)/2 - len(g.nodes))...)
+	}
+	return id
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // ConnectionsIterable
 
@@ -46,18 +73,25 @@ func (g *UndirectedMatrix) ConnectionsIter() <-chan Connection {
 	return g.EdgesIter()
 }
 
+func (g *UndirectedMatrix) ConnectionsSeq() iter.Seq[Connection] {
+	return g.EdgesSeq()
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // VertexesIterable
 
 func (g *UndirectedMatrix) VertexesIter() <-chan VertexId {
-	ch := make(chan VertexId)
-	go func() {
+	return seqToChan(g.VertexesSeq())
+}
+
+func (g *UndirectedMatrix) VertexesSeq() iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
 		for VertexId, _ := range g.VertexIds {
-			ch <- VertexId
+			if !yield(VertexId) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -73,26 +107,55 @@ func (g *UndirectedMatrix) CheckNode(node VertexId) (exists bool) {
 
 // Adding single node to graph
 func (g *UndirectedMatrix) AddNode(node VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Add node to graph.", err, 1)
-		res.AddV("node id", node)
-		return
+	if err := g.TryAddNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Add node to graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
 	}
+}
 
+// Adding single node to graph
+//
+// Returns ErrNodeExists if node is already in graph.
+func (g *UndirectedMatrix) TryAddNode(node VertexId) error {
 	if _, ok := g.VertexIds[node]; ok {
-		panic(makeError(erx.NewError("Node already exists.")))
+		return &VertexError{Op: "add node", Node: node, Err: ErrNodeExists}
 	}
 	
-	g.VertexIds[node] = len(g.VertexIds)
+	g.touchNode(node)
 
-	return	
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 // GraphVertexesRemover
 
+// Removing node with all it's edges from graph
 func (g *UndirectedMatrix) RemoveNode(node VertexId) {
-	panic(erx.NewError("Function doesn't implemented yet."))
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Removing node with all it's edges from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *UndirectedMatrix) TryRemoveNode(node VertexId) error {
+	id, ok := g.VertexIds[node]
+	if !ok {
+		return &VertexError{Op: "remove node", Node: node, Err: ErrNodeNotFound}
+	}
+	
+	for _, otherId := range g.VertexIds {
+		if otherId!=id && g.nodes[matrixTriangleIndex(id, otherId)] {
+			g.nodes[matrixTriangleIndex(id, otherId)] = false
+			g.edgesCnt--
+		}
+	}
+	g.releaseId(node)
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -100,34 +163,31 @@ func (g *UndirectedMatrix) RemoveNode(node VertexId) {
 
 // Adding new edge to graph
 func (g *UndirectedMatrix) AddEdge(node1, node2 VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Add edge to graph.", err, 1)
-		res.AddV("node 1", node1)
-		res.AddV("node 2", node2)
-		return
+	if err := g.TryAddEdge(node1, node2); err!=nil {
+		erxErr := erx.NewSequentLevel("Add edge to graph.", err, 1)
+		erxErr.AddV("node 1", node1)
+		erxErr.AddV("node 2", node2)
+		panic(erxErr)
 	}
-	
-	defer func() {
-		// warning! such code generates wrong file/line info about error!
-		// see http://groups.google.com/group/golang-nuts/browse_thread/thread/66bd57dcdac63aa
-		// for details
-		if err := recover(); err!=nil {
-			panic(makeError(err))
-		}
-	}()
+}
 
-	var conn int
-	conn = g.getConnectionId(node1, node2, true)
+// Adding new edge to graph
+//
+// Nodes are created if they don't exist. Returns ErrLoop if node1==node2 and
+// ErrDuplicateConnection if edge already exists.
+func (g *UndirectedMatrix) TryAddEdge(node1, node2 VertexId) error {
+	conn, err := g.tryGetConnectionId("add edge", node1, node2, true)
+	if err!=nil {
+		return err
+	}
 	
 	if g.nodes[conn] {
-		err := erx.NewError("Duplicate edge.")
-		err.AddV("connection id", conn)
-		panic(makeError(err))
+		return &ConnectionError{Op: "add edge", Tail: node1, Head: node2, Err: ErrDuplicateConnection}
 	}
 	g.nodes[conn] = true
 	g.edgesCnt++
 	
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -135,33 +195,32 @@ func (g *UndirectedMatrix) AddEdge(node1, node2 VertexId) {
 
 // Removing edge, connecting node1 and node2
 func (g *UndirectedMatrix) RemoveEdge(node1, node2 VertexId) {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Remove edge from graph.", err, 1)
-		res.AddV("node 1", node1)
-		res.AddV("node 2", node2)
-		return
+	if err := g.TryRemoveEdge(node1, node2); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove edge from graph.", err, 1)
+		erxErr.AddV("node 1", node1)
+		erxErr.AddV("node 2", node2)
+		panic(erxErr)
 	}
-	
-	defer func() {
-		// warning! such code generates wrong file/line info about error!
-		// see http://groups.google.com/group/golang-nuts/browse_thread/thread/66bd57dcdac63aa
-		// for details
-		if err := recover(); err!=nil {
-			panic(makeError(err))
-		}
-	}()
+}
 
-	
-	conn := g.getConnectionId(node1, node2, false)
+// Removing edge, connecting node1 and node2
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such edge.
+func (g *UndirectedMatrix) TryRemoveEdge(node1, node2 VertexId) error {
+	conn, err := g.tryGetConnectionId("remove edge", node1, node2, false)
+	if err!=nil {
+		return err
+	}
 	
 	if (!g.nodes[conn]) {
-		panic(erx.NewError("Edge doesn't exist."))
+		return &ConnectionError{Op: "remove edge", Tail: node1, Head: node2, Err: ErrConnectionNotFound}
 	}
 	
 	g.nodes[conn] = false
 	g.edgesCnt--
 	
-	return
+	return nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -180,47 +239,47 @@ func (g *UndirectedMatrix) EdgesCnt() int {
 
 // Getting all nodes, connected to given one
 func (g *UndirectedMatrix) GetNeighbours(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		go func() {
+	iterator := func(yield func(VertexId) bool) {
+		if _, ok := g.VertexIds[node]; !ok {
+			err := erx.NewSequent("Get node neighbours in undirected graph.", erx.NewError("Unknown node."))
+			err.AddV("node", node)
+			panic(err)
+		}
 
-			if _, ok := g.VertexIds[node]; !ok {
-				panic(erx.NewError("Unknown node."))
+		var connId int
+		for aNode, _ := range g.VertexIds {
+			if aNode==node {
+				continue
 			}
-
-			var connId int
-			for aNode, _ := range g.VertexIds {
-				if aNode==node {
-					continue
-				}
-				connId= g.getConnectionId(node, aNode, false)
-				
-				if g.nodes[connId] {
-					ch <- aNode
+			connId= g.getConnectionId(node, aNode, false)
+			
+			if g.nodes[connId] {
+				if !yield(aNode) {
+					return
 				}
 			}
-	
-			close(ch)
-		}()
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 func (g *UndirectedMatrix) EdgesIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
+	return seqToChan(g.EdgesSeq())
+}
+
+func (g *UndirectedMatrix) EdgesSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
 		for from, _ := range g.VertexIds {
 			for to, _ := range g.VertexIds {
 				if from<to && g.CheckEdge(from, to) {
-					ch <- Connection{from, to}
+					if !yield(Connection{from, to}) {
+						return
+					}
 				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 func (g *UndirectedMatrix) CheckEdge(node1, node2 VertexId) bool {
@@ -240,69 +299,33 @@ func (g *UndirectedMatrix) CheckEdge(node1, node2 VertexId) bool {
 }
 
 func (g *UndirectedMatrix) getConnectionId(node1, node2 VertexId, create bool) int {
-	makeError := func(err interface{}) (res erx.Error) {
-		res = erx.NewSequentLevel("Calculating connection id.", err, 1)
-		res.AddV("node 1", node1)
-		res.AddV("node 2", node2)
-		return
-	}
-	
-	defer func() {
-		// warning! such code generates wrong file/line info about error!
-		// see http://groups.google.com/group/golang-nuts/browse_thread/thread/66bd57dcdac63aa
-		// for details
-		if err := recover(); err!=nil {
-			panic(makeError(err))
-		}
-	}()
-	
-	var id1, id2 int
-	node1Exist := false
-	node2Exist := false
-	id1, node1Exist = g.VertexIds[node1]
-	id2, node2Exist = g.VertexIds[node2]
-	
-	// checking for errors
-	{
-		if node1==node2 {
-			panic(makeError(erx.NewError("Equal nodes.")))
-		}
-		if !create {
-			if !node1Exist {
-				panic(makeError(erx.NewError("First node doesn't exist in graph")))
-			}
-			if !node2Exist {
-				panic(makeError(erx.NewError("Second node doesn't exist in graph")))
-			}
-		} else if !node1Exist || !node2Exist {
-			if node1Exist && node2Exist {
-				if g.size - len(g.VertexIds) < 2 {
-					panic(makeError(erx.NewError("Not enough space to create two new nodes.")))
-				}
-			} else {
-				if g.size - len(g.VertexIds) < 1 {
-					panic(makeError(erx.NewError("Not enough space to create new node.")))
-				}
-			}
-		}
-	}
-	
-	if !node1Exist {
-		id1 = int(len(g.VertexIds))
-		g.VertexIds[node1] = id1
+	connId, err := g.tryGetConnectionId("get connection", node1, node2, create)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Calculating connection id.", err, 1)
+		erxErr.AddV("node 1", node1)
+		erxErr.AddV("node 2", node2)
+		panic(erxErr)
 	}
+	return connId
+}
 
-	if !node2Exist {
-		id2 = int(len(g.VertexIds))
-		g.VertexIds[node2] = id2
+// Calculating connection id.
+//
+// op is an operation name for returned errors. Nodes are created if create
+// flag is set.
+func (g *UndirectedMatrix) tryGetConnectionId(op string, node1, node2 VertexId, create bool) (int, error) {
+	if node1==node2 {
+		return 0, &ConnectionError{Op: op, Tail: node1, Head: node2, Err: ErrLoop}
 	}
 	
-	// switching id1, id2 in order to id1 < id2
-	if id1>id2 {
-		id1, id2 = id2, id1
+	if !create {
+		if !g.CheckNode(node1) {
+			return 0, &VertexError{Op: op, Node: node1, Err: ErrNodeNotFound}
+		}
+		if !g.CheckNode(node2) {
+			return 0, &VertexError{Op: op, Node: node2, Err: ErrNodeNotFound}
+		}
 	}
 	
-	// id from upper triangle matrix, stored in vector
-	connId := id1*(g.size-1) + id2 - 1 - id1*(id1+1)/2
-	return connId 
+	return matrixTriangleIndex(g.touchNode(node1), g.touchNode(node2)), nil
 }
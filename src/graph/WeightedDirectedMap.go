@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Weight of connections, added to weighted graph without explicit weight.
+//
+// It's the same weight, as SimpleWeightFunc returns.
+const DefaultConnectionWeight = float64(1.0)
+
+// Directed graph with map as a internal representation and float64 weight
+// for each arc.
+//
+// Arcs, added with AddArc, have DefaultConnectionWeight.
+type WeightedDirectedMap struct {
+	*DirectedMap
+	weights map[Connection]float64
+}
+
+func NewWeightedDirectedMap() *WeightedDirectedMap {
+	return &WeightedDirectedMap{
+		DirectedMap: NewDirectedMap(),
+		weights: make(map[Connection]float64),
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesRemover
+
+// Removing node with all it's arcs and their weights from graph
+func (g *WeightedDirectedMap) RemoveNode(node VertexId) {
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Removing node with all it's arcs and their weights from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *WeightedDirectedMap) TryRemoveNode(node VertexId) error {
+	if g.CheckNode(node) {
+		for accessor := range VertexesSeqOf(g.GetAccessors(node)) {
+			delete(g.weights, Connection{node, accessor})
+		}
+		for predecessor := range VertexesSeqOf(g.GetPredecessors(node)) {
+			delete(g.weights, Connection{predecessor, node})
+		}
+	}
+	return g.DirectedMap.TryRemoveNode(node)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DirectedGraphArcsRemover
+
+// Removing arrow 'from' and 'to' nodes with it's weight
+func (g *WeightedDirectedMap) RemoveArc(from, to VertexId) {
+	if err := g.TryRemoveArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove arc from graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+}
+
+// Removing arrow 'from' and 'to' nodes with it's weight
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such arc.
+func (g *WeightedDirectedMap) TryRemoveArc(from, to VertexId) error {
+	if err := g.DirectedMap.TryRemoveArc(from, to); err!=nil {
+		return err
+	}
+	delete(g.weights, Connection{from, to})
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WeightedDirectedGraphArcsWriter
+
+// Adding arrow with weight to graph.
+func (g *WeightedDirectedMap) AddWeightedArc(from, to VertexId, weight float64) {
+	if err := g.TryAddWeightedArc(from, to, weight); err!=nil {
+		erxErr := erx.NewSequentLevel("Add weighted arc to graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		erxErr.AddV("weight", weight)
+		panic(erxErr)
+	}
+}
+
+// Adding arrow with weight to graph.
+//
+// Nodes are created if they don't exist. Returns ErrDuplicateConnection if
+// arc already exists.
+func (g *WeightedDirectedMap) TryAddWeightedArc(from, to VertexId, weight float64) error {
+	if err := g.TryAddArc(from, to); err!=nil {
+		return err
+	}
+	g.weights[Connection{from, to}] = weight
+	return nil
+}
+
+// Changing weight of existing arc.
+func (g *WeightedDirectedMap) SetArcWeight(from, to VertexId, weight float64) {
+	if err := g.TrySetArcWeight(from, to, weight); err!=nil {
+		erxErr := erx.NewSequentLevel("Set arc weight.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		erxErr.AddV("weight", weight)
+		panic(erxErr)
+	}
+}
+
+// Changing weight of existing arc.
+//
+// Returns ErrConnectionNotFound if there is no such arc.
+func (g *WeightedDirectedMap) TrySetArcWeight(from, to VertexId, weight float64) error {
+	if !g.DirectedMap.directArcs[from][to] {
+		return &ConnectionError{Op: "set arc weight", Tail: from, Head: to, Err: ErrConnectionNotFound}
+	}
+	g.weights[Connection{from, to}] = weight
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WeightedDirectedGraphArcsReader
+
+// Getting arc weight.
+func (g *WeightedDirectedMap) GetArcWeight(from, to VertexId) float64 {
+	weight, err := g.TryGetArcWeight(from, to)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Get arc weight.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+	return weight
+}
+
+// Getting arc weight.
+//
+// Returns ErrConnectionNotFound if there is no such arc.
+func (g *WeightedDirectedMap) TryGetArcWeight(from, to VertexId) (float64, error) {
+	if !g.DirectedMap.directArcs[from][to] {
+		return 0.0, &ConnectionError{Op: "get arc weight", Tail: from, Head: to, Err: ErrConnectionNotFound}
+	}
+	if weight, ok := g.weights[Connection{from, to}]; ok {
+		return weight, nil
+	}
+	return DefaultConnectionWeight, nil
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestWeightedDirectedMap(t *testing.T) {
+	newGraph := func() WeightedDirectedGraph {
+		gr := NewWeightedDirectedMap()
+		gr.AddWeightedArc(1, 2, 5.0)
+		gr.AddWeightedArc(2, 3, 1.0)
+		gr.AddWeightedArc(1, 3, 10.0)
+		gr.AddArc(3, 4)
+		return gr
+	}
+
+	t.Run("Weights", func(t *testing.T) {
+		gr := newGraph()
+		expectEquals(t, gr.GetArcWeight(1, 2), 5.0)
+		expectEquals(t, gr.GetArcWeight(3, 4), DefaultConnectionWeight)
+		gr.SetArcWeight(1, 2, 0.5)
+		expectEquals(t, gr.GetArcWeight(1, 2), 0.5)
+		expectPanic(t, "reversed arc weight", func() { gr.GetArcWeight(2, 1) })
+		expectPanic(t, "unexistent arc weight", func() { gr.SetArcWeight(4, 1, 1.0) })
+	})
+
+	t.Run("Weight is removed with arc", func(t *testing.T) {
+		gr := newGraph()
+		gr.RemoveArc(1, 2)
+		gr.AddArc(1, 2)
+		expectEquals(t, gr.GetArcWeight(1, 2), DefaultConnectionWeight)
+	})
+
+	t.Run("Weight is removed with node", func(t *testing.T) {
+		gr := newGraph()
+		gr.RemoveNode(3)
+		gr.AddArc(1, 3)
+		expectEquals(t, gr.GetArcWeight(1, 3), DefaultConnectionWeight)
+		expectEquals(t, gr.ArcsCnt(), 2)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		gr := newGraph()
+		weight, exists := CheckPathDijkstra(NewDgraphOutNeighboursExtractor(gr), 1, 3, nil, ArcWeightFunc(gr))
+		expectTrue(t, exists, "path exists")
+		expectEquals(t, weight, 6.0)
+
+		marks := BellmanFordSingleSource(gr, 1, ArcWeightFunc(gr))
+		expectEquals(t, marks[4].Weight, 7.0)
+		expectPath(t, PathFromMarks(marks, 4), 1, 2, 3, 4)
+	})
+}
+
+func TestWeightedUndirectedMap(t *testing.T) {
+	gr := NewWeightedUndirectedMap()
+	gr.AddWeightedEdge(1, 2, 2.0)
+	gr.AddWeightedEdge(3, 2, 3.0)
+	gr.AddWeightedEdge(1, 3, 7.0)
+
+	expectEquals(t, gr.GetEdgeWeight(2, 1), 2.0)
+	expectEquals(t, gr.GetEdgeWeight(2, 3), 3.0)
+
+	weight, exists := CheckPathDijkstra(NewUgraphOutNeighboursExtractor(gr), 3, 1, nil, EdgeWeightFunc(gr))
+	expectTrue(t, exists, "path exists")
+	expectEquals(t, weight, 5.0)
+
+	gr.RemoveEdge(2, 3)
+	expectPanic(t, "removed edge weight", func() { gr.GetEdgeWeight(3, 2) })
+	gr.AddEdge(3, 2)
+	expectEquals(t, gr.GetEdgeWeight(3, 2), DefaultConnectionWeight)
+}
+
+func TestWeightedMixedMap(t *testing.T) {
+	var gr WeightedMixedGraph = NewWeightedMixedMap()
+	gr.AddWeightedArc(1, 2, 1.0)
+	gr.AddWeightedEdge(2, 3, 1.0)
+	gr.AddWeightedArc(1, 3, 5.0)
+	gr.AddWeightedArc(3, 4, 2.0)
+
+	expectEquals(t, gr.GetConnectionWeight(3, 2), 1.0)
+	expectEquals(t, gr.GetConnectionWeight(1, 3), 5.0)
+	expectPanic(t, "reversed arc weight", func() { gr.GetConnectionWeight(3, 1) })
+	expectPanic(t, "edge as arc weight", func() { gr.GetArcWeight(2, 3) })
+
+	weight, exists := CheckPathDijkstra(NewMgraphOutNeighboursExtractor(gr), 1, 4, nil, MixedWeightFunc(gr))
+	expectTrue(t, exists, "path exists")
+	expectEquals(t, weight, 4.0)
+
+	gr.RemoveNode(3)
+	gr.AddEdge(2, 3)
+	expectEquals(t, gr.GetEdgeWeight(2, 3), DefaultConnectionWeight)
+}
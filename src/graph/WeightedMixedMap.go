@@ -0,0 +1,286 @@
+package graph
+
+import (
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Mixed graph with map as a internal representation and float64 weight
+// for each arc and edge.
+//
+// Connections, added with AddArc or AddEdge, have DefaultConnectionWeight.
+type WeightedMixedMap struct {
+	*MixedMap
+	// Arcs are stored as is and edges with tail not greater than head.
+	// Mixed graph doesn't allow several connections between two nodes, so
+	// there are no collisions.
+	weights map[Connection]float64
+}
+
+func NewWeightedMixedMap() *WeightedMixedMap {
+	return &WeightedMixedMap{
+		MixedMap: NewMixedMap(),
+		weights: make(map[Connection]float64),
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesRemover
+
+// Removing node with all it's connections and their weights from graph
+func (g *WeightedMixedMap) RemoveNode(node VertexId) {
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Removing node with all it's connections and their weights from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *WeightedMixedMap) TryRemoveNode(node VertexId) error {
+	if g.CheckNode(node) {
+		for accessor := range VertexesSeqOf(g.GetAccessors(node)) {
+			delete(g.weights, Connection{node, accessor})
+		}
+		for predecessor := range VertexesSeqOf(g.GetPredecessors(node)) {
+			delete(g.weights, Connection{predecessor, node})
+		}
+		for neighbour := range VertexesSeqOf(g.GetNeighbours(node)) {
+			delete(g.weights, NewUndirectedConnection(node, neighbour).Connection)
+		}
+	}
+	return g.MixedMap.TryRemoveNode(node)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// DirectedGraphArcsRemover
+
+// Removing arrow 'from' and 'to' nodes with it's weight
+func (g *WeightedMixedMap) RemoveArc(from, to VertexId) {
+	if err := g.TryRemoveArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove arc from graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+}
+
+// Removing arrow 'from' and 'to' nodes with it's weight
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such arc.
+func (g *WeightedMixedMap) TryRemoveArc(from, to VertexId) error {
+	if err := g.MixedMap.TryRemoveArc(from, to); err!=nil {
+		return err
+	}
+	delete(g.weights, Connection{from, to})
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// UndirectedGraphEdgesRemover
+
+// Removing edge between 'from' and 'to' nodes with it's weight
+func (g *WeightedMixedMap) RemoveEdge(from, to VertexId) {
+	if err := g.TryRemoveEdge(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Removing edge from graph.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		panic(erxErr)
+	}
+}
+
+// Removing edge between 'from' and 'to' nodes with it's weight
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such edge.
+func (g *WeightedMixedMap) TryRemoveEdge(from, to VertexId) error {
+	if err := g.MixedMap.TryRemoveEdge(from, to); err!=nil {
+		return err
+	}
+	delete(g.weights, NewUndirectedConnection(from, to).Connection)
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WeightedDirectedGraphArcsWriter
+
+// Adding arrow with weight to graph.
+func (g *WeightedMixedMap) AddWeightedArc(from, to VertexId, weight float64) {
+	if err := g.TryAddWeightedArc(from, to, weight); err!=nil {
+		erxErr := erx.NewSequentLevel("Add weighted arc to graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		erxErr.AddV("weight", weight)
+		panic(erxErr)
+	}
+}
+
+// Adding arrow with weight to graph.
+//
+// Nodes are created if they don't exist. Returns ErrLoop if from==to and
+// ErrDuplicateConnection if nodes are already connected.
+func (g *WeightedMixedMap) TryAddWeightedArc(from, to VertexId, weight float64) error {
+	if err := g.TryAddArc(from, to); err!=nil {
+		return err
+	}
+	g.weights[Connection{from, to}] = weight
+	return nil
+}
+
+// Changing weight of existing arc.
+func (g *WeightedMixedMap) SetArcWeight(from, to VertexId, weight float64) {
+	if err := g.TrySetArcWeight(from, to, weight); err!=nil {
+		erxErr := erx.NewSequentLevel("Set arc weight.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		erxErr.AddV("weight", weight)
+		panic(erxErr)
+	}
+}
+
+// Changing weight of existing arc.
+//
+// Returns ErrConnectionNotFound if there is no such arc.
+func (g *WeightedMixedMap) TrySetArcWeight(from, to VertexId, weight float64) error {
+	if g.MixedMap.connections[from][to]!=CT_DIRECTED {
+		return &ConnectionError{Op: "set arc weight", Tail: from, Head: to, Err: ErrConnectionNotFound}
+	}
+	g.weights[Connection{from, to}] = weight
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WeightedDirectedGraphArcsReader
+
+// Getting arc weight.
+func (g *WeightedMixedMap) GetArcWeight(from, to VertexId) float64 {
+	weight, err := g.TryGetArcWeight(from, to)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Get arc weight.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+	return weight
+}
+
+// Getting arc weight.
+//
+// Returns ErrConnectionNotFound if there is no such arc.
+func (g *WeightedMixedMap) TryGetArcWeight(from, to VertexId) (float64, error) {
+	if g.MixedMap.connections[from][to]!=CT_DIRECTED {
+		return 0.0, &ConnectionError{Op: "get arc weight", Tail: from, Head: to, Err: ErrConnectionNotFound}
+	}
+	return g.storedWeight(Connection{from, to}), nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WeightedUndirectedGraphEdgesWriter
+
+// Adding edge with weight to graph.
+func (g *WeightedMixedMap) AddWeightedEdge(from, to VertexId, weight float64) {
+	if err := g.TryAddWeightedEdge(from, to, weight); err!=nil {
+		erxErr := erx.NewSequentLevel("Add weighted edge to graph.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		erxErr.AddV("weight", weight)
+		panic(erxErr)
+	}
+}
+
+// Adding edge with weight to graph.
+//
+// Nodes are created if they don't exist. Returns ErrLoop if from==to and
+// ErrDuplicateConnection if nodes are already connected.
+func (g *WeightedMixedMap) TryAddWeightedEdge(from, to VertexId, weight float64) error {
+	if err := g.TryAddEdge(from, to); err!=nil {
+		return err
+	}
+	g.weights[NewUndirectedConnection(from, to).Connection] = weight
+	return nil
+}
+
+// Changing weight of existing edge.
+func (g *WeightedMixedMap) SetEdgeWeight(from, to VertexId, weight float64) {
+	if err := g.TrySetEdgeWeight(from, to, weight); err!=nil {
+		erxErr := erx.NewSequentLevel("Set edge weight.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		erxErr.AddV("weight", weight)
+		panic(erxErr)
+	}
+}
+
+// Changing weight of existing edge.
+//
+// Returns ErrConnectionNotFound if there is no such edge.
+func (g *WeightedMixedMap) TrySetEdgeWeight(from, to VertexId, weight float64) error {
+	if g.MixedMap.connections[from][to]!=CT_UNDIRECTED {
+		return &ConnectionError{Op: "set edge weight", Tail: from, Head: to, Err: ErrConnectionNotFound}
+	}
+	g.weights[NewUndirectedConnection(from, to).Connection] = weight
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WeightedUndirectedGraphEdgesReader
+
+// Getting edge weight.
+func (g *WeightedMixedMap) GetEdgeWeight(from, to VertexId) float64 {
+	weight, err := g.TryGetEdgeWeight(from, to)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Get edge weight.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		panic(erxErr)
+	}
+	return weight
+}
+
+// Getting edge weight.
+//
+// Returns ErrConnectionNotFound if there is no such edge.
+func (g *WeightedMixedMap) TryGetEdgeWeight(from, to VertexId) (float64, error) {
+	if g.MixedMap.connections[from][to]!=CT_UNDIRECTED {
+		return 0.0, &ConnectionError{Op: "get edge weight", Tail: from, Head: to, Err: ErrConnectionNotFound}
+	}
+	return g.storedWeight(NewUndirectedConnection(from, to).Connection), nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WeightedMixedGraphSpecificReader
+
+// Getting weight of arc from tail to head or edge between them.
+func (g *WeightedMixedMap) GetConnectionWeight(tail, head VertexId) float64 {
+	weight, err := g.TryGetConnectionWeight(tail, head)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Get connection weight.", err, 1)
+		erxErr.AddV("tail", tail)
+		erxErr.AddV("head", head)
+		panic(erxErr)
+	}
+	return weight
+}
+
+// Getting weight of arc from tail to head or edge between them.
+//
+// Returns ErrConnectionNotFound if there is neither arc from tail to head
+// nor edge between them.
+func (g *WeightedMixedMap) TryGetConnectionWeight(tail, head VertexId) (float64, error) {
+	switch g.MixedMap.connections[tail][head] {
+		case CT_DIRECTED:
+			return g.storedWeight(Connection{tail, head}), nil
+		case CT_UNDIRECTED:
+			return g.storedWeight(NewUndirectedConnection(tail, head).Connection), nil
+	}
+	return 0.0, &ConnectionError{Op: "get connection weight", Tail: tail, Head: head, Err: ErrConnectionNotFound}
+}
+
+func (g *WeightedMixedMap) storedWeight(conn Connection) float64 {
+	if weight, ok := g.weights[conn]; ok {
+		return weight
+	}
+	return DefaultConnectionWeight
+}
@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Undirected graph with map as a internal representation and float64 weight
+// for each edge.
+//
+// Edges, added with AddEdge, have DefaultConnectionWeight.
+type WeightedUndirectedMap struct {
+	*UndirectedMap
+	weights map[Connection]float64 // tail is always not greater than head
+}
+
+func NewWeightedUndirectedMap() *WeightedUndirectedMap {
+	return &WeightedUndirectedMap{
+		UndirectedMap: NewUndirectedMap(),
+		weights: make(map[Connection]float64),
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GraphVertexesRemover
+
+// Removing node with all it's edges and their weights from graph
+func (g *WeightedUndirectedMap) RemoveNode(node VertexId) {
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Removing node with all it's edges and their weights from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *WeightedUndirectedMap) TryRemoveNode(node VertexId) error {
+	if g.CheckNode(node) {
+		for neighbour := range VertexesSeqOf(g.GetNeighbours(node)) {
+			delete(g.weights, NewUndirectedConnection(node, neighbour).Connection)
+		}
+	}
+	return g.UndirectedMap.TryRemoveNode(node)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// UndirectedGraphEdgesRemover
+
+// Removing edge between 'from' and 'to' nodes with it's weight
+func (g *WeightedUndirectedMap) RemoveEdge(from, to VertexId) {
+	if err := g.TryRemoveEdge(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove edge from graph.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		panic(erxErr)
+	}
+}
+
+// Removing edge between 'from' and 'to' nodes with it's weight
+//
+// Returns ErrNodeNotFound if one of the nodes doesn't exist and
+// ErrConnectionNotFound if there is no such edge.
+func (g *WeightedUndirectedMap) TryRemoveEdge(from, to VertexId) error {
+	if err := g.UndirectedMap.TryRemoveEdge(from, to); err!=nil {
+		return err
+	}
+	delete(g.weights, NewUndirectedConnection(from, to).Connection)
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WeightedUndirectedGraphEdgesWriter
+
+// Adding edge with weight to graph.
+func (g *WeightedUndirectedMap) AddWeightedEdge(from, to VertexId, weight float64) {
+	if err := g.TryAddWeightedEdge(from, to, weight); err!=nil {
+		erxErr := erx.NewSequentLevel("Add weighted edge to graph.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		erxErr.AddV("weight", weight)
+		panic(erxErr)
+	}
+}
+
+// Adding edge with weight to graph.
+//
+// Nodes are created if they don't exist. Returns ErrDuplicateConnection if
+// edge already exists.
+func (g *WeightedUndirectedMap) TryAddWeightedEdge(from, to VertexId, weight float64) error {
+	if err := g.TryAddEdge(from, to); err!=nil {
+		return err
+	}
+	g.weights[NewUndirectedConnection(from, to).Connection] = weight
+	return nil
+}
+
+// Changing weight of existing edge.
+func (g *WeightedUndirectedMap) SetEdgeWeight(from, to VertexId, weight float64) {
+	if err := g.TrySetEdgeWeight(from, to, weight); err!=nil {
+		erxErr := erx.NewSequentLevel("Set edge weight.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		erxErr.AddV("weight", weight)
+		panic(erxErr)
+	}
+}
+
+// Changing weight of existing edge.
+//
+// Returns ErrConnectionNotFound if there is no such edge.
+func (g *WeightedUndirectedMap) TrySetEdgeWeight(from, to VertexId, weight float64) error {
+	if !g.UndirectedMap.edges[from][to] {
+		return &ConnectionError{Op: "set edge weight", Tail: from, Head: to, Err: ErrConnectionNotFound}
+	}
+	g.weights[NewUndirectedConnection(from, to).Connection] = weight
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// WeightedUndirectedGraphEdgesReader
+
+// Getting edge weight.
+func (g *WeightedUndirectedMap) GetEdgeWeight(from, to VertexId) float64 {
+	weight, err := g.TryGetEdgeWeight(from, to)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Get edge weight.", err, 1)
+		erxErr.AddV("node 1", from)
+		erxErr.AddV("node 2", to)
+		panic(erxErr)
+	}
+	return weight
+}
+
+// Getting edge weight.
+//
+// Returns ErrConnectionNotFound if there is no such edge.
+func (g *WeightedUndirectedMap) TryGetEdgeWeight(from, to VertexId) (float64, error) {
+	if !g.UndirectedMap.edges[from][to] {
+		return 0.0, &ConnectionError{Op: "get edge weight", Tail: from, Head: to, Err: ErrConnectionNotFound}
+	}
+	if weight, ok := g.weights[NewUndirectedConnection(from, to).Connection]; ok {
+		return weight, nil
+	}
+	return DefaultConnectionWeight, nil
+}
@@ -1,17 +1,26 @@
 package graph
 
-import (
-	"github.com/StepLg/go-erx/src/erx"
-)
-
 // Copy graph og to rg except args i->j, where exists non direct path i->...->j
 //
 // Graph rg contains all vertexes from original graph gr and arcs i->j, if there
 // doesn't exist path in original graph from i to j, which contains at least
 // 3 vertexes
+//
+// Without stopFunc it's the transitive reduction for acyclic graphs, so much
+// faster TransitiveReduction is used in this case. Otherwise it runs
+// Dijkstra search for each arc, which is too slow for large graphs.
 func ReduceDirectPaths(og DirectedGraphReader, rg DirectedGraphArcsWriter, stopFunc func(from, to VertexId, weight float64) bool) {
+	if stopFunc==nil {
+		if reduced, err := TryTransitiveReduction(og); err==nil {
+			for conn := range ArcsSeqOf(reduced) {
+				rg.AddArc(conn.Tail, conn.Head)
+			}
+			return
+		}
+	}
+	
 	var checkStopFunc StopFunc
-	for conn := range og.ArcsIter() {
+	for conn := range ArcsSeqOf(og) {
 		filteredGraph := NewDirectedGraphArcFilter(og, conn.Tail, conn.Head)
 		if stopFunc!=nil {
 			checkStopFunc = func(node VertexId, weight float64) bool {
@@ -30,58 +39,28 @@ func ReduceDirectPaths(og DirectedGraphReader, rg DirectedGraphArcsWriter, stopF
 //
 // Return nodes in topological order. If graph has cycles, then hasCycles==true 
 // and nodes==nil in function result.
+//
+// Result order depends on graph internals and could differ from run to run.
+// Use KahnTopologicalSort for deterministic order.
 func TopologicalSort(gr DirectedGraphReader) (nodes []VertexId, hasCycles bool) {
-	hasCycles = false
 	nodes = make([]VertexId, gr.Order())
 	pos := len(nodes)
-	// map of node status. If node doesn't present in map - white color,
-	// node in map with false value - grey color, and with true value - black color
-	status := make(map[VertexId]bool)
-	for source := range gr.GetSources().VertexesIter() {
-		pos, hasCycles = topologicalSortHelper(gr, source, nodes[0:pos], status)
-		if hasCycles {
-			nodes = nil
-			return
-		}
-	}
-	if pos!=0 {
-		// cycle without path from any source to this cycle
-		nodes = nil
-		hasCycles = true
-	}
-	return
-}
-
-func topologicalSortHelper(gr DirectedGraphReader, curNode VertexId, nodes []VertexId, status map[VertexId]bool) (pos int, hasCycles bool) {
-	if isBlack, ok := status[curNode]; ok {
-		err := erx.NewError("Internal error in topological sort: node already in status map")
-		err.AddV("node id", curNode)
-		err.AddV("status in map", isBlack)
-		panic(err)
-	}
-	hasCycles = false
-	status[curNode] = false
-	pos = len(nodes)
-	for accessor := range gr.GetAccessors(curNode).VertexesIter() {
-		if isBlack, ok := status[accessor]; ok {
-			if !isBlack {
-				// cycle detected!
-				hasCycles = true
-				return
-			} else {
-				// we have already visited this node
-				continue
-			}
-		}
-		pos, hasCycles = topologicalSortHelper(gr, accessor, nodes[0:pos], status)
-		if hasCycles {
-			return
-		}
-	}
-	status[curNode] = true
-	pos--
-	nodes[pos] = curNode
-	return
+	completed := DepthFirst(NewDgraphOutNeighboursExtractor(gr), CollectVertexes(gr.GetSources()), Visitor{
+		ClassifiedEdge: func(tail, head VertexId, class EdgeClass) bool {
+			// back connection means cycle
+			return class!=EC_BACK
+		},
+		FinishVertex: func(node VertexId) bool {
+			pos--
+			nodes[pos] = node
+			return true
+		},
+	})
+	if !completed || pos!=0 {
+		// pos!=0 means cycle without path from any source to this cycle
+		return nil, true
+	}
+	return nodes, false
 }
 
 // Split mixed graph to independed subraphs.
@@ -99,7 +78,7 @@ func SplitGraphToIndependentSubgraphs_mixed(gr MixedGraphReader) []MixedGraph {
 	curColor := 0
 	
 	// coloring vertexes
-	for curNode := range gr.GetSources().VertexesIter() {
+	for curNode := range VertexesSeqOf(gr.GetSources()) {
 		if _, ok := nodesColor[curNode]; ok {
 			// node already visited
 			continue
@@ -108,33 +87,25 @@ func SplitGraphToIndependentSubgraphs_mixed(gr MixedGraphReader) []MixedGraph {
 		curColor++
 	}
 	
-	// get total vertexes number of each subgraph
-	colors := make(map[int]int)
-	for _, color := range nodesColor {
-		if _, ok := colors[color]; !ok {
-			colors[color] = 0
-		}
-		colors[color]++
-	}
-
-	// making subgraphs map with color as a key	
-	result := make(map[int]MixedGraph, len(colors))
-	for color, nodesCnt := range colors {
-		result[color] = NewMixedMatrix(nodesCnt)
-	}
-	
 	// copying nodes to subgraphs
-	for node := range gr.VertexesIter() {
-		result[nodesColor[node]].AddNode(node)
+	result := make(map[int]MixedGraph, curColor)
+	for node := range VertexesSeqOf(gr) {
+		var subgr MixedGraph
+		var ok bool
+		if subgr, ok = result[nodesColor[node]]; !ok {
+			subgr = NewMixedMatrix(0)
+			result[nodesColor[node]] = subgr
+		}
+		subgr.AddNode(node)
 	}
 	
 	// copying arcs to subgraphs
-	for arc := range gr.ArcsIter() {
+	for arc := range ArcsSeqOf(gr) {
 		result[nodesColor[arc.Tail]].AddArc(arc.Tail, arc.Head)
 	}
 	
 	// copying edges to subgraphs
-	for edge := range gr.EdgesIter() {
+	for edge := range EdgesSeqOf(gr) {
 		result[nodesColor[edge.Tail]].AddEdge(edge.Tail, edge.Head)
 	}
 	
@@ -163,14 +134,14 @@ func SplitGraphToIndependentSubgraphs_directed(gr DirectedGraphReader) []Directe
 	curColor := 0
 	
 	// coloring vertexes
-	for curNode := range gr.GetSources().VertexesIter() {
+	for curNode := range VertexesSeqOf(gr.GetSources()) {
 		splitGraphToIndependentSubgraphs_helper(curNode, curColor, NewDgraphOutNeighboursExtractor(gr), nodesColor)
 		curColor++
 	}
 	
 	// copying nodes to subgraphs
 	result := make(map[int]DirectedGraph)
-	for node := range gr.VertexesIter() {
+	for node := range VertexesSeqOf(gr) {
 		var subgr DirectedGraph
 		var ok bool
 		if subgr, ok = result[nodesColor[node]]; !ok {
@@ -181,7 +152,7 @@ func SplitGraphToIndependentSubgraphs_directed(gr DirectedGraphReader) []Directe
 	}
 	
 	// copying arcs to subgraphs
-	for arc := range gr.ArcsIter() {
+	for arc := range ArcsSeqOf(gr) {
 		result[nodesColor[arc.Tail]].AddArc(arc.Tail, arc.Head)
 	}
 	
@@ -210,7 +181,7 @@ func SplitGraphToIndependentSubgraphs_undirected(gr UndirectedGraphReader) []Und
 	curColor := 0
 	
 	// coloring vertexes
-	for curNode := range gr.VertexesIter() {
+	for curNode := range VertexesSeqOf(gr) {
 		if _, ok := nodesColor[curNode]; ok {
 			// node already visited
 			continue
@@ -221,7 +192,7 @@ func SplitGraphToIndependentSubgraphs_undirected(gr UndirectedGraphReader) []Und
 	
 	// copying nodes to subgraphs
 	result := make(map[int]UndirectedGraph)
-	for node := range gr.VertexesIter() {
+	for node := range VertexesSeqOf(gr) {
 		var subgr UndirectedGraph
 		var ok bool
 		if subgr, ok = result[nodesColor[node]]; !ok {
@@ -232,7 +203,7 @@ func SplitGraphToIndependentSubgraphs_undirected(gr UndirectedGraphReader) []Und
 	}
 	
 	// copying edges to subgraphs
-	for edge := range gr.EdgesIter() {
+	for edge := range EdgesSeqOf(gr) {
 		result[nodesColor[edge.Tail]].AddEdge(edge.Tail, edge.Head)
 	}
 	
@@ -253,7 +224,7 @@ func SplitGraphToIndependentSubgraphs_undirected(gr UndirectedGraphReader) []Und
 // changing color to new.
 func splitGraphToIndependentSubgraphs_helper(node VertexId, color int, gr OutNeighboursExtractor, nodesColor map[VertexId]int) {
 	nodesColor[node] = color
-	for next := range gr.GetOutNeighbours(node).VertexesIter() {
+	for next := range VertexesSeqOf(gr.GetOutNeighbours(node)) {
 		if nextColor, ok := nodesColor[next]; ok {
 			if nextColor != color {
 				// change all 'nextColor' nodes to 'color' nodes
@@ -2,27 +2,25 @@ package graph
 
 import (
 	"testing"
-	"github.com/orfjackal/gospec/src/gospec"
-	. "github.com/orfjackal/gospec/src/gospec"
 )
 
-func ReduceDirectPathsSpec(c gospec.Context) {
-	c.Specify("Reduced triangle", func() {
+func TestReduceDirectPaths(t *testing.T) {
+	t.Run("Reduced triangle", func(t *testing.T) {
 		gr := NewDirectedMap()
 		gr.AddArc(1, 2)
 		gr.AddArc(2, 3)
 		gr.AddArc(1, 3)
-		
+
 		rgr := NewDirectedMap()
 		ReduceDirectPaths(gr, rgr, nil)
-		
+
 		expectedGraph := NewDirectedMap()
 		expectedGraph.AddArc(1, 2)
 		expectedGraph.AddArc(2, 3)
-		c.Expect(rgr, DirectedGraphEquals, expectedGraph)
+		expectDirectedGraphEquals(t, rgr, expectedGraph)
 	})
-	
-	c.Specify("A bit more complex example", func() {
+
+	t.Run("A bit more complex example", func(t *testing.T) {
 		gr := NewDirectedMap()
 		gr.AddArc(1, 2)
 		gr.AddArc(2, 3)
@@ -31,49 +29,51 @@ func ReduceDirectPathsSpec(c gospec.Context) {
 		gr.AddArc(4, 5)
 		gr.AddArc(1, 6)
 		gr.AddArc(2, 6)
-		
+
 		rgr := NewDirectedMap()
 		ReduceDirectPaths(gr, rgr, nil)
-		
+
 		expectedGraph := NewDirectedMap()
 		expectedGraph.AddArc(1, 2)
 		expectedGraph.AddArc(2, 3)
 		expectedGraph.AddArc(3, 4)
 		expectedGraph.AddArc(4, 5)
 		expectedGraph.AddArc(2, 6)
-		c.Expect(rgr, DirectedGraphEquals, expectedGraph)
+		expectDirectedGraphEquals(t, rgr, expectedGraph)
 	})
 }
 
-func TopologicalSortSpec(c gospec.Context) {
-	gr := NewDirectedMap()
-	c.Specify("Single node graph", func() {
+func TestTopologicalSort(t *testing.T) {
+	t.Run("Single node graph", func(t *testing.T) {
+		gr := NewDirectedMap()
 		gr.AddNode(VertexId(1))
 		nodes, hasCycle := TopologicalSort(gr)
-		c.Expect(hasCycle, IsFalse)
-		c.Expect(nodes, ContainsExactly, Values(VertexId(1)))
+		expectFalse(t, hasCycle, "has cycle")
+		expectVertexesExactly(t, nodes, 1)
 	})
-	
-	c.Specify("Simple two nodes graph", func() {
+
+	t.Run("Simple two nodes graph", func(t *testing.T) {
+		gr := NewDirectedMap()
 		gr.AddArc(1, 2)
 		nodes, hasCycle := TopologicalSort(gr)
-		c.Expect(hasCycle, IsFalse)
-		c.Expect(nodes, ContainsExactly, Values(VertexId(1), VertexId(2)))
+		expectFalse(t, hasCycle, "has cycle")
+		expectPath(t, nodes, 1, 2)
 	})
-	
-	c.Specify("Pseudo loops", func() {
+
+	t.Run("Pseudo loops", func(t *testing.T) {
+		gr := NewDirectedMap()
 		gr.AddArc(1, 2)
 		gr.AddArc(2, 3)
 		gr.AddArc(1, 4)
 		gr.AddArc(4, 3)
-		
+
 		_, hasCycle := TopologicalSort(gr)
-		c.Expect(hasCycle, IsFalse)
+		expectFalse(t, hasCycle, "has cycle")
 	})
 }
 
-func SplitGraphToIndependentSubgraphs_mixedSpec(c gospec.Context) {
-	c.Specify("Single node graph", func() {
+func TestSplitGraphToIndependentSubgraphs_mixed(t *testing.T) {
+	t.Run("Mixed graph with 2 independent parts", func(t *testing.T) {
 		subgr1 := NewMixedMatrix(3)
 		subgr1.AddArc(1, 2)
 		subgr1.AddEdge(1, 3)
@@ -81,56 +81,49 @@ func SplitGraphToIndependentSubgraphs_mixedSpec(c gospec.Context) {
 		subgr2.AddArc(4, 5)
 		subgr2.AddArc(5, 6)
 		subgr2.AddArc(4, 6)
-		
+
 		gr := NewMixedMatrix(6)
 		CopyMixedGraph(subgr1, gr)
 		CopyMixedGraph(subgr2, gr)
-		
+
 		subgraphs := SplitGraphToIndependentSubgraphs_mixed(gr)
-		c.Expect(len(subgraphs), Equals, 2)
-		// @todo: Add ContainsGraph comparator to check if slice contains a graph
-		c.Expect(MixedGraphsEquals(subgr1, subgraphs[1]), IsTrue)
-		c.Expect(MixedGraphsEquals(subgr2, subgraphs[0]), IsTrue)
+		expectEquals(t, len(subgraphs), 2)
+		if subgraphs[0].CheckNode(VertexId(1)) {
+			expectTrue(t, MixedGraphsEquals(subgr1, subgraphs[0]), "first subgraph")
+			expectTrue(t, MixedGraphsEquals(subgr2, subgraphs[1]), "second subgraph")
+		} else {
+			expectTrue(t, MixedGraphsEquals(subgr1, subgraphs[1]), "first subgraph")
+			expectTrue(t, MixedGraphsEquals(subgr2, subgraphs[0]), "second subgraph")
+		}
 	})
 }
 
-func SplitGraphToIndependentSubgraphs_directedSpec(c gospec.Context) {
-	c.Specify("Directed graph with 2 independent parts", func() {
+func TestSplitGraphToIndependentSubgraphs_directed(t *testing.T) {
+	t.Run("Directed graph with 2 independent parts", func(t *testing.T) {
 		gr1, gr2, gr_merged := genDgr2IndependentSubGr()
 		subgraphs := SplitGraphToIndependentSubgraphs_directed(gr_merged)
-		c.Expect(len(subgraphs), Equals, 2)
+		expectEquals(t, len(subgraphs), 2)
 		if subgraphs[0].CheckNode(VertexId(1)) {
-			c.Expect(DirectedGraphsEquals(subgraphs[0], gr1), IsTrue)
-			c.Expect(DirectedGraphsEquals(subgraphs[1], gr2), IsTrue)
+			expectTrue(t, DirectedGraphsEquals(subgraphs[0], gr1), "first subgraph")
+			expectTrue(t, DirectedGraphsEquals(subgraphs[1], gr2), "second subgraph")
 		} else {
-			c.Expect(DirectedGraphsEquals(subgraphs[0], gr2), IsTrue)
-			c.Expect(DirectedGraphsEquals(subgraphs[1], gr1), IsTrue)
+			expectTrue(t, DirectedGraphsEquals(subgraphs[0], gr2), "second subgraph")
+			expectTrue(t, DirectedGraphsEquals(subgraphs[1], gr1), "first subgraph")
 		}
 	})
 }
 
-func SplitGraphToIndependentSubgraphs_undirectedSpec(c gospec.Context) {
-	c.Specify("Undirected graph with 2 independent parts", func() {
+func TestSplitGraphToIndependentSubgraphs_undirected(t *testing.T) {
+	t.Run("Undirected graph with 2 independent parts", func(t *testing.T) {
 		gr1, gr2, gr_merged := genUgr2IndependentSubGr()
 		subgraphs := SplitGraphToIndependentSubgraphs_undirected(gr_merged)
-		c.Expect(len(subgraphs), Equals, 2)
+		expectEquals(t, len(subgraphs), 2)
 		if subgraphs[0].CheckNode(VertexId(1)) {
-			c.Expect(UndirectedGraphsEquals(subgraphs[0], gr1), IsTrue)
-			c.Expect(UndirectedGraphsEquals(subgraphs[1], gr2), IsTrue)
+			expectTrue(t, UndirectedGraphsEquals(subgraphs[0], gr1), "first subgraph")
+			expectTrue(t, UndirectedGraphsEquals(subgraphs[1], gr2), "second subgraph")
 		} else {
-			c.Expect(UndirectedGraphsEquals(subgraphs[0], gr2), IsTrue)
-			c.Expect(UndirectedGraphsEquals(subgraphs[1], gr1), IsTrue)
+			expectTrue(t, UndirectedGraphsEquals(subgraphs[0], gr2), "second subgraph")
+			expectTrue(t, UndirectedGraphsEquals(subgraphs[1], gr1), "first subgraph")
 		}
 	})
 }
-
-
-func TestAlgorithms(t *testing.T) {
-	r := gospec.NewRunner()
-	r.AddSpec(ReduceDirectPathsSpec)
-	r.AddSpec(TopologicalSortSpec)
-	r.AddSpec(SplitGraphToIndependentSubgraphs_mixedSpec)
-	r.AddSpec(SplitGraphToIndependentSubgraphs_directedSpec)
-	r.AddSpec(SplitGraphToIndependentSubgraphs_undirectedSpec)
-	gospec.MainGoTest(r, t)
-}
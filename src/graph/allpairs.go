@@ -0,0 +1,190 @@
+package graph
+
+import (
+	"math"
+	"sort"
+)
+
+// Shortest paths between all pairs of graph vertexes.
+//
+// Stores distance matrix and next hop matrix, so every path can be
+// reconstructed in O(path length) time.
+type AllPairsPaths struct {
+	vertexes Vertexes // sorted graph vertexes
+	index map[VertexId]int
+	dist []float64 // n*n matrix, +Inf if there is no path
+	next []int // n*n matrix of next hop indexes, -1 if there is no path
+}
+
+func newAllPairsPaths(gr DirectedGraphReader) *AllPairsPaths {
+	vertexes := CollectVertexes(gr)
+	sort.Slice(vertexes, func(i, j int) bool { return vertexes[i]<vertexes[j] })
+	n := len(vertexes)
+	p := &AllPairsPaths{
+		vertexes: vertexes,
+		index: make(map[VertexId]int, n),
+		dist: make([]float64, n*n),
+		next: make([]int, n*n),
+	}
+	for i, vertex := range vertexes {
+		p.index[vertex] = i
+	}
+	for i, _ := range p.dist {
+		p.dist[i] = math.Inf(1)
+		p.next[i] = -1
+	}
+	for i:=0; i<n; i++ {
+		p.dist[i*n+i] = 0.0
+		p.next[i*n+i] = i
+	}
+	return p
+}
+
+// All vertexes of graph in ascending order
+func (p *AllPairsPaths) Vertexes() Vertexes {
+	return p.vertexes
+}
+
+// Shortest path weight from one vertex to another
+//
+// ok is false if there is no path or any of vertexes doesn't belong to graph.
+func (p *AllPairsPaths) Distance(from, to VertexId) (weight float64, ok bool) {
+	i, okFrom := p.index[from]
+	j, okTo := p.index[to]
+	if !okFrom || !okTo || p.next[i*len(p.vertexes)+j]==-1 {
+		return math.Inf(1), false
+	}
+	return p.dist[i*len(p.vertexes)+j], true
+}
+
+// Next vertex in shortest path from one vertex to another
+//
+// ok is false if there is no path or any of vertexes doesn't belong to graph.
+func (p *AllPairsPaths) NextHop(from, to VertexId) (next VertexId, ok bool) {
+	i, okFrom := p.index[from]
+	j, okTo := p.index[to]
+	if !okFrom || !okTo || p.next[i*len(p.vertexes)+j]==-1 {
+		return 0, false
+	}
+	return p.vertexes[p.next[i*len(p.vertexes)+j]], true
+}
+
+// Shortest path from one vertex to another
+//
+// Returns nil if there is no path.
+func (p *AllPairsPaths) Path(from, to VertexId) []VertexId {
+	if _, ok := p.NextHop(from, to); !ok {
+		return nil
+	}
+	n := len(p.vertexes)
+	i, j := p.index[from], p.index[to]
+	path := []VertexId{from}
+	for i!=j {
+		i = p.next[i*n+j]
+		path = append(path, p.vertexes[i])
+	}
+	return path
+}
+
+// Compute all-pairs shortest paths with Floyd-Warshall algorithm
+//
+// Takes O(n^3) time and O(n^2) memory, so it's suitable for dense graphs.
+// Negative weights are allowed. If graph has negative cycle, returns nil and
+// true.
+func FloydWarshall(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) (paths *AllPairsPaths, hasNegativeCycle bool) {
+	p := newAllPairsPaths(gr)
+	n := len(p.vertexes)
+	for conn := range ArcsSeqOf(gr) {
+		i, j := p.index[conn.Tail], p.index[conn.Head]
+		weight := weightFunc(conn.Tail, conn.Head)
+		if weight < p.dist[i*n+j] {
+			p.dist[i*n+j] = weight
+			p.next[i*n+j] = j
+		}
+	}
+	
+	for k:=0; k<n; k++ {
+		for i:=0; i<n; i++ {
+			ik := p.dist[i*n+k]
+			if math.IsInf(ik, 1) {
+				continue
+			}
+			for j:=0; j<n; j++ {
+				if weight := ik + p.dist[k*n+j]; weight < p.dist[i*n+j] {
+					p.dist[i*n+j] = weight
+					p.next[i*n+j] = p.next[i*n+k]
+				}
+			}
+		}
+	}
+	
+	for i:=0; i<n; i++ {
+		if p.dist[i*n+i] < 0 {
+			return nil, true
+		}
+	}
+	return p, false
+}
+
+// Compute all-pairs shortest paths with Johnson algorithm
+//
+// Vertexes potentials are computed with BellmanFordMultiSource (all vertexes
+// are sources, which is the same as virtual source connected with all
+// vertexes), then Dijkstra algorithm runs from each vertex with non-negative
+// reweighted connections. Takes O(n*m*log(n)) time, so it's suitable for
+// sparse graphs. Negative weights are allowed. If graph has negative cycle,
+// returns nil and true.
+func Johnson(gr DirectedGraphReader, weightFunc ConnectionWeightFunc) (paths *AllPairsPaths, hasNegativeCycle bool) {
+	potentials := BellmanFordMultiSource(gr, CollectVertexes(gr), weightFunc)
+	if potentials==nil {
+		return nil, true
+	}
+	
+	reweighted := func(tail, head VertexId) float64 {
+		weight := weightFunc(tail, head) + potentials[tail].Weight - potentials[head].Weight
+		// rounding errors could make zero weight slightly negative
+		return math.Max(weight, 0.0)
+	}
+	
+	p := newAllPairsPaths(gr)
+	n := len(p.vertexes)
+	extractor := NewDgraphOutNeighboursExtractor(gr)
+	for i, source := range p.vertexes {
+		marks := DijkstraSingleSource(extractor, source, reweighted)
+		hops := firstHops(marks, source)
+		for target, mark := range marks {
+			j := p.index[target]
+			p.next[i*n+j] = p.index[hops[target]]
+			p.dist[i*n+j] = mark.Weight - potentials[source].Weight + potentials[target].Weight
+		}
+	}
+	return p, false
+}
+
+// Vertexes after source in paths from marks to each of marked vertexes
+func firstHops(marks PathMarks, source VertexId) map[VertexId]VertexId {
+	hops := make(map[VertexId]VertexId, len(marks))
+	hops[source] = source
+	stack := make(Vertexes, 0)
+	for target, _ := range marks {
+		// going back by marks until vertex with known hop
+		for {
+			if _, ok := hops[target]; ok {
+				break
+			}
+			prev := marks[target].PrevVertex
+			if prev==source {
+				hops[target] = target
+				break
+			}
+			stack = append(stack, target)
+			target = prev
+		}
+		hop := hops[target]
+		for _, vertex := range stack {
+			hops[vertex] = hop
+		}
+		stack = stack[:0]
+	}
+	return hops
+}
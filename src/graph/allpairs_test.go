@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAllPairsPaths(t *testing.T) {
+	algorithms := map[string]func(DirectedGraphReader, ConnectionWeightFunc) (*AllPairsPaths, bool){
+		"floyd-warshall": FloydWarshall,
+		"johnson": Johnson,
+	}
+
+	for name, algorithm := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			t.Run("simple graph", func(t *testing.T) {
+				gr := generateDirectedGraph1()
+				gr.AddNode(10)
+				paths, hasNegativeCycle := algorithm(gr, SimpleWeightFunc)
+				expectFalse(t, hasNegativeCycle, "negative cycle")
+				expectPath(t, paths.Vertexes(), 1, 2, 3, 4, 5, 6, 10)
+				expectPath(t, paths.Path(1, 5), 1, 2, 4, 5)
+				expectPath(t, paths.Path(3, 3), 3)
+				expectEquals(t, len(paths.Path(5, 1)), 0)
+				expectEquals(t, len(paths.Path(1, 100)), 0)
+				weight, ok := paths.Distance(1, 5)
+				expectTrue(t, ok, "path exists")
+				expectEquals(t, weight, 3.0)
+				_, ok = paths.Distance(1, 10)
+				expectFalse(t, ok, "path to isolated vertex exists")
+				next, ok := paths.NextHop(2, 5)
+				expectTrue(t, ok, "next hop exists")
+				expectEquals(t, next, VertexId(4))
+			})
+
+			t.Run("negative weights", func(t *testing.T) {
+				gr := NewWeightedDirectedMap()
+				gr.AddWeightedArc(1, 2, 4.0)
+				gr.AddWeightedArc(1, 3, 1.0)
+				gr.AddWeightedArc(3, 2, -2.0)
+				gr.AddWeightedArc(2, 4, 1.0)
+				gr.AddWeightedArc(4, 3, 3.0)
+				paths, hasNegativeCycle := algorithm(gr, ArcWeightFunc(gr))
+				expectFalse(t, hasNegativeCycle, "negative cycle")
+				expectPath(t, paths.Path(1, 4), 1, 3, 2, 4)
+				weight, _ := paths.Distance(1, 4)
+				expectEquals(t, weight, 0.0)
+				weight, _ = paths.Distance(4, 2)
+				expectEquals(t, weight, 1.0)
+			})
+
+			t.Run("negative cycle", func(t *testing.T) {
+				gr := NewWeightedDirectedMap()
+				gr.AddWeightedArc(1, 2, 1.0)
+				gr.AddWeightedArc(2, 3, -2.0)
+				gr.AddWeightedArc(3, 2, 1.0)
+				gr.AddWeightedArc(3, 4, 1.0)
+				paths, hasNegativeCycle := algorithm(gr, ArcWeightFunc(gr))
+				expectTrue(t, hasNegativeCycle, "negative cycle")
+				expectTrue(t, paths==nil, "no paths with negative cycle")
+
+				loop := NewDirectedMatrix(2)
+				loop.AddArc(1, 1)
+				_, hasNegativeCycle = algorithm(loop, func(tail, head VertexId) float64 { return -1.0 })
+				expectTrue(t, hasNegativeCycle, "negative loop")
+			})
+		})
+	}
+
+	t.Run("random graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		size := 30
+		for i:=0; i<10; i++ {
+			gr := NewWeightedDirectedMap()
+			potential := make([]float64, size)
+			for node:=0; node<size; node++ {
+				gr.AddNode(VertexId(node))
+				potential[node] = float64(rnd.Intn(10))
+			}
+			for j:=0; j<size*3; j++ {
+				tail, head := rnd.Intn(size), rnd.Intn(size)
+				if tail==head || gr.CheckArc(VertexId(tail), VertexId(head)) {
+					continue
+				}
+				// potentials difference makes negative weights without negative cycles
+				gr.AddWeightedArc(VertexId(tail), VertexId(head), float64(rnd.Intn(10)) + potential[tail] - potential[head])
+			}
+			weightFunc := ArcWeightFunc(gr)
+			floyd, _ := FloydWarshall(gr, weightFunc)
+			johnson, _ := Johnson(gr, weightFunc)
+			for from:=0; from<size; from++ {
+				marks := BellmanFordSingleSource(gr, VertexId(from), weightFunc)
+				for to:=0; to<size; to++ {
+					floydWeight, floydOk := floyd.Distance(VertexId(from), VertexId(to))
+					johnsonWeight, johnsonOk := johnson.Distance(VertexId(from), VertexId(to))
+					expectEquals(t, floydOk, marks[VertexId(to)].Weight!=math.MaxFloat64)
+					expectEquals(t, johnsonOk, floydOk)
+					if !floydOk {
+						continue
+					}
+					expectTrue(t, math.Abs(floydWeight-marks[VertexId(to)].Weight)<1e-9, "floyd-warshall distance")
+					expectTrue(t, math.Abs(johnsonWeight-floydWeight)<1e-9, "johnson distance")
+					for _, paths := range []*AllPairsPaths{floyd, johnson} {
+						path := paths.Path(VertexId(from), VertexId(to))
+						pathWeight := 0.0
+						for k:=1; k<len(path); k++ {
+							pathWeight += weightFunc(path[k-1], path[k])
+						}
+						expectTrue(t, math.Abs(pathWeight-floydWeight)<1e-9, "path weight")
+					}
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Enables expensive runtime checks of algorithms input.
+//
+// For now only A* uses it to check heuristic consistency. Useful in tests
+// and while developing new heuristics, should be disabled in production.
+var DebugChecks = false
+
+// Estimation of path weight from vertex to the search target.
+//
+// A* finds shortest path only with consistent heuristic:
+// heuristic(target)==0 and heuristic(tail) <= weight(tail, head) + heuristic(head)
+// for every connection.
+type HeuristicFunc func(node VertexId) float64
+
+// Vertex coordinates on the plane.
+type VertexCoordsFunc func(node VertexId) (x, y float64)
+
+// Coordinates, taken from vertexes attributes.
+//
+// Panics if vertex doesn't have any of coordinate attributes.
+func AttributesCoords(attrs *GraphAttributes, xKey, yKey AttributeKey[float64]) VertexCoordsFunc {
+	return func(node VertexId) (float64, float64) {
+		vertexAttrs, ok := attrs.LookupVertex(node)
+		if ok {
+			x, okX := xKey.Get(vertexAttrs)
+			y, okY := yKey.Get(vertexAttrs)
+			if okX && okY {
+				return x, y
+			}
+		}
+		err := erx.NewError("Vertex doesn't have coordinates.")
+		err.AddV("node", node)
+		err.AddV("x attribute", string(xKey))
+		err.AddV("y attribute", string(yKey))
+		panic(err)
+	}
+}
+
+// Manhattan distance from vertex to target
+//
+// Consistent if connection weight isn't less than manhattan distance between
+// it's vertexes, for example on 4-connected grid with unit cells.
+func ManhattanHeuristic(coords VertexCoordsFunc, target VertexId) HeuristicFunc {
+	targetX, targetY := coords(target)
+	return func(node VertexId) float64 {
+		x, y := coords(node)
+		return math.Abs(x-targetX) + math.Abs(y-targetY)
+	}
+}
+
+// Euclidean distance from vertex to target
+//
+// Consistent if connection weight isn't less than euclidean distance between
+// it's vertexes, for example on geographic graphs with roads lengths as weights.
+func EuclideanHeuristic(coords VertexCoordsFunc, target VertexId) HeuristicFunc {
+	targetX, targetY := coords(target)
+	return func(node VertexId) float64 {
+		x, y := coords(node)
+		return math.Hypot(x-targetX, y-targetY)
+	}
+}
+
+// Find shortest path with A* algorithm
+//
+// Returns path from one node to another and it's weight, or nil and -1.0 if
+// there is no path. All weights must be non-negative and heuristic must be
+// consistent (see HeuristicFunc), otherwise result path may be not the shortest.
+// Set DebugChecks to check heuristic consistency for each visited connection.
+func AStar(neighboursExtractor OutNeighboursExtractor, from, to VertexId, weightFunction ConnectionWeightFunc, heuristic HeuristicFunc) ([]VertexId, float64) {
+	path, weight, err := TryAStarWithStop(neighboursExtractor, from, to, weightFunction, heuristic, nil)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Find shortest path with A* algorithm.", err, 1)
+		erxErr.AddV("from", from)
+		erxErr.AddV("to", to)
+		panic(erxErr)
+	}
+	return path, weight
+}
+
+// Error-returning version of AStar.
+func TryAStar(neighboursExtractor OutNeighboursExtractor, from, to VertexId, weightFunction ConnectionWeightFunc, heuristic HeuristicFunc) ([]VertexId, float64, error) {
+	return TryAStarWithStop(neighboursExtractor, from, to, weightFunction, heuristic, nil)
+}
+
+// Find shortest path with A* algorithm, skipping vertexes by stopFunc
+//
+// stopFunc is called with path weight from source to vertex (without heuristic
+// estimation). Paths through vertexes, for which it returns true, are ignored.
+// See AStar for details.
+func AStarWithStop(neighboursExtractor OutNeighboursExtractor, from, to VertexId, weightFunction ConnectionWeightFunc, heuristic HeuristicFunc, stopFunc StopFunc) ([]VertexId, float64) {
+	path, weight, err := TryAStarWithStop(neighboursExtractor, from, to, weightFunction, heuristic, stopFunc)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Find shortest path with A* algorithm.", err, 1)
+		erxErr.AddV("from", from)
+		erxErr.AddV("to", to)
+		panic(erxErr)
+	}
+	return path, weight
+}
+
+// Error-returning version of AStarWithStop.
+//
+// Returns *ConnectionError with ErrNegativeWeight if weightFunction returns
+// negative value for any checked connection. If DebugChecks is set, returns
+// *ConnectionError (or *VertexError for target) with ErrInconsistentHeuristic.
+// Panics from neighboursExtractor and heuristic are returned as errors too.
+func TryAStarWithStop(neighboursExtractor OutNeighboursExtractor, from, to VertexId, weightFunction ConnectionWeightFunc, heuristic HeuristicFunc, stopFunc StopFunc) (path []VertexId, weight float64, err error) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			path, weight, err = nil, -1.0, recoveredToError(e)
+		}
+	}()
+	
+	if DebugChecks && heuristic(to)!=0 {
+		return nil, -1.0, &VertexError{Op: "a*", Node: to, Err: ErrInconsistentHeuristic}
+	}
+	
+	marks := PathMarks{from: &VertexPathMark{Weight: 0.0, PrevVertex: from}}
+	q := NewBinaryHeap(10)
+	q.Push(from, heuristic(from))
+	closed := make(map[VertexId]bool)
+	
+	for !q.Empty() {
+		curNode, _ := q.Pop()
+		if curNode==to {
+			return PathFromMarks(marks, to), marks[to].Weight, nil
+		}
+		closed[curNode] = true
+		
+		curWeight := marks[curNode].Weight
+		for nextNode := range VertexesSeqOf(neighboursExtractor.GetOutNeighbours(curNode)) {
+			arcWeight := weightFunction(curNode, nextNode)
+			if arcWeight < 0 {
+				return nil, -1.0, &ConnectionError{Op: "a*", Tail: curNode, Head: nextNode, Err: ErrNegativeWeight}
+			}
+			if DebugChecks && heuristic(curNode) > arcWeight + heuristic(nextNode) + 1e-9 {
+				return nil, -1.0, &ConnectionError{Op: "a*", Tail: curNode, Head: nextNode, Err: ErrInconsistentHeuristic}
+			}
+			if closed[nextNode] {
+				continue
+			}
+			nextWeight := curWeight + arcWeight
+			if stopFunc!=nil && stopFunc(nextNode, nextWeight) {
+				continue
+			}
+			if mark, ok := marks[nextNode]; !ok || nextWeight < mark.Weight {
+				marks[nextNode] = &VertexPathMark{Weight: nextWeight, PrevVertex: curNode}
+				PushOrDecreaseKey(q, nextNode, nextWeight + heuristic(nextNode))
+			}
+		}
+	}
+	
+	return nil, -1.0, nil
+}
@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"testing"
+)
+
+var (
+	xAttr = AttributeKey[float64]("x")
+	yAttr = AttributeKey[float64]("y")
+)
+
+// Generate 4-connected grid graph size x size with wall in column wallX,
+// which has only one hole in the top row.
+func generateGridGraph(size, wallX int) (*AttributedUndirectedGraph, func(x, y int) VertexId) {
+	node := func(x, y int) VertexId {
+		return VertexId(y*size + x)
+	}
+	gr := NewAttributedUndirectedGraph(NewUndirectedMap())
+	for y:=0; y<size; y++ {
+		for x:=0; x<size; x++ {
+			gr.AddNode(node(x, y))
+			xAttr.Set(gr.Attributes().Vertex(node(x, y)), float64(x))
+			yAttr.Set(gr.Attributes().Vertex(node(x, y)), float64(y))
+		}
+	}
+	isWall := func(x, y int) bool {
+		return x==wallX && y>0
+	}
+	for y:=0; y<size; y++ {
+		for x:=0; x<size; x++ {
+			if isWall(x, y) {
+				continue
+			}
+			if x+1<size && !isWall(x+1, y) {
+				gr.AddEdge(node(x, y), node(x+1, y))
+			}
+			if y+1<size && !isWall(x, y+1) {
+				gr.AddEdge(node(x, y), node(x, y+1))
+			}
+		}
+	}
+	return gr, node
+}
+
+type countingExtractor struct {
+	OutNeighboursExtractor
+	calls int
+}
+
+func (e *countingExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	e.calls++
+	return e.OutNeighboursExtractor.GetOutNeighbours(node)
+}
+
+func TestAStar(t *testing.T) {
+	gr, node := generateGridGraph(10, 5)
+	extractor := NewUgraphOutNeighboursExtractor(gr)
+	coords := AttributesCoords(gr.Attributes(), xAttr, yAttr)
+	from, to := node(0, 9), node(9, 9)
+
+	t.Run("same as dijkstra", func(t *testing.T) {
+		marks := DijkstraSingleSource(extractor, from, SimpleWeightFunc)
+		for name, heuristic := range map[string]HeuristicFunc{
+			"manhattan": ManhattanHeuristic(coords, to),
+			"euclidean": EuclideanHeuristic(coords, to),
+			"zero": func(VertexId) float64 { return 0.0 },
+		} {
+			path, weight := AStar(extractor, from, to, SimpleWeightFunc, heuristic)
+			expectEquals(t, weight, marks[to].Weight)
+			expectEquals(t, len(path), int(weight)+1)
+			expectEquals(t, path[0], from)
+			expectEquals(t, path[len(path)-1], to)
+			for i:=1; i<len(path); i++ {
+				expectTrue(t, gr.CheckEdge(path[i-1], path[i]), name+" path edge exists")
+			}
+		}
+	})
+
+	t.Run("explores less than dijkstra", func(t *testing.T) {
+		counter := &countingExtractor{OutNeighboursExtractor: extractor}
+		AStar(counter, node(0, 0), node(9, 0), SimpleWeightFunc, ManhattanHeuristic(coords, node(9, 0)))
+		aStarCalls := counter.calls
+		counter.calls = 0
+		DijkstraSingleSource(counter, node(0, 0), SimpleWeightFunc, node(9, 0))
+		expectTrue(t, aStarCalls*3 < counter.calls, "A* visits much less vertexes")
+	})
+
+	t.Run("trivial paths", func(t *testing.T) {
+		path, weight := AStar(extractor, from, from, SimpleWeightFunc, ManhattanHeuristic(coords, from))
+		expectPath(t, path, from)
+		expectEquals(t, weight, 0.0)
+
+		gr.AddNode(1000)
+		defer gr.RemoveNode(1000)
+		path, weight = AStar(extractor, from, 1000, SimpleWeightFunc, func(VertexId) float64 { return 0.0 })
+		expectEquals(t, len(path), 0)
+		expectEquals(t, weight, -1.0)
+	})
+
+	t.Run("stop func", func(t *testing.T) {
+		path, _ := AStarWithStop(extractor, from, to, SimpleWeightFunc, ManhattanHeuristic(coords, to), func(node VertexId, weight float64) bool {
+			return weight > 10
+		})
+		expectEquals(t, len(path), 0)
+		// hole in the wall is forbidden
+		_, weight := AStarWithStop(extractor, node(0, 0), node(9, 0), SimpleWeightFunc, ManhattanHeuristic(coords, node(9, 0)), func(n VertexId, weight float64) bool {
+			return n==node(5, 0)
+		})
+		expectEquals(t, weight, -1.0)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		negative := func(tail, head VertexId) float64 { return -1.0 }
+		_, _, err := TryAStar(extractor, from, to, negative, ManhattanHeuristic(coords, to))
+		expectErrorIs(t, err, ErrNegativeWeight)
+		expectPanic(t, "negative weight", func() { AStar(extractor, from, to, negative, ManhattanHeuristic(coords, to)) })
+
+		// overestimating heuristic
+		heuristic := func(node VertexId) float64 { return 3*ManhattanHeuristic(coords, to)(node) }
+		_, _, err = TryAStar(extractor, from, to, SimpleWeightFunc, heuristic)
+		expectTrue(t, err==nil, "no consistency check without debug")
+		DebugChecks = true
+		defer func() { DebugChecks = false }()
+		_, _, err = TryAStar(extractor, from, to, SimpleWeightFunc, heuristic)
+		expectErrorIs(t, err, ErrInconsistentHeuristic)
+		_, _, err = TryAStar(extractor, from, to, SimpleWeightFunc, func(VertexId) float64 { return 1.0 })
+		expectErrorIs(t, err, ErrInconsistentHeuristic)
+		_, _, err = TryAStar(extractor, from, to, SimpleWeightFunc, EuclideanHeuristic(coords, to))
+		expectTrue(t, err==nil, "euclidean heuristic is consistent")
+
+		noCoords := AttributesCoords(NewGraphAttributes(), xAttr, yAttr)
+		expectPanic(t, "vertex without coordinates", func() { noCoords(1) })
+	})
+}
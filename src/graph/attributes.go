@@ -0,0 +1,357 @@
+package graph
+
+import (
+	"fmt"
+)
+
+// Vertex or connection attributes: attribute name -> value.
+type Attributes map[string]interface{}
+
+// Shallow copy of attributes.
+func (attrs Attributes) Clone() Attributes {
+	res := make(Attributes, len(attrs))
+	for name, value := range attrs {
+		res[name] = value
+	}
+	return res
+}
+
+// Typed attribute name.
+//
+// Used to get and set attributes values without type assertions:
+//
+//	var Color = AttributeKey[string]("color")
+//	Color.Set(attrs.Vertex(1), "red")
+//	color, ok := Color.Get(attrs.Vertex(1))
+type AttributeKey[T any] string
+
+// Getting attribute value.
+//
+// ok is false if there is no such attribute or it has another type.
+func (key AttributeKey[T]) Get(attrs Attributes) (value T, ok bool) {
+	value, ok = attrs[string(key)].(T)
+	return
+}
+
+// Setting attribute value.
+func (key AttributeKey[T]) Set(attrs Attributes, value T) {
+	attrs[string(key)] = value
+}
+
+// Attributes of graph vertexes and connections.
+//
+// Connections are stored by agreement: edges with tail not greater than
+// head (see NewUndirectedConnection) and reversed arcs as direct ones. So
+// it doesn't matter which nodes order is used to access edge attributes.
+type GraphAttributes struct {
+	vertexes map[VertexId]Attributes
+	connections map[TypedConnection]Attributes
+}
+
+func NewGraphAttributes() *GraphAttributes {
+	return &GraphAttributes{
+		vertexes: make(map[VertexId]Attributes),
+		connections: make(map[TypedConnection]Attributes),
+	}
+}
+
+func normalizeConnection(conn TypedConnection) TypedConnection {
+	switch conn.Type {
+		case CT_UNDIRECTED:
+			return NewUndirectedConnection(conn.Tail, conn.Head)
+		case CT_DIRECTED_REVERSED:
+			return NewDirectedConnection(conn.Head, conn.Tail)
+	}
+	return conn
+}
+
+// Getting vertex attributes.
+//
+// Empty attributes are created if vertex doesn't have them yet, so result
+// could be modified directly.
+func (ga *GraphAttributes) Vertex(node VertexId) Attributes {
+	attrs, ok := ga.vertexes[node]
+	if !ok {
+		attrs = make(Attributes)
+		ga.vertexes[node] = attrs
+	}
+	return attrs
+}
+
+// Getting vertex attributes without creating them.
+func (ga *GraphAttributes) LookupVertex(node VertexId) (Attributes, bool) {
+	attrs, ok := ga.vertexes[node]
+	return attrs, ok
+}
+
+// Getting connection attributes.
+//
+// Empty attributes are created if connection doesn't have them yet, so result
+// could be modified directly.
+func (ga *GraphAttributes) Connection(conn TypedConnection) Attributes {
+	conn = normalizeConnection(conn)
+	attrs, ok := ga.connections[conn]
+	if !ok {
+		attrs = make(Attributes)
+		ga.connections[conn] = attrs
+	}
+	return attrs
+}
+
+// Getting connection attributes without creating them.
+func (ga *GraphAttributes) LookupConnection(conn TypedConnection) (Attributes, bool) {
+	attrs, ok := ga.connections[normalizeConnection(conn)]
+	return attrs, ok
+}
+
+// Getting arc attributes.
+//
+// Synonym to Connection(NewDirectedConnection(tail, head)).
+func (ga *GraphAttributes) Arc(tail, head VertexId) Attributes {
+	return ga.Connection(NewDirectedConnection(tail, head))
+}
+
+// Getting edge attributes.
+//
+// Synonym to Connection(NewUndirectedConnection(node1, node2)).
+func (ga *GraphAttributes) Edge(node1, node2 VertexId) Attributes {
+	return ga.Connection(NewUndirectedConnection(node1, node2))
+}
+
+// Removing all vertex attributes.
+func (ga *GraphAttributes) RemoveVertex(node VertexId) {
+	delete(ga.vertexes, node)
+}
+
+// Removing all connection attributes.
+func (ga *GraphAttributes) RemoveConnection(conn TypedConnection) {
+	delete(ga.connections, normalizeConnection(conn))
+}
+
+// Copy vertex attributes from another storage.
+//
+// Nothing is done if source vertex doesn't have attributes.
+func (ga *GraphAttributes) copyVertex(from *GraphAttributes, node VertexId) {
+	if attrs, ok := from.LookupVertex(node); ok {
+		ga.vertexes[node] = attrs.Clone()
+	}
+}
+
+// Copy connection and it's vertexes attributes from another storage.
+//
+// conn is a connection in destination graph. fromConns are possible keys of
+// the same connection in source graph, first existing one is used.
+func (ga *GraphAttributes) copyConnection(from *GraphAttributes, conn TypedConnection, fromConns ...TypedConnection) {
+	ga.copyVertex(from, conn.Tail)
+	ga.copyVertex(from, conn.Head)
+	for _, fromConn := range fromConns {
+		if attrs, ok := from.LookupConnection(fromConn); ok {
+			ga.connections[normalizeConnection(conn)] = attrs.Clone()
+			return
+		}
+	}
+}
+
+// Graph with vertexes and connections attributes.
+type AttributedGraph interface {
+	Attributes() *GraphAttributes
+}
+
+// Getting attributes storages of copy source and destination.
+//
+// Returns nils if one of them doesn't have attributes.
+func attributesForCopy(from, to interface{}) (*GraphAttributes, *GraphAttributes) {
+	fromAttributed, ok := from.(AttributedGraph)
+	if !ok {
+		return nil, nil
+	}
+	toAttributed, ok := to.(AttributedGraph)
+	if !ok {
+		return nil, nil
+	}
+	return fromAttributed.Attributes(), toAttributed.Attributes()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Directed graph with vertexes and arcs attributes.
+//
+// Attributes are removed together with arcs and nodes, if they are removed
+// through this wrapper.
+type AttributedDirectedGraph struct {
+	DirectedGraph
+	directedGraphSeqs
+	attrs *GraphAttributes
+}
+
+func NewAttributedDirectedGraph(gr DirectedGraph) *AttributedDirectedGraph {
+	return &AttributedDirectedGraph{
+		DirectedGraph: gr,
+		directedGraphSeqs: directedGraphSeqs{gr},
+		attrs: NewGraphAttributes(),
+	}
+}
+
+func (g *AttributedDirectedGraph) Attributes() *GraphAttributes {
+	return g.attrs
+}
+
+// Removing node with all it's arcs and attributes from graph
+func (g *AttributedDirectedGraph) RemoveNode(node VertexId) {
+	if g.CheckNode(node) {
+		for accessor := range VertexesSeqOf(g.GetAccessors(node)) {
+			g.attrs.RemoveConnection(NewDirectedConnection(node, accessor))
+		}
+		for predecessor := range VertexesSeqOf(g.GetPredecessors(node)) {
+			g.attrs.RemoveConnection(NewDirectedConnection(predecessor, node))
+		}
+		g.attrs.RemoveVertex(node)
+	}
+	g.DirectedGraph.RemoveNode(node)
+}
+
+// Removing arc with it's attributes from graph
+func (g *AttributedDirectedGraph) RemoveArc(from, to VertexId) {
+	g.DirectedGraph.RemoveArc(from, to)
+	g.attrs.RemoveConnection(NewDirectedConnection(from, to))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Undirected graph with vertexes and edges attributes.
+//
+// Attributes are removed together with edges and nodes, if they are removed
+// through this wrapper.
+type AttributedUndirectedGraph struct {
+	UndirectedGraph
+	undirectedGraphSeqs
+	attrs *GraphAttributes
+}
+
+func NewAttributedUndirectedGraph(gr UndirectedGraph) *AttributedUndirectedGraph {
+	return &AttributedUndirectedGraph{
+		UndirectedGraph: gr,
+		undirectedGraphSeqs: undirectedGraphSeqs{gr},
+		attrs: NewGraphAttributes(),
+	}
+}
+
+func (g *AttributedUndirectedGraph) Attributes() *GraphAttributes {
+	return g.attrs
+}
+
+// Removing node with all it's edges and attributes from graph
+func (g *AttributedUndirectedGraph) RemoveNode(node VertexId) {
+	if g.CheckNode(node) {
+		for neighbour := range VertexesSeqOf(g.GetNeighbours(node)) {
+			g.attrs.RemoveConnection(NewUndirectedConnection(node, neighbour))
+		}
+		g.attrs.RemoveVertex(node)
+	}
+	g.UndirectedGraph.RemoveNode(node)
+}
+
+// Removing edge with it's attributes from graph
+func (g *AttributedUndirectedGraph) RemoveEdge(node1, node2 VertexId) {
+	g.UndirectedGraph.RemoveEdge(node1, node2)
+	g.attrs.RemoveConnection(NewUndirectedConnection(node1, node2))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Mixed graph with vertexes and connections attributes.
+//
+// Attributes are removed together with connections and nodes, if they are
+// removed through this wrapper.
+type AttributedMixedGraph struct {
+	MixedGraph
+	mixedGraphSeqs
+	attrs *GraphAttributes
+}
+
+func NewAttributedMixedGraph(gr MixedGraph) *AttributedMixedGraph {
+	return &AttributedMixedGraph{
+		MixedGraph: gr,
+		mixedGraphSeqs: mixedGraphSeqs{gr},
+		attrs: NewGraphAttributes(),
+	}
+}
+
+func (g *AttributedMixedGraph) Attributes() *GraphAttributes {
+	return g.attrs
+}
+
+// Removing node with all it's connections and attributes from graph
+func (g *AttributedMixedGraph) RemoveNode(node VertexId) {
+	if g.CheckNode(node) {
+		for accessor := range VertexesSeqOf(g.GetAccessors(node)) {
+			g.attrs.RemoveConnection(NewDirectedConnection(node, accessor))
+		}
+		for predecessor := range VertexesSeqOf(g.GetPredecessors(node)) {
+			g.attrs.RemoveConnection(NewDirectedConnection(predecessor, node))
+		}
+		for neighbour := range VertexesSeqOf(g.GetNeighbours(node)) {
+			g.attrs.RemoveConnection(NewUndirectedConnection(node, neighbour))
+		}
+		g.attrs.RemoveVertex(node)
+	}
+	g.MixedGraph.RemoveNode(node)
+}
+
+// Removing arc with it's attributes from graph
+func (g *AttributedMixedGraph) RemoveArc(from, to VertexId) {
+	g.MixedGraph.RemoveArc(from, to)
+	g.attrs.RemoveConnection(NewDirectedConnection(from, to))
+}
+
+// Removing edge with it's attributes from graph
+func (g *AttributedMixedGraph) RemoveEdge(node1, node2 VertexId) {
+	g.MixedGraph.RemoveEdge(node1, node2)
+	g.attrs.RemoveConnection(NewUndirectedConnection(node1, node2))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Dot style function for vertexes, which takes style properties from
+// vertexes attributes.
+//
+// Style starts with SimpleNodeStyle. Then attributes with given names (or all
+// attributes if there are no names) are added as style properties.
+func AttributesNodeStyle(attrs *GraphAttributes, names ...string) DotNodeStyleFunc {
+	return func(node VertexId) map[string]string {
+		style := SimpleNodeStyle(node)
+		if nodeAttrs, ok := attrs.LookupVertex(node); ok {
+			addAttributesToStyle(style, nodeAttrs, names)
+		}
+		return style
+	}
+}
+
+// Dot style function for connections, which takes style properties from
+// connections attributes.
+//
+// Style starts with SimpleConnectionStyle. Then attributes with given names
+// (or all attributes if there are no names) are added as style properties.
+func AttributesConnectionStyle(attrs *GraphAttributes, names ...string) DotConnectionStyleFunc {
+	return func(conn TypedConnection) map[string]string {
+		style := SimpleConnectionStyle(conn)
+		if connAttrs, ok := attrs.LookupConnection(conn); ok {
+			addAttributesToStyle(style, connAttrs, names)
+		}
+		return style
+	}
+}
+
+func addAttributesToStyle(style map[string]string, attrs Attributes, names []string) {
+	if len(names)==0 {
+		for name, value := range attrs {
+			style[name] = fmt.Sprint(value)
+		}
+		return
+	}
+	for _, name := range names {
+		if value, ok := attrs[name]; ok {
+			style[name] = fmt.Sprint(value)
+		}
+	}
+}
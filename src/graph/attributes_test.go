@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var (
+	colorAttr = AttributeKey[string]("color")
+	weightAttr = AttributeKey[int]("weight")
+)
+
+func TestGraphAttributes(t *testing.T) {
+	t.Run("Typed keys", func(t *testing.T) {
+		attrs := NewGraphAttributes()
+		colorAttr.Set(attrs.Vertex(1), "red")
+		color, ok := colorAttr.Get(attrs.Vertex(1))
+		expectTrue(t, ok, "color is set")
+		expectEquals(t, color, "red")
+
+		_, ok = weightAttr.Get(attrs.Vertex(1))
+		expectFalse(t, ok, "weight is set")
+		attrs.Vertex(1)["weight"] = "heavy"
+		_, ok = weightAttr.Get(attrs.Vertex(1))
+		expectFalse(t, ok, "weight with wrong type is set")
+	})
+
+	t.Run("Edges don't depend on nodes order", func(t *testing.T) {
+		attrs := NewGraphAttributes()
+		colorAttr.Set(attrs.Edge(2, 1), "blue")
+		color, _ := colorAttr.Get(attrs.Edge(1, 2))
+		expectEquals(t, color, "blue")
+		_, ok := attrs.LookupConnection(NewDirectedConnection(1, 2))
+		expectFalse(t, ok, "arc attributes exist")
+	})
+}
+
+func TestAttributedGraphs(t *testing.T) {
+	t.Run("Remove arc and node", func(t *testing.T) {
+		gr := NewAttributedDirectedGraph(NewDirectedMap())
+		gr.AddArc(1, 2)
+		gr.AddArc(2, 3)
+		colorAttr.Set(gr.Attributes().Arc(1, 2), "red")
+		colorAttr.Set(gr.Attributes().Arc(2, 3), "green")
+		colorAttr.Set(gr.Attributes().Vertex(3), "blue")
+
+		gr.RemoveArc(1, 2)
+		_, ok := gr.Attributes().LookupConnection(NewDirectedConnection(1, 2))
+		expectFalse(t, ok, "removed arc attributes exist")
+
+		gr.RemoveNode(3)
+		_, ok = gr.Attributes().LookupConnection(NewDirectedConnection(2, 3))
+		expectFalse(t, ok, "arc of removed node attributes exist")
+		_, ok = gr.Attributes().LookupVertex(3)
+		expectFalse(t, ok, "removed node attributes exist")
+	})
+
+	t.Run("Remove node in mixed graph", func(t *testing.T) {
+		gr := NewAttributedMixedGraph(NewMixedMap())
+		gr.AddArc(1, 2)
+		gr.AddEdge(2, 3)
+		gr.AddArc(1, 3)
+		colorAttr.Set(gr.Attributes().Edge(3, 2), "red")
+		colorAttr.Set(gr.Attributes().Arc(1, 3), "red")
+		colorAttr.Set(gr.Attributes().Arc(1, 2), "red")
+
+		gr.RemoveNode(3)
+		_, ok := gr.Attributes().LookupConnection(NewUndirectedConnection(2, 3))
+		expectFalse(t, ok, "edge attributes exist")
+		_, ok = gr.Attributes().LookupConnection(NewDirectedConnection(1, 3))
+		expectFalse(t, ok, "arc attributes exist")
+		_, ok = gr.Attributes().LookupConnection(NewDirectedConnection(1, 2))
+		expectTrue(t, ok, "remaining arc attributes exist")
+	})
+
+	t.Run("Copy directed graph", func(t *testing.T) {
+		gr := NewAttributedDirectedGraph(NewDirectedMap())
+		gr.AddArc(1, 2)
+		colorAttr.Set(gr.Attributes().Arc(1, 2), "red")
+		colorAttr.Set(gr.Attributes().Vertex(2), "blue")
+
+		gr2 := NewAttributedDirectedGraph(NewDirectedMap())
+		CopyDirectedGraph(gr, gr2)
+		color, _ := colorAttr.Get(gr2.Attributes().Arc(1, 2))
+		expectEquals(t, color, "red")
+		color, _ = colorAttr.Get(gr2.Attributes().Vertex(2))
+		expectEquals(t, color, "blue")
+
+		// attributes are copied, not shared
+		colorAttr.Set(gr2.Attributes().Vertex(2), "green")
+		color, _ = colorAttr.Get(gr.Attributes().Vertex(2))
+		expectEquals(t, color, "blue")
+	})
+
+	t.Run("Copy mixed graph", func(t *testing.T) {
+		gr := NewAttributedMixedGraph(NewMixedMap())
+		gr.AddArc(1, 2)
+		gr.AddEdge(2, 3)
+		colorAttr.Set(gr.Attributes().Edge(2, 3), "red")
+
+		gr2 := NewAttributedMixedGraph(NewMixedMatrix(3))
+		CopyMixedGraph(gr, gr2)
+		color, _ := colorAttr.Get(gr2.Attributes().Edge(3, 2))
+		expectEquals(t, color, "red")
+		expectTrue(t, MixedGraphsEquals(gr, gr2), "graphs are equal")
+	})
+
+	t.Run("Dot styles", func(t *testing.T) {
+		gr := NewAttributedDirectedGraph(NewDirectedMap())
+		gr.AddArc(1, 2)
+		colorAttr.Set(gr.Attributes().Vertex(1), "red")
+		weightAttr.Set(gr.Attributes().Vertex(1), 5)
+		colorAttr.Set(gr.Attributes().Arc(1, 2), "blue")
+
+		buf := &bytes.Buffer{}
+		PlotDgraphToDot(gr, buf, AttributesNodeStyle(gr.Attributes(), "color"), AttributesConnectionStyle(gr.Attributes()))
+		out := buf.String()
+		expectTrue(t, strings.Contains(out, `n1[color="red",label="1"];`), "node style in "+out)
+		expectTrue(t, strings.Contains(out, `n2[label="2"];`), "default node style in "+out)
+		expectTrue(t, strings.Contains(out, `n1->n2[color="blue"];`), "arc style in "+out)
+	})
+}
@@ -0,0 +1,218 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Join path from forward marks (from source to meeting vertex) with path from
+// backward marks (from meeting vertex to target).
+func joinBidirectionalPath(forward, backward PathMarks, meet VertexId) []VertexId {
+	path := PathFromMarks(forward, meet)
+	tail := PathFromMarks(backward, meet)
+	// tail is from target to meeting vertex, which is already in path
+	for i:=len(tail)-2; i>=0; i-- {
+		path = append(path, tail[i])
+	}
+	return path
+}
+
+// Find path with minimal number of connections with bidirectional breadth-first search
+//
+// Search expands from both ends level by level (always the smaller frontier)
+// and stops when frontiers meet. Returns nil if there is no path.
+func BidirectionalBFS(gr NeighboursExtractor, from, to VertexId) []VertexId {
+	path, err := TryBidirectionalBFS(gr, from, to)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Find path with bidirectional BFS.", err, 1)
+		erxErr.AddV("from", from)
+		erxErr.AddV("to", to)
+		panic(erxErr)
+	}
+	return path
+}
+
+// Error-returning version of BidirectionalBFS.
+//
+// Panics from gr are returned as errors.
+func TryBidirectionalBFS(gr NeighboursExtractor, from, to VertexId) (path []VertexId, err error) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			path, err = nil, recoveredToError(e)
+		}
+	}()
+	
+	forward := PathMarks{from: &VertexPathMark{Weight: 0.0, PrevVertex: from}}
+	backward := PathMarks{to: &VertexPathMark{Weight: 0.0, PrevVertex: to}}
+	if from==to {
+		return []VertexId{from}, nil
+	}
+	forwardFrontier := Vertexes{from}
+	backwardFrontier := Vertexes{to}
+	
+	for len(forwardFrontier)>0 && len(backwardFrontier)>0 {
+		var meet VertexId
+		met := false
+		if len(forwardFrontier)<=len(backwardFrontier) {
+			forwardFrontier, meet, met = expandBFSLevel(forwardFrontier, forward, backward, gr.GetOutNeighbours)
+		} else {
+			backwardFrontier, meet, met = expandBFSLevel(backwardFrontier, backward, forward, gr.GetInNeighbours)
+		}
+		if met {
+			return joinBidirectionalPath(forward, backward, meet), nil
+		}
+	}
+	
+	return nil, nil
+}
+
+// Expand one BFS level from frontier
+//
+// Returns next frontier. If some of reached vertexes is already marked by
+// the opposite search, returns it as meeting vertex.
+func expandBFSLevel(frontier Vertexes, marks, opposite PathMarks, neighbours func(VertexId) VertexesIterable) (Vertexes, VertexId, bool) {
+	next := make(Vertexes, 0)
+	for _, curNode := range frontier {
+		curWeight := marks[curNode].Weight
+		for nextNode := range VertexesSeqOf(neighbours(curNode)) {
+			if _, ok := marks[nextNode]; ok {
+				continue
+			}
+			marks[nextNode] = &VertexPathMark{Weight: curWeight+1, PrevVertex: curNode}
+			if _, ok := opposite[nextNode]; ok {
+				return nil, nextNode, true
+			}
+			next = append(next, nextNode)
+		}
+	}
+	return next, 0, false
+}
+
+// Find shortest path with bidirectional Dijkstra algorithm
+//
+// Forward search goes from source by out neighbours, backward search goes from
+// target by in neighbours (weightFunc is called with original connection
+// direction in both cases). Search stops when sum of minimal weights in both
+// queues isn't less than the best found path. Returns path and it's weight,
+// or nil and -1.0 if there is no path.
+//
+// All weights must be non-negative, otherwise function panics.
+func BidirectionalDijkstra(gr NeighboursExtractor, from, to VertexId, weightFunc ConnectionWeightFunc) ([]VertexId, float64) {
+	path, weight, err := TryBidirectionalDijkstra(gr, from, to, weightFunc)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Find shortest path with bidirectional Dijkstra algorithm.", err, 1)
+		erxErr.AddV("from", from)
+		erxErr.AddV("to", to)
+		panic(erxErr)
+	}
+	return path, weight
+}
+
+// Error-returning version of BidirectionalDijkstra.
+//
+// Returns *ConnectionError with ErrNegativeWeight if weightFunc returns
+// negative value for any checked connection. Panics from gr are returned as
+// errors too.
+func TryBidirectionalDijkstra(gr NeighboursExtractor, from, to VertexId, weightFunc ConnectionWeightFunc) (path []VertexId, weight float64, err error) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			path, weight, err = nil, -1.0, recoveredToError(e)
+		}
+	}()
+	
+	forward := &dijkstraFrontier{
+		marks: PathMarks{from: &VertexPathMark{Weight: 0.0, PrevVertex: from}},
+		queue: NewBinaryHeap(10),
+		settled: make(map[VertexId]bool),
+		neighbours: gr.GetOutNeighbours,
+		weight: weightFunc,
+		reversed: false,
+	}
+	backward := &dijkstraFrontier{
+		marks: PathMarks{to: &VertexPathMark{Weight: 0.0, PrevVertex: to}},
+		queue: NewBinaryHeap(10),
+		settled: make(map[VertexId]bool),
+		neighbours: gr.GetInNeighbours,
+		weight: weightFunc,
+		reversed: true,
+	}
+	forward.queue.Push(from, 0.0)
+	backward.queue.Push(to, 0.0)
+	
+	best := math.Inf(1)
+	var meet VertexId
+	if from==to {
+		best, meet = 0.0, from
+	}
+	
+	for !forward.queue.Empty() && !backward.queue.Empty() {
+		_, forwardMin := forward.queue.Peek()
+		_, backwardMin := backward.queue.Peek()
+		if forwardMin+backwardMin >= best {
+			break
+		}
+		cur, opposite := forward, backward
+		if backwardMin<forwardMin {
+			cur, opposite = backward, forward
+		}
+		node, weight, err := cur.step(opposite)
+		if err!=nil {
+			return nil, -1.0, err
+		}
+		if weight<best {
+			best, meet = weight, node
+		}
+	}
+	
+	if math.IsInf(best, 1) {
+		return nil, -1.0, nil
+	}
+	return joinBidirectionalPath(forward.marks, backward.marks, meet), best, nil
+}
+
+// One direction of bidirectional Dijkstra search.
+type dijkstraFrontier struct {
+	marks PathMarks
+	queue *BinaryHeap
+	settled map[VertexId]bool
+	neighbours func(VertexId) VertexesIterable
+	weight ConnectionWeightFunc
+	reversed bool // backward search goes against connections direction
+}
+
+// Settle next vertex from queue and relax it's connections
+//
+// Returns best path weight through vertexes, marked by both searches, found
+// on this step (and the meeting vertex), or +Inf.
+func (f *dijkstraFrontier) step(opposite *dijkstraFrontier) (VertexId, float64, error) {
+	curNode, curWeight := f.queue.Pop()
+	f.settled[curNode] = true
+	
+	best := math.Inf(1)
+	var meet VertexId
+	for nextNode := range VertexesSeqOf(f.neighbours(curNode)) {
+		tail, head := curNode, nextNode
+		if f.reversed {
+			tail, head = nextNode, curNode
+		}
+		connWeight := f.weight(tail, head)
+		if connWeight < 0 {
+			return 0, 0.0, &ConnectionError{Op: "bidirectional dijkstra", Tail: tail, Head: head, Err: ErrNegativeWeight}
+		}
+		if f.settled[nextNode] {
+			continue
+		}
+		nextWeight := curWeight + connWeight
+		if mark, ok := f.marks[nextNode]; !ok || nextWeight < mark.Weight {
+			f.marks[nextNode] = &VertexPathMark{Weight: nextWeight, PrevVertex: curNode}
+			PushOrDecreaseKey(f.queue, nextNode, nextWeight)
+		}
+		if oppositeMark, ok := opposite.marks[nextNode]; ok {
+			if pathWeight := f.marks[nextNode].Weight + oppositeMark.Weight; pathWeight<best {
+				best, meet = pathWeight, nextNode
+			}
+		}
+	}
+	return meet, best, nil
+}
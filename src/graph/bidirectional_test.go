@@ -0,0 +1,137 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// Random weights, same for both directions of connection.
+func symmetricWeightFunc(seed int64) ConnectionWeightFunc {
+	return func(tail, head VertexId) float64 {
+		if tail>head {
+			tail, head = head, tail
+		}
+		return float64((int64(tail)*7919 + int64(head)*104729 + seed) % 10)
+	}
+}
+
+func expectPathIsValid(t *testing.T, path []VertexId, from, to VertexId, check func(tail, head VertexId) bool) {
+	t.Helper()
+	expectEquals(t, path[0], from)
+	expectEquals(t, path[len(path)-1], to)
+	for i:=1; i<len(path); i++ {
+		expectTrue(t, check(path[i-1], path[i]), "path connection exists")
+	}
+}
+
+func TestBidirectionalSearch(t *testing.T) {
+	t.Run("directed", func(t *testing.T) {
+		gr := generateDirectedGraph1()
+		extractor := NewDgraphNeighboursExtractor(gr)
+		expectPath(t, BidirectionalBFS(extractor, 1, 5), 1, 2, 4, 5)
+		expectPath(t, BidirectionalBFS(extractor, 3, 3), 3)
+		expectEquals(t, len(BidirectionalBFS(extractor, 5, 1)), 0)
+
+		path, weight := BidirectionalDijkstra(extractor, 1, 5, SimpleWeightFunc)
+		expectPath(t, path, 1, 2, 4, 5)
+		expectEquals(t, weight, 3.0)
+		path, weight = BidirectionalDijkstra(extractor, 6, 2, SimpleWeightFunc)
+		expectEquals(t, len(path), 0)
+		expectEquals(t, weight, -1.0)
+		path, weight = BidirectionalDijkstra(extractor, 4, 4, SimpleWeightFunc)
+		expectPath(t, path, 4)
+		expectEquals(t, weight, 0.0)
+	})
+
+	t.Run("random graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		size := 60
+		for i:=0; i<20; i++ {
+			gr := NewMixedMap()
+			for node:=0; node<size; node++ {
+				gr.AddNode(VertexId(node))
+			}
+			for j:=0; j<size*2; j++ {
+				tail, head := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+				if tail==head || gr.CheckEdgeType(tail, head)!=CT_NONE {
+					continue
+				}
+				if rnd.Intn(2)==0 {
+					gr.AddArc(tail, head)
+				} else {
+					gr.AddEdge(tail, head)
+				}
+			}
+			weightFunc := symmetricWeightFunc(int64(i))
+			checkConnection := func(tail, head VertexId) bool {
+				connType := gr.CheckEdgeType(tail, head)
+				return connType==CT_DIRECTED || connType==CT_UNDIRECTED
+			}
+			extractor := NewMgraphNeighboursExtractor(gr)
+			for j:=0; j<10; j++ {
+				from, to := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+				marks := DijkstraSingleSource(NewMgraphOutNeighboursExtractor(gr), from, weightFunc)
+				unitMarks := DijkstraSingleSource(NewMgraphOutNeighboursExtractor(gr), from, SimpleWeightFunc)
+
+				path, weight := BidirectionalDijkstra(extractor, from, to, weightFunc)
+				bfsPath := BidirectionalBFS(extractor, from, to)
+				mark, ok := marks[to]
+				if !ok {
+					expectEquals(t, len(path), 0)
+					expectEquals(t, len(bfsPath), 0)
+					continue
+				}
+				expectEquals(t, weight, mark.Weight)
+				expectPathIsValid(t, path, from, to, checkConnection)
+				pathWeight := 0.0
+				for k:=1; k<len(path); k++ {
+					pathWeight += weightFunc(path[k-1], path[k])
+				}
+				expectEquals(t, pathWeight, weight)
+
+				expectEquals(t, float64(len(bfsPath)-1), unitMarks[to].Weight)
+				expectPathIsValid(t, bfsPath, from, to, checkConnection)
+			}
+		}
+	})
+
+	t.Run("undirected", func(t *testing.T) {
+		gr := NewUndirectedMap()
+		ReadUgraphLine(gr, "1-2-3-4-5")
+		ReadUgraphLine(gr, "1-6-5")
+		extractor := NewUgraphNeighboursExtractor(gr)
+		expectPath(t, BidirectionalBFS(extractor, 5, 1), 5, 6, 1)
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==6 || head==6 {
+				return 10.0
+			}
+			return 1.0
+		}
+		path, weight := BidirectionalDijkstra(extractor, 1, 5, weightFunc)
+		expectPath(t, path, 1, 2, 3, 4, 5)
+		expectEquals(t, weight, 4.0)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		gr := generateDirectedGraph1()
+		extractor := NewDgraphNeighboursExtractor(gr)
+		negative := func(tail, head VertexId) float64 {
+			if tail==4 && head==5 {
+				return -1.0
+			}
+			return 1.0
+		}
+		_, _, err := TryBidirectionalDijkstra(extractor, 1, 5, negative)
+		expectErrorIs(t, err, ErrNegativeWeight)
+		var connErr *ConnectionError
+		expectTrue(t, errors.As(err, &connErr), "connection error")
+		expectEquals(t, connErr.Tail, VertexId(4))
+		expectEquals(t, connErr.Head, VertexId(5))
+		expectPanic(t, "negative weight", func() { BidirectionalDijkstra(extractor, 1, 5, negative) })
+
+		_, err = TryBidirectionalBFS(extractor, 1, 100)
+		expectTrue(t, err!=nil, "error for unknown node")
+		expectPanic(t, "unknown node", func() { BidirectionalBFS(extractor, 100, 1) })
+	})
+}
@@ -1,7 +1,7 @@
 package graph
 
 import (
-	"github.com/StepLg/go-erx/src/erx"
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 // Check two mixed graph equality
@@ -20,11 +20,8 @@ func MixedGraphsEquals(gr1, gr2 MixedGraphReader) bool {
 }
 
 // Check if graph gr include all connections
-//
-// Warning!!! Due to channels issue 296: http://code.google.com/p/go/issues/detail?id=296
-// goroutine will block if not all connections exists in graph
 func MixedGraphIncludeConnections(gr MixedGraphReader, connections TypedConnectionsIterable) bool {
-	for conn := range connections.TypedConnectionsIter() {
+	for conn := range TypedConnectionsSeqOf(connections) {
 		switch conn.Type {
 			case CT_UNDIRECTED:
 				if !gr.CheckEdge(conn.Tail, conn.Head) {
@@ -43,11 +40,8 @@ func MixedGraphIncludeConnections(gr MixedGraphReader, connections TypedConnecti
 }
 
 // Check if graph gr include all nodes from nodesToCheck
-//
-// Warning!!! Due to channels issue 296: http://code.google.com/p/go/issues/detail?id=296
-// goroutine will block if not all nodes exists in graph
 func GraphIncludeVertexes(gr VertexesChecker, nodesToCheck VertexesIterable) bool {
-	for node := range nodesToCheck.VertexesIter() {
+	for node := range VertexesSeqOf(nodesToCheck) {
 		if !gr.CheckNode(node) {
 			return false
 		}
@@ -56,11 +50,8 @@ func GraphIncludeVertexes(gr VertexesChecker, nodesToCheck VertexesIterable) boo
 }
 
 // Check if graph gr include all edges from edgesToCheck
-//
-// Warning!!! Due to channels issue 296: http://code.google.com/p/go/issues/detail?id=296
-// goroutine will block if function result is false
 func GraphIncludeEdges(gr UndirectedGraphReader, edgesToCheck EdgesIterable) bool {
-	for conn := range edgesToCheck.EdgesIter() {
+	for conn := range EdgesSeqOf(edgesToCheck) {
 		if !gr.CheckEdge(conn.Tail, conn.Head) {
 			return false
 		}
@@ -69,11 +60,8 @@ func GraphIncludeEdges(gr UndirectedGraphReader, edgesToCheck EdgesIterable) boo
 }
 
 // Check if graph gr include all arcs from edgesToCheck
-//
-// Warning!!! Due to channels issue 296: http://code.google.com/p/go/issues/detail?id=296
-// goroutine will block if function result is false
 func GraphIncludeArcs(gr DirectedGraphReader, arcsToCheck ArcsIterable) bool {
-	for conn := range arcsToCheck.ArcsIter() {
+	for conn := range ArcsSeqOf(arcsToCheck) {
 		if !gr.CheckArc(conn.Tail, conn.Head) {
 			return false
 		}
@@ -82,9 +70,6 @@ func GraphIncludeArcs(gr DirectedGraphReader, arcsToCheck ArcsIterable) bool {
 }
 
 // Check if graph gr include all arcs and all nodes from gr2
-//
-// Warning!!! Due to channels issue 296: http://code.google.com/p/go/issues/detail?id=296
-// goroutine will block if function result is false
 func DirectedGraphInclude(gr1, gr2 DirectedGraphReader) bool {
 	if !GraphIncludeVertexes(gr1, gr2) {
 		return false
@@ -98,9 +83,6 @@ func DirectedGraphInclude(gr1, gr2 DirectedGraphReader) bool {
 }
 
 // Check if graph gr include all edges and all nodes from gr2
-//
-// Warning!!! Due to channels issue 296: http://code.google.com/p/go/issues/detail?id=296
-// goroutine will block if function result is false
 func UndirectedGraphInclude(gr1, gr2 DirectedGraphReader) bool {
 	if !GraphIncludeVertexes(gr1, gr2) {
 		return false
@@ -114,9 +96,6 @@ func UndirectedGraphInclude(gr1, gr2 DirectedGraphReader) bool {
 }
 
 // Check if two directed grahps are equal
-//
-// Warning!!! Due to channels issue 296: http://code.google.com/p/go/issues/detail?id=296
-// goroutine will block if function result is false
 func DirectedGraphsEquals(gr1, gr2 DirectedGraphReader) bool {
 	if !GraphIncludeVertexes(gr1, gr2) || !GraphIncludeVertexes(gr2, gr1) {
 		return false
@@ -130,9 +109,6 @@ func DirectedGraphsEquals(gr1, gr2 DirectedGraphReader) bool {
 }
 
 // Check if two undirected grahps are equal
-//
-// Warning!!! Due to channels issue 296: http://code.google.com/p/go/issues/detail?id=296
-// goroutine will block if function result is false
 func UndirectedGraphsEquals(gr1, gr2 UndirectedGraphReader) bool {
 	if !GraphIncludeVertexes(gr1, gr2) || !GraphIncludeVertexes(gr2, gr1) {
 		return false
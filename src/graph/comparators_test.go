@@ -2,11 +2,9 @@ package graph
 
 import (
 	"testing"
-	"github.com/orfjackal/gospec/src/gospec"
-	. "github.com/orfjackal/gospec/src/gospec"
 )
 
-func ComparatorsSpec(c gospec.Context) {
+func TestComparators(t *testing.T) {
 	gr := NewMixedMatrix(10)
 	gr.AddArc(1, 2)
 	gr.AddArc(2, 3)
@@ -19,71 +17,65 @@ func ComparatorsSpec(c gospec.Context) {
 	gr.AddEdge(2, 7)
 	gr.AddArc(7, 4)
 
-	c.Specify("Graph copy", func() {
+	t.Run("Graph copy", func(t *testing.T) {
 		grcopy := NewMixedMatrix(gr.Order())
 		CopyMixedGraph(gr, grcopy)
 
-		c.Specify("includes must be true in both ways", func() {
-			c.Expect(GraphIncludeVertexes(gr, grcopy), IsTrue)
-			c.Expect(GraphIncludeVertexes(grcopy, gr), IsTrue)
+		t.Run("includes must be true in both ways", func(t *testing.T) {
+			expectTrue(t, GraphIncludeVertexes(gr, grcopy), "GraphIncludeVertexes(gr, grcopy)")
+			expectTrue(t, GraphIncludeVertexes(grcopy, gr), "GraphIncludeVertexes(grcopy, gr)")
 
-			c.Expect(MixedGraphIncludeConnections(gr, grcopy), IsTrue)
-			c.Expect(MixedGraphIncludeConnections(grcopy, gr), IsTrue)
+			expectTrue(t, MixedGraphIncludeConnections(gr, grcopy), "MixedGraphIncludeConnections(gr, grcopy)")
+			expectTrue(t, MixedGraphIncludeConnections(grcopy, gr), "MixedGraphIncludeConnections(grcopy, gr)")
 		})
 		
-		c.Specify("must be equal to original", func() {
-			c.Expect(MixedGraphsEquals(gr, grcopy), IsTrue)
-			c.Expect(MixedGraphsEquals(grcopy, gr), IsTrue)
-			c.Expect(DirectedGraphsEquals(gr, grcopy), IsTrue)
-			c.Expect(DirectedGraphsEquals(grcopy, gr), IsTrue)
-			c.Expect(UndirectedGraphsEquals(gr, grcopy), IsTrue)
-			c.Expect(UndirectedGraphsEquals(grcopy, gr), IsTrue)
+		t.Run("must be equal to original", func(t *testing.T) {
+			expectTrue(t, MixedGraphsEquals(gr, grcopy), "MixedGraphsEquals(gr, grcopy)")
+			expectTrue(t, MixedGraphsEquals(grcopy, gr), "MixedGraphsEquals(grcopy, gr)")
+			expectTrue(t, DirectedGraphsEquals(gr, grcopy), "DirectedGraphsEquals(gr, grcopy)")
+			expectTrue(t, DirectedGraphsEquals(grcopy, gr), "DirectedGraphsEquals(grcopy, gr)")
+			expectTrue(t, UndirectedGraphsEquals(gr, grcopy), "UndirectedGraphsEquals(gr, grcopy)")
+			expectTrue(t, UndirectedGraphsEquals(grcopy, gr), "UndirectedGraphsEquals(grcopy, gr)")
 		})
 		
-		c.Specify("must include all arcs in both ways", func() {
-			c.Expect(GraphIncludeArcs(gr, grcopy), IsTrue)
-			c.Expect(GraphIncludeArcs(grcopy, gr), IsTrue)
+		t.Run("must include all arcs in both ways", func(t *testing.T) {
+			expectTrue(t, GraphIncludeArcs(gr, grcopy), "GraphIncludeArcs(gr, grcopy)")
+			expectTrue(t, GraphIncludeArcs(grcopy, gr), "GraphIncludeArcs(grcopy, gr)")
 		})
 
-		c.Specify("must include all edges in both ways", func() {
-			c.Expect(GraphIncludeEdges(gr, grcopy), IsTrue)
-			c.Expect(GraphIncludeEdges(grcopy, gr), IsTrue)
+		t.Run("must include all edges in both ways", func(t *testing.T) {
+			expectTrue(t, GraphIncludeEdges(gr, grcopy), "GraphIncludeEdges(gr, grcopy)")
+			expectTrue(t, GraphIncludeEdges(grcopy, gr), "GraphIncludeEdges(grcopy, gr)")
 		})
 	})
 	
-	c.Specify("Graph copy with additional connection", func() {
+	t.Run("Graph copy with additional connection", func(t *testing.T) {
 		grcopy := NewMixedMatrix(gr.Order())
 		CopyMixedGraph(gr, grcopy)
 		grcopy.AddEdge(4, 6)
 		
-		c.Specify("includes original as a subgraph", func() {
-			c.Expect(GraphIncludeVertexes(gr, grcopy), IsTrue)
-			c.Expect(GraphIncludeVertexes(grcopy, gr), IsTrue)
+		t.Run("includes original as a subgraph", func(t *testing.T) {
+			expectTrue(t, GraphIncludeVertexes(gr, grcopy), "GraphIncludeVertexes(gr, grcopy)")
+			expectTrue(t, GraphIncludeVertexes(grcopy, gr), "GraphIncludeVertexes(grcopy, gr)")
 
-			c.Expect(MixedGraphIncludeConnections(gr, grcopy), IsFalse)
-			c.Expect(MixedGraphIncludeConnections(grcopy, gr), IsTrue)
+			expectFalse(t, MixedGraphIncludeConnections(gr, grcopy), "MixedGraphIncludeConnections(gr, grcopy)")
+			expectTrue(t, MixedGraphIncludeConnections(grcopy, gr), "MixedGraphIncludeConnections(grcopy, gr)")
 		})
 		
-		c.Specify("must not be equal to original", func() {
-			c.Expect(MixedGraphsEquals(gr, grcopy), IsFalse)
-			c.Expect(MixedGraphsEquals(grcopy, gr), IsFalse)
+		t.Run("must not be equal to original", func(t *testing.T) {
+			expectFalse(t, MixedGraphsEquals(gr, grcopy), "MixedGraphsEquals(gr, grcopy)")
+			expectFalse(t, MixedGraphsEquals(grcopy, gr), "MixedGraphsEquals(grcopy, gr)")
 		})
 
-		c.Specify("must include all arcs in both ways", func() {
-			c.Expect(GraphIncludeArcs(gr, grcopy), IsTrue)
-			c.Expect(GraphIncludeArcs(grcopy, gr), IsTrue)
+		t.Run("must include all arcs in both ways", func(t *testing.T) {
+			expectTrue(t, GraphIncludeArcs(gr, grcopy), "GraphIncludeArcs(gr, grcopy)")
+			expectTrue(t, GraphIncludeArcs(grcopy, gr), "GraphIncludeArcs(grcopy, gr)")
 		})
 
-		c.Specify("must edges from origtinal graph", func() {
-			c.Expect(GraphIncludeEdges(gr, grcopy), IsFalse)
-			c.Expect(GraphIncludeEdges(grcopy, gr), IsTrue)
+		t.Run("must edges from origtinal graph", func(t *testing.T) {
+			expectFalse(t, GraphIncludeEdges(gr, grcopy), "GraphIncludeEdges(gr, grcopy)")
+			expectTrue(t, GraphIncludeEdges(grcopy, gr), "GraphIncludeEdges(grcopy, gr)")
 		})
 
 	})
 }
-
-func TestComparators(t *testing.T) {
-	r := gospec.NewRunner()
-	r.AddSpec(ComparatorsSpec)
-	gospec.MainGoTest(r, t)
-}
@@ -0,0 +1,356 @@
+package graph
+
+import (
+	"context"
+	"iter"
+	"sort"
+)
+
+// Find any cycle in directed graph
+//
+// Returns nil if graph is acyclic. Otherwise cycle[i]->cycle[i+1] are graph
+// arcs, and so is last->first. Loop is returned as single vertex cycle.
+func FindCycle(gr DirectedGraphReader) Vertexes {
+	return findCycle(NewDgraphOutNeighboursExtractor(gr), CollectVertexes(gr), nil)
+}
+
+// Find any cycle in mixed graph
+//
+// Edges are traversable in both directions, but cycle can't go back by the
+// same edge. See FindCycle for details.
+//
+// Depth-first search can't find such cycles directly, so strongly connected
+// components are checked instead: component with arc inside always has cycle
+// through this arc, and component of edges only has cycle if it isn't a tree.
+// Parallel edges (in multigraphs) form a cycle of two vertexes.
+func FindCycle_mixed(gr MixedGraphReader) Vertexes {
+	isEdge := func(tail, head VertexId) bool {
+		return gr.CheckEdgeType(tail, head)==CT_UNDIRECTED
+	}
+	extractor := NewMgraphOutNeighboursExtractor(gr)
+	components := TarjanSCC_mixed(gr)
+	componentOf := make(map[VertexId]int)
+	for i, component := range components {
+		for _, vertex := range component {
+			componentOf[vertex] = i
+		}
+	}
+
+	for arc := range ArcsSeqOf(gr) {
+		if componentOf[arc.Tail]!=componentOf[arc.Head] {
+			continue
+		}
+		if arc.Tail==arc.Head {
+			return Vertexes{arc.Tail}
+		}
+		// path back from head to tail can't use this arc, and arc closes it
+		return findPath(extractor, arc.Head, arc.Tail)
+	}
+
+	// edges always connect vertexes of the same component
+	edgesCnt := make([]int, len(components))
+	edges := make(map[Connection]bool)
+	for edge := range EdgesSeqOf(gr) {
+		if edge.Tail==edge.Head {
+			return Vertexes{edge.Tail}
+		}
+		key := Connection{min(edge.Tail, edge.Head), max(edge.Tail, edge.Head)}
+		if edges[key] {
+			return Vertexes{edge.Tail, edge.Head}
+		}
+		edges[key] = true
+		edgesCnt[componentOf[edge.Tail]]++
+	}
+	for i, component := range components {
+		if edgesCnt[i]>=len(component) {
+			return findCycle(extractor, component, isEdge)
+		}
+	}
+	return nil
+}
+
+// Find path with minimal number of connections with breadth-first traversal
+//
+// Returns nil if there is no path.
+func findPath(gr OutNeighboursExtractor, from, to VertexId) Vertexes {
+	parent := map[VertexId]VertexId{from: from}
+	found := from==to
+	BreadthFirst(gr, Vertexes{from}, Visitor{
+		ClassifiedEdge: func(tail, head VertexId, class EdgeClass) bool {
+			if class==EC_TREE {
+				parent[head] = tail
+				found = head==to
+			}
+			return !found
+		},
+	})
+	if !found {
+		return nil
+	}
+	path := Vertexes{to}
+	for cur:=to; cur!=from; {
+		cur = parent[cur]
+		path = append(path, cur)
+	}
+	reverseVertexes(path)
+	return path
+}
+
+// Find cycle with depth-first traversal
+//
+// isEdge is used to skip going back to parent by the same undirected edge.
+func findCycle(gr OutNeighboursExtractor, vertexes Vertexes, isEdge func(tail, head VertexId) bool) Vertexes {
+	parent := make(map[VertexId]VertexId)
+	var cycle Vertexes
+	DepthFirst(gr, vertexes, Visitor{
+		StartVertex: func(node VertexId) bool {
+			parent[node] = node
+			return true
+		},
+		ClassifiedEdge: func(tail, head VertexId, class EdgeClass) bool {
+			switch class {
+				case EC_TREE:
+					parent[head] = tail
+				case EC_BACK:
+					if isEdge!=nil && head==parent[tail] && head!=tail && isEdge(tail, head) {
+						// the same edge, which was used to come to tail
+						return true
+					}
+					// head is ancestor of tail
+					cycle = Vertexes{tail}
+					for cur:=tail; cur!=head; {
+						cur = parent[cur]
+						cycle = append(cycle, cur)
+					}
+					reverseVertexes(cycle)
+					return false
+			}
+			return true
+		},
+	})
+	return cycle
+}
+
+func reverseVertexes(vertexes Vertexes) {
+	for i, j := 0, len(vertexes)-1; i<j; i, j = i+1, j-1 {
+		vertexes[i], vertexes[j] = vertexes[j], vertexes[i]
+	}
+}
+
+// Iterate all elementary cycles of directed graph with Johnson algorithm
+//
+// Elementary cycle doesn't contain repeated vertexes. Each cycle starts from
+// it's minimal vertex, cycle[i]->cycle[i+1] are graph arcs, and so is
+// last->first. Takes O((n+m)(c+1)) time for c cycles.
+func ElementaryCyclesSeq(gr DirectedGraphReader) iter.Seq[Vertexes] {
+	return elementaryCyclesSeq(NewDgraphOutNeighboursExtractor(gr), CollectVertexes(gr), nil)
+}
+
+// Iterate all elementary cycles of mixed graph with Johnson algorithm
+//
+// Edges are traversable in both directions, but cycle can't go back by the
+// same edge. Cycles, which consist only of edges, are returned once (in one
+// of directions). In multigraph two parallel edges, or edge and arc between
+// the same vertexes, make a cycle. See ElementaryCyclesSeq for details.
+func ElementaryCyclesSeq_mixed(gr MixedGraphReader) iter.Seq[Vertexes] {
+	return elementaryCyclesSeq(NewMgraphOutNeighboursExtractor(gr), CollectVertexes(gr), mixedCyclesFilter(gr))
+}
+
+// Mixed multigraph, which counts parallel connections
+type mixedMultiplicityReader interface {
+	ArcMultiplicity(from, to VertexId) int
+	EdgeMultiplicity(node1, node2 VertexId) int
+}
+
+// Filter of cycles, found in mixed graph as in directed one
+func mixedCyclesFilter(gr MixedGraphReader) func(cycle Vertexes) bool {
+	isEdge := func(tail, head VertexId) bool {
+		return gr.CheckEdgeType(tail, head)==CT_UNDIRECTED
+	}
+	singleEdge := isEdge
+	if multi, ok := gr.(mixedMultiplicityReader); ok {
+		// parallel edges, or edge together with arc, make a cycle of two vertexes
+		singleEdge = func(node1, node2 VertexId) bool {
+			return multi.EdgeMultiplicity(node1, node2)==1 &&
+				multi.ArcMultiplicity(node1, node2)==0 &&
+				multi.ArcMultiplicity(node2, node1)==0
+		}
+	}
+	return func(cycle Vertexes) bool {
+		if len(cycle)==2 && singleEdge(cycle[0], cycle[1]) {
+			// going back by the same edge
+			return false
+		}
+		if len(cycle)<3 || cycle[1]<cycle[len(cycle)-1] {
+			return true
+		}
+		// reversed version of edges only cycle is accepted instead
+		for i, tail := range cycle {
+			if !isEdge(tail, cycle[(i+1)%len(cycle)]) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Get all elementary cycles of directed graph
+//
+// Cycles are searched in separate goroutine. Channel is closed when all
+// cycles are found or ctx is done. See ElementaryCyclesSeq for details.
+func AllElementaryCycles(ctx context.Context, gr DirectedGraphReader) <-chan Vertexes {
+	extractor := &contextExtractor{ctx: ctx, OutNeighboursExtractor: NewDgraphOutNeighboursExtractor(gr)}
+	return cyclesToChan(ctx, elementaryCyclesSeq(extractor, CollectVertexes(gr), nil))
+}
+
+// Get all elementary cycles of mixed graph
+//
+// See AllElementaryCycles and ElementaryCyclesSeq_mixed for details.
+func AllElementaryCycles_mixed(ctx context.Context, gr MixedGraphReader) <-chan Vertexes {
+	extractor := &contextExtractor{ctx: ctx, OutNeighboursExtractor: NewMgraphOutNeighboursExtractor(gr)}
+	return cyclesToChan(ctx, elementaryCyclesSeq(extractor, CollectVertexes(gr), mixedCyclesFilter(gr)))
+}
+
+func cyclesToChan(ctx context.Context, seq iter.Seq[Vertexes]) <-chan Vertexes {
+	ch := make(chan Vertexes)
+	go func() {
+		defer close(ch)
+		for cycle := range seq {
+			select {
+				case ch <- cycle:
+				case <-ctx.Done():
+					return
+			}
+		}
+	}()
+	return ch
+}
+
+// Extractor without any neighbours after ctx is done, so any search stops quickly.
+type contextExtractor struct {
+	OutNeighboursExtractor
+	ctx context.Context
+}
+
+func (e *contextExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	if e.ctx.Err()!=nil {
+		return &nodesIterableLambdaHelper{seq: func(yield func(VertexId) bool) {}}
+	}
+	return e.OutNeighboursExtractor.GetOutNeighbours(node)
+}
+
+// Johnson algorithm
+//
+// For each vertex s (in ascending order) cycles are searched in strongly
+// connected component of s in subgraph of vertexes not less than s.
+// accept is an optional cycles filter.
+func elementaryCyclesSeq(gr OutNeighboursExtractor, vertexes Vertexes, accept func(cycle Vertexes) bool) iter.Seq[Vertexes] {
+	return func(yield func(Vertexes) bool) {
+		order := make(Vertexes, len(vertexes))
+		copy(order, vertexes)
+		sort.Slice(order, func(i, j int) bool { return order[i]<order[j] })
+		
+		removed := make(map[VertexId]bool)
+		for _, start := range order {
+			subgraph := &vertexesFilterExtractor{OutNeighboursExtractor: gr, removed: removed}
+			// component of start is the last one, because start is the root of search
+			components := tarjanSCC(subgraph, Vertexes{start})
+			inComponent := make(map[VertexId]bool)
+			for _, vertex := range components[len(components)-1] {
+				inComponent[vertex] = true
+			}
+			
+			johnson := &johnsonCircuit{
+				start: start,
+				neighbours: make(map[VertexId]Vertexes),
+				blocked: make(map[VertexId]bool),
+				blockedBy: make(map[VertexId]map[VertexId]bool),
+				accept: accept,
+				yield: yield,
+			}
+			for vertex, _ := range inComponent {
+				neighbours := make(Vertexes, 0)
+				// mixed multigraph lists vertex twice, if it's connected by arc and edge
+				listed := make(map[VertexId]bool)
+				for next := range VertexesSeqOf(subgraph.GetOutNeighbours(vertex)) {
+					if inComponent[next] && !listed[next] {
+						listed[next] = true
+						neighbours = append(neighbours, next)
+					}
+				}
+				johnson.neighbours[vertex] = neighbours
+			}
+			if _, stopped := johnson.circuit(start); stopped {
+				return
+			}
+			removed[start] = true
+		}
+	}
+}
+
+// Johnson algorithm state for single start vertex.
+type johnsonCircuit struct {
+	start VertexId
+	neighbours map[VertexId]Vertexes // connections inside component
+	stack Vertexes
+	blocked map[VertexId]bool
+	blockedBy map[VertexId]map[VertexId]bool
+	accept func(cycle Vertexes) bool
+	yield func(Vertexes) bool
+}
+
+// Search cycles through current path and node
+//
+// Returns if any cycle was found and if search was stopped by consumer.
+func (j *johnsonCircuit) circuit(node VertexId) (found bool, stopped bool) {
+	j.stack = append(j.stack, node)
+	j.blocked[node] = true
+	defer func() {
+		j.stack = j.stack[:len(j.stack)-1]
+	}()
+	
+	for _, next := range j.neighbours[node] {
+		if next==j.start {
+			found = true
+			cycle := make(Vertexes, len(j.stack))
+			copy(cycle, j.stack)
+			if (j.accept==nil || j.accept(cycle)) && !j.yield(cycle) {
+				return found, true
+			}
+		} else if !j.blocked[next] {
+			nextFound, nextStopped := j.circuit(next)
+			if nextStopped {
+				return found, true
+			}
+			found = found || nextFound
+		}
+	}
+	
+	if found {
+		j.unblock(node)
+	} else {
+		for _, next := range j.neighbours[node] {
+			if j.blockedBy[next]==nil {
+				j.blockedBy[next] = make(map[VertexId]bool)
+			}
+			j.blockedBy[next][node] = true
+		}
+	}
+	return found, false
+}
+
+func (j *johnsonCircuit) unblock(node VertexId) {
+	queue := Vertexes{node}
+	for len(queue)>0 {
+		cur := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if !j.blocked[cur] {
+			continue
+		}
+		j.blocked[cur] = false
+		for other, _ := range j.blockedBy[cur] {
+			queue = append(queue, other)
+		}
+		delete(j.blockedBy, cur)
+	}
+}
@@ -0,0 +1,330 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Check that cycle is elementary and all it's connections exist
+func expectCycle(t *testing.T, cycle Vertexes, connected func(tail, head VertexId) bool) {
+	t.Helper()
+	expectTrue(t, len(cycle)>0, "cycle is found")
+	visited := make(map[VertexId]bool)
+	for i, tail := range cycle {
+		expectFalse(t, visited[tail], "cycle is elementary")
+		visited[tail] = true
+		expectTrue(t, connected(tail, cycle[(i+1)%len(cycle)]), "cycle connection exists")
+	}
+}
+
+// Cycle, rotated to start from minimal vertex
+func cycleKey(cycle Vertexes, undirected bool) string {
+	minPos := 0
+	for i, vertex := range cycle {
+		if vertex<cycle[minPos] {
+			minPos = i
+		}
+	}
+	rotated := append(append(Vertexes{}, cycle[minPos:]...), cycle[:minPos]...)
+	if undirected && len(rotated)>2 && rotated[1]>rotated[len(rotated)-1] {
+		reverseVertexes(rotated[1:])
+	}
+	return fmt.Sprint(rotated)
+}
+
+// All elementary cycles by brute force
+func bruteForceCycles(gr OutNeighboursExtractor, vertexes Vertexes, accept func(Vertexes) bool, undirected func(Vertexes) bool) []string {
+	keys := make(map[string]bool)
+	var search func(path Vertexes, onPath map[VertexId]bool)
+	search = func(path Vertexes, onPath map[VertexId]bool) {
+		last := path[len(path)-1]
+		for next := range VertexesSeqOf(gr.GetOutNeighbours(last)) {
+			if next==path[0] {
+				cycle := append(Vertexes{}, path...)
+				if accept(cycle) {
+					keys[cycleKey(cycle, undirected(cycle))] = true
+				}
+			} else if next>path[0] && !onPath[next] {
+				onPath[next] = true
+				search(append(path, next), onPath)
+				delete(onPath, next)
+			}
+		}
+	}
+	for _, vertex := range vertexes {
+		search(Vertexes{vertex}, map[VertexId]bool{vertex: true})
+	}
+	res := make([]string, 0, len(keys))
+	for key, _ := range keys {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func collectCycleKeys(t *testing.T, cycles []Vertexes, undirected func(Vertexes) bool) []string {
+	t.Helper()
+	keys := make([]string, 0, len(cycles))
+	known := make(map[string]bool)
+	for _, cycle := range cycles {
+		key := cycleKey(cycle, undirected(cycle))
+		expectFalse(t, known[key], "cycle is unique")
+		known[key] = true
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestFindCycle(t *testing.T) {
+	t.Run("directed", func(t *testing.T) {
+		gr := generateDirectedGraph1()
+		expectTrue(t, FindCycle(gr)==nil, "acyclic graph has cycle")
+		gr.AddArc(5, 2)
+		cycle := FindCycle(gr)
+		expectCycle(t, cycle, gr.CheckArc)
+		expectTrue(t, containsVertex(cycle, 5) && containsVertex(cycle, 2), "cycle goes through new arc")
+
+		gr = generateDirectedGraph1()
+		gr.AddArc(6, 6)
+		expectPath(t, FindCycle(gr), 6)
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		gr := NewMixedMap()
+		ReadMgraphLine(gr, "1-2-3>4")
+		gr.AddArc(1, 5)
+		expectTrue(t, FindCycle_mixed(gr)==nil, "tree has cycle")
+		gr.AddEdge(3, 1)
+		cycle := FindCycle_mixed(gr)
+		expectVertexesExactly(t, cycle, 1, 2, 3)
+		gr.RemoveEdge(3, 1)
+		gr.AddArc(4, 2)
+		cycle = FindCycle_mixed(gr)
+		expectVertexesExactly(t, cycle, 2, 3, 4)
+		expectCycle(t, cycle, func(tail, head VertexId) bool {
+			connType := gr.CheckEdgeType(tail, head)
+			return connType==CT_DIRECTED || connType==CT_UNDIRECTED
+		})
+
+		// depth-first search from 1 enters cycle by both edges and never
+		// goes back by arc 3->2
+		gr = NewMixedMap()
+		ReadMgraphLine(gr, "2-1-3>2")
+		cycle = FindCycle_mixed(gr)
+		expectVertexesExactly(t, cycle, 1, 2, 3)
+		expectCycle(t, cycle, func(tail, head VertexId) bool {
+			connType := gr.CheckEdgeType(tail, head)
+			return connType==CT_DIRECTED || connType==CT_UNDIRECTED
+		})
+
+		multi := NewMixedMultiMap()
+		multi.AddEdge(1, 2)
+		multi.AddArc(2, 3)
+		expectTrue(t, FindCycle_mixed(multi)==nil, "tree has cycle")
+		multi.AddEdge(1, 2)
+		expectVertexesExactly(t, FindCycle_mixed(multi), 1, 2)
+	})
+
+	t.Run("random graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		size := 30
+		for i:=0; i<50; i++ {
+			gr := NewDirectedMap()
+			for node:=0; node<size; node++ {
+				gr.AddNode(VertexId(node))
+			}
+			for j:=0; j<size; j++ {
+				tail, head := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+				if tail!=head && !gr.CheckArc(tail, head) {
+					gr.AddArc(tail, head)
+				}
+			}
+			_, hasCycles := TopologicalSort(gr)
+			cycle := FindCycle(gr)
+			expectEquals(t, cycle!=nil, hasCycles)
+			if hasCycles {
+				expectCycle(t, cycle, gr.CheckArc)
+			}
+		}
+	})
+}
+
+func TestElementaryCycles(t *testing.T) {
+	t.Run("directed", func(t *testing.T) {
+		gr := NewDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>1")
+		ReadDgraphLine(gr, "2>1")
+		ReadDgraphLine(gr, "3>3")
+		ReadDgraphLine(gr, "3>4")
+		cycles := make([]Vertexes, 0)
+		for cycle := range ElementaryCyclesSeq(gr) {
+			expectCycle(t, cycle, gr.CheckArc)
+			cycles = append(cycles, cycle)
+		}
+		notUndirected := func(Vertexes) bool { return false }
+		expectEquals(t, fmt.Sprint(collectCycleKeys(t, cycles, notUndirected)), "[[1 2 3] [1 2] [3]]")
+	})
+
+	t.Run("random directed graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		size := 10
+		notUndirected := func(Vertexes) bool { return false }
+		for i:=0; i<30; i++ {
+			gr := NewDirectedMap()
+			for node:=0; node<size; node++ {
+				gr.AddNode(VertexId(node))
+			}
+			for j:=0; j<size*2; j++ {
+				tail, head := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+				if !gr.CheckArc(tail, head) {
+					gr.AddArc(tail, head)
+				}
+			}
+			cycles := make([]Vertexes, 0)
+			for cycle := range ElementaryCyclesSeq(gr) {
+				expectCycle(t, cycle, gr.CheckArc)
+				cycles = append(cycles, cycle)
+			}
+			expected := bruteForceCycles(NewDgraphOutNeighboursExtractor(gr), CollectVertexes(gr), func(Vertexes) bool { return true }, notUndirected)
+			expectEquals(t, fmt.Sprint(collectCycleKeys(t, cycles, notUndirected)), fmt.Sprint(expected))
+		}
+	})
+
+	t.Run("random mixed graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(2))
+		size := 8
+		for i:=0; i<30; i++ {
+			gr := NewMixedMap()
+			for node:=0; node<size; node++ {
+				gr.AddNode(VertexId(node))
+			}
+			for j:=0; j<size+i/3; j++ {
+				tail, head := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+				if tail==head || gr.CheckEdgeType(tail, head)!=CT_NONE {
+					continue
+				}
+				if rnd.Intn(2)==0 {
+					gr.AddArc(tail, head)
+				} else {
+					gr.AddEdge(tail, head)
+				}
+			}
+			isEdge := func(tail, head VertexId) bool {
+				return gr.CheckEdgeType(tail, head)==CT_UNDIRECTED
+			}
+			edgesOnly := func(cycle Vertexes) bool {
+				for k, tail := range cycle {
+					if !isEdge(tail, cycle[(k+1)%len(cycle)]) {
+						return false
+					}
+				}
+				return true
+			}
+			accept := func(cycle Vertexes) bool {
+				return len(cycle)!=2 || !isEdge(cycle[0], cycle[1])
+			}
+			connected := func(tail, head VertexId) bool {
+				connType := gr.CheckEdgeType(tail, head)
+				return connType==CT_DIRECTED || connType==CT_UNDIRECTED
+			}
+
+			cycles := make([]Vertexes, 0)
+			for cycle := range ElementaryCyclesSeq_mixed(gr) {
+				expectCycle(t, cycle, connected)
+				cycles = append(cycles, cycle)
+			}
+			expected := bruteForceCycles(NewMgraphOutNeighboursExtractor(gr), CollectVertexes(gr), accept, edgesOnly)
+			expectEquals(t, fmt.Sprint(collectCycleKeys(t, cycles, edgesOnly)), fmt.Sprint(expected))
+
+			hasCycle := FindCycle_mixed(gr)!=nil
+			expectEquals(t, hasCycle, len(expected)>0)
+		}
+	})
+
+	t.Run("mixed multigraph", func(t *testing.T) {
+		notUndirected := func(Vertexes) bool { return false }
+		collectAll := func(gr MixedGraphReader) (seqKeys, chanKeys string) {
+			cycles := make([]Vertexes, 0)
+			for cycle := range ElementaryCyclesSeq_mixed(gr) {
+				cycles = append(cycles, cycle)
+			}
+			seqKeys = fmt.Sprint(collectCycleKeys(t, cycles, notUndirected))
+			cycles = make([]Vertexes, 0)
+			for cycle := range AllElementaryCycles_mixed(context.Background(), gr) {
+				cycles = append(cycles, cycle)
+			}
+			chanKeys = fmt.Sprint(collectCycleKeys(t, cycles, notUndirected))
+			return
+		}
+
+		gr := NewMixedMultiMap()
+		gr.AddEdge(1, 2)
+		gr.AddArc(2, 3)
+		seqKeys, chanKeys := collectAll(gr)
+		expectEquals(t, seqKeys, "[]")
+		expectEquals(t, chanKeys, "[]")
+		gr.AddEdge(1, 2)
+		expectPath(t, FindCycle_mixed(gr), 1, 2)
+		seqKeys, chanKeys = collectAll(gr)
+		expectEquals(t, seqKeys, "[[1 2]]")
+		expectEquals(t, chanKeys, "[[1 2]]")
+
+		gr = NewMixedMultiMap()
+		gr.AddArc(1, 2)
+		gr.AddEdge(1, 2)
+		expectPath(t, FindCycle_mixed(gr), 2, 1)
+		seqKeys, chanKeys = collectAll(gr)
+		expectEquals(t, seqKeys, "[[1 2]]")
+		expectEquals(t, chanKeys, "[[1 2]]")
+	})
+
+	t.Run("early stop and cancel", func(t *testing.T) {
+		// complete graph has a lot of cycles
+		gr := NewDirectedMap()
+		size := 12
+		for i:=0; i<size; i++ {
+			for j:=0; j<size; j++ {
+				if i!=j {
+					gr.AddArc(VertexId(i), VertexId(j))
+				}
+			}
+		}
+		cnt := 0
+		for _ = range ElementaryCyclesSeq(gr) {
+			cnt++
+			if cnt==10 {
+				break
+			}
+		}
+		expectEquals(t, cnt, 10)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := AllElementaryCycles(ctx, gr)
+		<-ch
+		cancel()
+		done := make(chan bool)
+		go func() {
+			for _ = range ch {
+			}
+			done <- true
+		}()
+		select {
+			case <-done:
+			case <-time.After(5*time.Second):
+				t.Fatal("channel isn't closed after cancel")
+		}
+
+		mgr := NewMixedMap()
+		ReadMgraphLine(mgr, "1-2-3-1")
+		cnt = 0
+		for _ = range AllElementaryCycles_mixed(context.Background(), mgr) {
+			cnt++
+		}
+		expectEquals(t, cnt, 1)
+	})
+}
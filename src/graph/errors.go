@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors, returned by error-returning API (Try* functions and methods).
+//
+// Check them with errors.Is. Panicking API wraps the same errors into erx
+// errors, so errors.Is works with recovered values too.
+var (
+	ErrNodeNotFound = errors.New("node doesn't exist")
+	ErrNodeExists = errors.New("node already exists")
+	ErrConnectionNotFound = errors.New("connection doesn't exist")
+	ErrDuplicateConnection = errors.New("duplicate connection")
+	ErrLoop = errors.New("loops are not allowed")
+	ErrCapacityExceeded = errors.New("not enough space in graph")
+	ErrNegativeWeight = errors.New("negative weight detected")
+	ErrPathMarkNotFound = errors.New("path mark not found")
+	ErrPriorityIncrease = errors.New("priority can't be increased")
+	ErrInconsistentHeuristic = errors.New("inconsistent heuristic")
+	ErrSyntax = errors.New("syntax error") // error in text graph representation
+	ErrCycle = errors.New("cycle detected")
+)
+
+// Error in operation with single vertex.
+type VertexError struct {
+	Op string // operation name, for example "add node"
+	Node VertexId
+	Err error
+}
+
+func (e *VertexError) Error() string {
+	return fmt.Sprintf("%v %v: %v", e.Op, e.Node, e.Err)
+}
+
+func (e *VertexError) Unwrap() error {
+	return e.Err
+}
+
+// Error in operation with connection between two vertexes.
+type ConnectionError struct {
+	Op string // operation name, for example "add arc"
+	Tail VertexId
+	Head VertexId
+	Err error
+}
+
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("%v %v, %v: %v", e.Op, e.Tail, e.Head, e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// Error in operation, which would create a cycle in acyclic graph.
+//
+// Cycle[i]->Cycle[i+1] are graph arcs, and so is last->first. First arc
+// (Tail->Head) is the one, which caused the cycle.
+type CycleError struct {
+	Op string // operation name, for example "add arc"
+	Tail VertexId
+	Head VertexId
+	Cycle Vertexes
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("%v %v, %v: %v: %v", e.Op, e.Tail, e.Head, ErrCycle, e.Cycle)
+}
+
+func (e *CycleError) Unwrap() error {
+	return ErrCycle
+}
+
+// Build cycle error for found cycle.
+//
+// First arc of cycle becomes Tail->Head. Cycle may be empty, if it wasn't
+// found (for example with inconsistent graph implementation).
+func newCycleError(op string, cycle Vertexes) *CycleError {
+	err := &CycleError{Op: op, Cycle: cycle}
+	if len(cycle)>0 {
+		err.Tail, err.Head = cycle[0], cycle[1%len(cycle)]
+	}
+	return err
+}
+
+// Error in operation with multigraph connection, given by id.
+type ConnectionIdError struct {
+	Op string // operation name, for example "remove connection"
+	Id ConnectionId
+	Err error
+}
+
+func (e *ConnectionIdError) Error() string {
+	return fmt.Sprintf("%v #%v: %v", e.Op, e.Id, e.Err)
+}
+
+func (e *ConnectionIdError) Unwrap() error {
+	return e.Err
+}
+
+// Error while parsing text graph representation.
+//
+// Line and Column start from 1. Line is 0 if single line was parsed
+// (for example with TryReadDgraphLine).
+type ParseError struct {
+	Line int
+	Column int
+	Chunk string // part of line, which caused an error
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	pos := fmt.Sprintf("column %v", e.Column)
+	if e.Line > 0 {
+		pos = fmt.Sprintf("line %v, %v", e.Line, pos)
+	}
+	if e.Chunk!="" {
+		return fmt.Sprintf("%v: %q: %v", pos, e.Chunk, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", pos, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Convert value, recovered from panic, to error.
+func recoveredToError(e interface{}) error {
+	if err, ok := e.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", e)
+}
+
+// Call function and convert panic (if any) to error.
+func catchPanic(f func()) (err error) {
+	defer func() {
+		if e := recover(); e!=nil {
+			err = recoveredToError(e)
+		}
+	}()
+	f()
+	return
+}
@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func expectErrorIs(t *testing.T, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("expected error %q, got %v", target, err)
+	}
+}
+
+func TestTryGraphWriters(t *testing.T) {
+	t.Run("DirectedMap", func(t *testing.T) {
+		gr := NewDirectedMap()
+		expectErrorIs(t, gr.TryAddArc(1, 2), nil)
+		expectErrorIs(t, gr.TryAddArc(1, 2), ErrDuplicateConnection)
+		expectErrorIs(t, gr.TryAddNode(1), ErrNodeExists)
+		expectErrorIs(t, gr.TryRemoveArc(2, 1), ErrConnectionNotFound)
+		expectErrorIs(t, gr.TryRemoveNode(5), ErrNodeNotFound)
+
+		var connErr *ConnectionError
+		if !errors.As(gr.TryAddArc(1, 2), &connErr) {
+			t.Fatal("expected *ConnectionError")
+		}
+		expectEquals(t, connErr.Tail, VertexId(1))
+		expectEquals(t, connErr.Head, VertexId(2))
+
+		var vertexErr *VertexError
+		if !errors.As(gr.TryRemoveNode(5), &vertexErr) {
+			t.Fatal("expected *VertexError")
+		}
+		expectEquals(t, vertexErr.Node, VertexId(5))
+	})
+
+	t.Run("UndirectedMap", func(t *testing.T) {
+		gr := NewUndirectedMap()
+		expectErrorIs(t, gr.TryAddEdge(1, 2), nil)
+		expectErrorIs(t, gr.TryAddEdge(2, 1), ErrDuplicateConnection)
+		expectErrorIs(t, gr.TryRemoveEdge(1, 3), ErrNodeNotFound)
+		expectErrorIs(t, gr.TryRemoveNode(3), ErrNodeNotFound)
+		gr.AddNode(3)
+		expectErrorIs(t, gr.TryRemoveEdge(1, 3), ErrConnectionNotFound)
+	})
+
+	t.Run("MixedMap", func(t *testing.T) {
+		gr := NewMixedMap()
+		expectErrorIs(t, gr.TryAddArc(1, 2), nil)
+		expectErrorIs(t, gr.TryAddEdge(1, 2), ErrDuplicateConnection)
+		expectErrorIs(t, gr.TryAddArc(3, 3), ErrLoop)
+		expectErrorIs(t, gr.TryRemoveEdge(1, 2), ErrConnectionNotFound)
+	})
+
+	t.Run("UndirectedMatrix", func(t *testing.T) {
+		gr := NewUndirectedMatrix(2)
+		expectErrorIs(t, gr.TryAddEdge(1, 2), nil)
+		expectErrorIs(t, gr.TryAddNode(1), ErrNodeExists)
+		expectErrorIs(t, gr.TryRemoveNode(3), ErrNodeNotFound)
+	})
+
+	t.Run("MixedMatrix", func(t *testing.T) {
+		gr := NewMixedMatrix(2)
+		expectErrorIs(t, gr.TryAddArc(1, 2), nil)
+		expectErrorIs(t, gr.TryAddArc(1, 2), ErrDuplicateConnection)
+		expectErrorIs(t, gr.TryAddEdge(2, 2), ErrLoop)
+		expectErrorIs(t, gr.TryRemoveEdge(1, 2), ErrConnectionNotFound)
+	})
+}
+
+func TestPanickingApiWrapsErrors(t *testing.T) {
+	gr := NewDirectedMap()
+	gr.AddArc(1, 2)
+	err := catchPanic(func() { gr.AddArc(1, 2) })
+	expectErrorIs(t, err, ErrDuplicateConnection)
+}
+
+func TestTryReadGraphFile(t *testing.T) {
+	t.Run("Valid input", func(t *testing.T) {
+		gr := NewDirectedMap()
+		err := TryReadDgraphFile(strings.NewReader("1>2>3\n4\n"), gr)
+		expectErrorIs(t, err, nil)
+		expectTrue(t, gr.CheckArc(2, 3), "arc 2->3")
+		expectTrue(t, gr.CheckNode(4), "node 4")
+	})
+
+	t.Run("Syntax error", func(t *testing.T) {
+		gr := NewDirectedMap()
+		err := TryReadDgraphFile(strings.NewReader("1>2\n2>x\n"), gr)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		expectEquals(t, parseErr.Line, 2)
+		expectEquals(t, parseErr.Column, 3)
+		expectEquals(t, parseErr.Chunk, "x")
+	})
+
+	t.Run("Dangling delimiter", func(t *testing.T) {
+		err := TryReadUgraphLine(NewUndirectedMap(), "1-2-")
+		expectErrorIs(t, err, ErrSyntax)
+	})
+
+	t.Run("Duplicate connection", func(t *testing.T) {
+		gr := NewMixedMap()
+		err := TryReadMgraphFile(strings.NewReader("1>2\n3-1>2\n"), gr)
+		expectErrorIs(t, err, ErrDuplicateConnection)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		expectEquals(t, parseErr.Line, 2)
+		expectEquals(t, parseErr.Column, 5)
+	})
+}
+
+func TestTrySearch(t *testing.T) {
+	t.Run("Dijkstra with negative weight", func(t *testing.T) {
+		gr := NewDirectedMap()
+		gr.AddArc(1, 2)
+		gr.AddArc(2, 3)
+		weight := func(tail, head VertexId) float64 {
+			return -1.0
+		}
+		_, _, err := TryCheckPathDijkstra(NewDgraphOutNeighboursExtractor(gr), 1, 3, nil, weight)
+		expectErrorIs(t, err, ErrNegativeWeight)
+	})
+
+	t.Run("Path from inconsistent marks", func(t *testing.T) {
+		marks := PathMarks{
+			3: &VertexPathMark{Weight: 2.0, PrevVertex: 2},
+		}
+		_, err := TryPathFromMarks(marks, 3)
+		expectErrorIs(t, err, ErrPathMarkNotFound)
+	})
+}
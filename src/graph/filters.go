@@ -1,7 +1,9 @@
 package graph
 
 import (
-	"github.com/StepLg/go-erx/src/erx"
+	"iter"
+
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 // Arcs filter in DirectedGraphReader
@@ -36,39 +38,32 @@ func NewDirectedGraphArcFilter(g DirectedGraphArcsReader, tail, head VertexId) *
 
 // Getting node accessors
 func (filter *DirectedGraphArcsFilter) GetAccessors(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		go func() {
-			AccessorsLoop: 
-			for accessor := range filter.DirectedGraphArcsReader.GetAccessors(node).VertexesIter() {
-				if !filter.IsArcFiltering(node, accessor) {
-					ch <- accessor
+	iterator := func(yield func(VertexId) bool) {
+		for accessor := range VertexesSeqOf(filter.DirectedGraphArcsReader.GetAccessors(node)) {
+			if !filter.IsArcFiltering(node, accessor) {
+				if !yield(accessor) {
+					return
 				}
 			}
-			close(ch)
-		}()
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Getting node predecessors
 func (filter *DirectedGraphArcsFilter) GetPredecessors(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		go func() {
-			for predecessor := range filter.DirectedGraphArcsReader.GetPredecessors(node).VertexesIter() {
-				if !filter.IsArcFiltering(predecessor, node) {
-					ch <- predecessor
+	iterator := func(yield func(VertexId) bool) {
+		for predecessor := range VertexesSeqOf(filter.DirectedGraphArcsReader.GetPredecessors(node)) {
+			if !filter.IsArcFiltering(predecessor, node) {
+				if !yield(predecessor) {
+					return
 				}
 			}
-			close(ch)
-		}()
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Checking arrow existance between node1 and node2
@@ -88,16 +83,19 @@ func (filter *DirectedGraphArcsFilter) CheckArc(node1, node2 VertexId) bool {
 }
 
 func (filter *DirectedGraphArcsFilter) ArcsIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
-		for conn := range filter.DirectedGraphArcsReader.ArcsIter() {
+	return seqToChan(filter.ArcsSeq())
+}
+
+func (filter *DirectedGraphArcsFilter) ArcsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for conn := range ArcsSeqOf(filter.DirectedGraphArcsReader) {
 			if !filter.IsArcFiltering(conn.Tail, conn.Head) {
-				ch <- conn
+				if !yield(conn) {
+					return
+				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 func (filter *DirectedGraphArcsFilter) IsArcFiltering(tail, head VertexId) bool {
@@ -157,20 +155,17 @@ func NewUndirectedGraphEdgeFilter(g UndirectedGraphEdgesReader, tail, head Verte
 
 // Getting node neighbours
 func (filter *UndirectedGraphEdgesFilter) GetNeighbours(node VertexId) VertexesIterable {
-	iterator := func() <-chan VertexId {
-		ch := make(chan VertexId)
-		go func() {
-			for neighbour := range filter.UndirectedGraphEdgesReader.GetNeighbours(node).VertexesIter() {
-				if !filter.IsEdgeFiltering(node, neighbour) {
-					ch <- neighbour
+	iterator := func(yield func(VertexId) bool) {
+		for neighbour := range VertexesSeqOf(filter.UndirectedGraphEdgesReader.GetNeighbours(node)) {
+			if !filter.IsEdgeFiltering(node, neighbour) {
+				if !yield(neighbour) {
+					return
 				}
 			}
-			close(ch)
-		}()
-		return ch
+		}
 	}
 	
-	return VertexesIterable(&nodesIterableLambdaHelper{iterFunc:iterator})
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
 }
 
 // Checking edge existance between node1 and node2
@@ -185,16 +180,19 @@ func (filter *UndirectedGraphEdgesFilter) CheckEdge(node1, node2 VertexId) bool
 }
 
 func (filter *UndirectedGraphEdgesFilter) EdgesIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
-		for conn := range filter.UndirectedGraphEdgesReader.EdgesIter() {
+	return seqToChan(filter.EdgesSeq())
+}
+
+func (filter *UndirectedGraphEdgesFilter) EdgesSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for conn := range EdgesSeqOf(filter.UndirectedGraphEdgesReader) {
 			if !filter.IsEdgeFiltering(conn.Tail, conn.Head) {
-				ch <- conn
+				if !yield(conn) {
+					return
+				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 func (filter *UndirectedGraphEdgesFilter) IsEdgeFiltering(tail, head VertexId) bool {
@@ -230,20 +228,26 @@ func NewMixedGraphArcsFilter(g MixedGraphConnectionsReader, arcs []Connection, e
 }
 
 func (filter *MixedGraphConnectionsFilter) ConnectionsIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
-		for conn := range filter.TypedConnectionsIter() {
-			ch <- conn.Connection
+	return seqToChan(filter.ConnectionsSeq())
+}
+
+func (filter *MixedGraphConnectionsFilter) ConnectionsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for conn := range filter.TypedConnectionsSeq() {
+			if !yield(conn.Connection) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 func (filter *MixedGraphConnectionsFilter) TypedConnectionsIter() <-chan TypedConnection {
-	ch := make(chan TypedConnection)
-	go func() {
-		for conn := range filter.gr.TypedConnectionsIter() {
+	return seqToChan(filter.TypedConnectionsSeq())
+}
+
+func (filter *MixedGraphConnectionsFilter) TypedConnectionsSeq() iter.Seq[TypedConnection] {
+	return func(yield func(TypedConnection) bool) {
+		for conn := range TypedConnectionsSeqOf(filter.gr) {
 			needToFilter := false
 			switch conn.Type {
 				case CT_UNDIRECTED:
@@ -255,12 +259,12 @@ func (filter *MixedGraphConnectionsFilter) TypedConnectionsIter() <-chan TypedCo
 					panic(err)
 			}
 			if !needToFilter {
-				ch <- conn
+				if !yield(conn) {
+					return
+				}
 			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 func (filter *MixedGraphConnectionsFilter) CheckEdgeType(tail VertexId, head VertexId) MixedConnectionType {
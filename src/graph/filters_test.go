@@ -2,11 +2,9 @@ package graph
 
 import (
 	"testing"
-	"github.com/orfjackal/gospec/src/gospec"
-	. "github.com/orfjackal/gospec/src/gospec"
 )
 
-func DirectedGraphArcsFilterSpec(c gospec.Context) {
+func TestDirectedGraphArcsFilter(t *testing.T) {
 	gr := NewDirectedMap()
 	gr.AddArc(1, 2)
 	gr.AddArc(2, 3)
@@ -16,30 +14,30 @@ func DirectedGraphArcsFilterSpec(c gospec.Context) {
 	gr.AddArc(1, 6)
 	gr.AddArc(2, 6)
 
-	c.Specify("Single filtered arc", func() {
+	t.Run("Single filtered arc", func(t *testing.T) {
 		ftail := VertexId(2)
 		fhead := VertexId(3)
 		f := NewDirectedGraphArcFilter(gr, ftail, fhead)
-		
-		c.Specify("shouldn't be checked", func() {
-			c.Expect(f.CheckArc(ftail, fhead), IsFalse)
+
+		t.Run("shouldn't be checked", func(t *testing.T) {
+			expectFalse(t, f.CheckArc(ftail, fhead), "filtered arc checked")
 		})
-		
-		c.Specify("shouldn't appear in accessors", func() {
-			c.Expect(CollectVertexes(f.GetAccessors(VertexId(ftail))), Not(Contains), fhead)
+
+		t.Run("shouldn't appear in accessors", func(t *testing.T) {
+			expectNotContains(t, CollectVertexes(f.GetAccessors(ftail)), fhead)
 		})
-		c.Specify("shouldn't appear in predecessors", func() {
-			c.Expect(CollectVertexes(f.GetPredecessors(VertexId(fhead))), Not(Contains), ftail)
+		t.Run("shouldn't appear in predecessors", func(t *testing.T) {
+			expectNotContains(t, CollectVertexes(f.GetPredecessors(fhead)), ftail)
 		})
-		c.Specify("shouldn't appear in iterator", func() {
+		t.Run("shouldn't appear in iterator", func(t *testing.T) {
 			for conn := range f.ArcsIter() {
-				c.Expect(conn.Tail==ftail && conn.Head==fhead, IsFalse)
+				expectFalse(t, conn.Tail==ftail && conn.Head==fhead, "filtered arc in iterator")
 			}
 		})
 	})
 }
 
-func UndirectedGraphEdgesFilterSpec(c gospec.Context) {
+func TestUndirectedGraphEdgesFilter(t *testing.T) {
 	gr := NewUndirectedMap()
 	gr.AddEdge(1, 2)
 	gr.AddEdge(2, 3)
@@ -49,35 +47,35 @@ func UndirectedGraphEdgesFilterSpec(c gospec.Context) {
 	gr.AddEdge(1, 6)
 	gr.AddEdge(2, 6)
 
-	c.Specify("Single filtered arc", func() {
+	t.Run("Single filtered arc", func(t *testing.T) {
 		ftail := VertexId(3)
 		fhead := VertexId(2)
 		f := NewUndirectedGraphEdgeFilter(gr, ftail, fhead)
-		
-		c.Specify("should be filtered", func() {
-			c.Expect(f.IsEdgeFiltering(ftail, fhead), IsTrue)
-			c.Expect(f.IsEdgeFiltering(fhead, ftail), IsTrue)
+
+		t.Run("should be filtered", func(t *testing.T) {
+			expectTrue(t, f.IsEdgeFiltering(ftail, fhead), "tail-head filtering")
+			expectTrue(t, f.IsEdgeFiltering(fhead, ftail), "head-tail filtering")
 		})
-		
-		c.Specify("shouldn't be checked", func() {
-			c.Expect(f.CheckEdge(ftail, fhead), IsFalse)
-			c.Expect(f.CheckEdge(fhead, ftail), IsFalse)
+
+		t.Run("shouldn't be checked", func(t *testing.T) {
+			expectFalse(t, f.CheckEdge(ftail, fhead), "tail-head checked")
+			expectFalse(t, f.CheckEdge(fhead, ftail), "head-tail checked")
 		})
-		
-		c.Specify("shouldn't appear in neighbours", func() {
-			c.Expect(CollectVertexes(f.GetNeighbours(VertexId(ftail))), Not(Contains), fhead)
-			c.Expect(CollectVertexes(f.GetNeighbours(VertexId(fhead))), Not(Contains), ftail)
+
+		t.Run("shouldn't appear in neighbours", func(t *testing.T) {
+			expectNotContains(t, CollectVertexes(f.GetNeighbours(ftail)), fhead)
+			expectNotContains(t, CollectVertexes(f.GetNeighbours(fhead)), ftail)
 		})
-		c.Specify("shouldn't appear in iterator", func() {
+		t.Run("shouldn't appear in iterator", func(t *testing.T) {
 			for conn := range f.EdgesIter() {
 				// iter always retur min node id as tail and max node id as head
-				c.Expect(conn.Tail==fhead && conn.Head==ftail, IsFalse)
+				expectFalse(t, conn.Tail==fhead && conn.Head==ftail, "filtered edge in iterator")
 			}
 		})
 	})
 }
 
-func MixedGraphConnectionsFilterSpec(c gospec.Context) {
+func TestMixedGraphConnectionsFilter(t *testing.T) {
 	gr := NewMixedMatrix(10)
 	gr.AddArc(1, 2)
 	gr.AddArc(2, 3)
@@ -87,32 +85,25 @@ func MixedGraphConnectionsFilterSpec(c gospec.Context) {
 	gr.AddArc(1, 6)
 	gr.AddArc(2, 6)
 
-	c.Specify("Single filtered arc", func() {
+	t.Run("Single filtered arc", func(t *testing.T) {
 		ftail := VertexId(2)
 		fhead := VertexId(3)
 		f := NewDirectedGraphArcFilter(gr, ftail, fhead)
-		
-		c.Specify("shouldn't be checked", func() {
-			c.Expect(f.CheckArc(ftail, fhead), IsFalse)
+
+		t.Run("shouldn't be checked", func(t *testing.T) {
+			expectFalse(t, f.CheckArc(ftail, fhead), "filtered arc checked")
 		})
-		
-		c.Specify("shouldn't appear in accessors", func() {
-			c.Expect(CollectVertexes(f.GetAccessors(VertexId(ftail))), Not(Contains), fhead)
+
+		t.Run("shouldn't appear in accessors", func(t *testing.T) {
+			expectNotContains(t, CollectVertexes(f.GetAccessors(ftail)), fhead)
 		})
-		c.Specify("shouldn't appear in predecessors", func() {
-			c.Expect(CollectVertexes(f.GetPredecessors(VertexId(fhead))), Not(Contains), ftail)
+		t.Run("shouldn't appear in predecessors", func(t *testing.T) {
+			expectNotContains(t, CollectVertexes(f.GetPredecessors(fhead)), ftail)
 		})
-		c.Specify("shouldn't appear in iterator", func() {
+		t.Run("shouldn't appear in iterator", func(t *testing.T) {
 			for conn := range f.ArcsIter() {
-				c.Expect(conn.Tail==ftail && conn.Head==fhead, IsFalse)
+				expectFalse(t, conn.Tail==ftail && conn.Head==fhead, "filtered arc in iterator")
 			}
 		})
 	})
 }
-func TestGraphFilters(t *testing.T) {
-	r := gospec.NewRunner()
-	r.AddSpec(DirectedGraphArcsFilterSpec)
-	r.AddSpec(UndirectedGraphEdgesFilterSpec)
-	r.AddSpec(MixedGraphConnectionsFilterSpec)
-	gospec.MainGoTest(r, t)
-}
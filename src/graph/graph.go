@@ -1,5 +1,9 @@
 package graph
 
+import (
+	"iter"
+)
+
 type VertexId uint
 
 type Vertexes []VertexId
@@ -14,26 +18,67 @@ type TypedConnection struct {
 	Type MixedConnectionType
 }
 
+// Identity of connection in multigraph, where several connections could
+// exist between two nodes.
+type ConnectionId uint
+
+// Iterables provide two kinds of iterators.
+//
+// Channel iterators (XxxIter functions) spawn goroutine on each call. If
+// consumer stops reading from channel before it's closed, goroutine blocks
+// forever. Function iterators (XxxSeq functions) don't use goroutines at all
+// and stop as soon as yield function returns false, so it's safe to break
+// range loop over them.
+//
+// Function iterators are declared in separate XxxSeqIterable interfaces, so
+// iterables, implemented outside the package, aren't required to provide
+// them. Use XxxSeqOf adapters to get function iterator of any iterable:
+//
+//	for node := range VertexesSeqOf(gr) { ... }
+//
+// Adapter returns XxxSeq() of iterable if it's implemented and wraps channel
+// iterator otherwise. All package graphs implement function iterators.
+
 type ConnectionsIterable interface {
 	ConnectionsIter() <-chan Connection
 }
 
+type ConnectionsSeqIterable interface {
+	ConnectionsSeq() iter.Seq[Connection]
+}
+
 type EdgesIterable interface {
 	EdgesIter() <-chan Connection
 }
 
+type EdgesSeqIterable interface {
+	EdgesSeq() iter.Seq[Connection]
+}
+
 type ArcsIterable interface {
 	ArcsIter() <-chan Connection
 }
 
+type ArcsSeqIterable interface {
+	ArcsSeq() iter.Seq[Connection]
+}
+
 type TypedConnectionsIterable interface {
 	TypedConnectionsIter() <-chan TypedConnection
 }
 
+type TypedConnectionsSeqIterable interface {
+	TypedConnectionsSeq() iter.Seq[TypedConnection]
+}
+
 type VertexesIterable interface {
 	VertexesIter() <-chan VertexId
 }
 
+type VertexesSeqIterable interface {
+	VertexesSeq() iter.Seq[VertexId]
+}
+
 type VertexesChecker interface {
 	// Check node existance in graph
 	CheckNode(node VertexId) bool
@@ -44,6 +89,12 @@ type GraphVertexesWriter interface {
 	AddNode(node VertexId)
 }
 
+// Error-returning version of GraphVertexesWriter
+type GraphVertexesTryWriter interface {
+	// Adding single node to graph
+	TryAddNode(node VertexId) error
+}
+
 type GraphVertexesReader interface {
 	VertexesChecker
 	// Getting nodes count in graph
@@ -55,16 +106,34 @@ type GraphVertexesRemover interface {
 	RemoveNode(node VertexId)
 }
 
+// Error-returning version of GraphVertexesRemover
+type GraphVertexesTryRemover interface {
+	// Removing node from graph
+	TryRemoveNode(node VertexId) error
+}
+
 type DirectedGraphArcsWriter interface {
 	// Adding directed arc to graph
 	AddArc(from, to VertexId)
 }
 
+// Error-returning version of DirectedGraphArcsWriter
+type DirectedGraphArcsTryWriter interface {
+	// Adding directed arc to graph
+	TryAddArc(from, to VertexId) error
+}
+
 type DirectedGraphArcsRemover interface {
 	// Removding directed arc
 	RemoveArc(from, to VertexId)
 }
 
+// Error-returning version of DirectedGraphArcsRemover
+type DirectedGraphArcsTryRemover interface {
+	// Removding directed arc
+	TryRemoveArc(from, to VertexId) error
+}
+
 type DirectedGraphArcsReader interface {
 	ArcsIterable
 	
@@ -113,6 +182,32 @@ type DirectedGraph interface {
 	DirectedGraphArcsReader
 }
 
+type WeightedDirectedGraphArcsReader interface {
+	// Getting arc weight
+	//
+	// Arc must exist in graph or error will be returned
+	GetArcWeight(tail, head VertexId) float64
+}
+
+type WeightedDirectedGraphArcsWriter interface {
+	// Adding directed arc with weight to graph
+	AddWeightedArc(tail, head VertexId, weight float64)
+	// Changing weight of existing arc
+	SetArcWeight(tail, head VertexId, weight float64)
+}
+
+type WeightedDirectedGraphReader interface {
+	DirectedGraphReader
+	WeightedDirectedGraphArcsReader
+}
+
+// Interface representing directed graph with arcs weights
+type WeightedDirectedGraph interface {
+	DirectedGraph
+	WeightedDirectedGraphArcsWriter
+	WeightedDirectedGraphArcsReader
+}
+
 type UndirectedGraphEdgesReader interface {
 	EdgesIterable
 
@@ -133,11 +228,23 @@ type UndirectedGraphEdgesWriter interface {
 	AddEdge(node1, node2 VertexId)	
 }
 
+// Error-returning version of UndirectedGraphEdgesWriter
+type UndirectedGraphEdgesTryWriter interface {
+	// Adding new edge to graph
+	TryAddEdge(node1, node2 VertexId) error
+}
+
 type UndirectedGraphEdgesRemover interface {
 	// Removing edge, connecting node1 and node2
 	RemoveEdge(node1, node2 VertexId)
 }
 
+// Error-returning version of UndirectedGraphEdgesRemover
+type UndirectedGraphEdgesTryRemover interface {
+	// Removing edge, connecting node1 and node2
+	TryRemoveEdge(node1, node2 VertexId) error
+}
+
 type UndirectedGraphReader interface {
 	GraphVertexesReader
 	UndirectedGraphEdgesReader
@@ -164,6 +271,32 @@ type UndirectedGraph interface {
 	UndirectedGraphEdgesReader
 }
 
+type WeightedUndirectedGraphEdgesReader interface {
+	// Getting edge weight
+	//
+	// Edge must exist in graph or error will be returned
+	GetEdgeWeight(node1, node2 VertexId) float64
+}
+
+type WeightedUndirectedGraphEdgesWriter interface {
+	// Adding edge with weight to graph
+	AddWeightedEdge(node1, node2 VertexId, weight float64)
+	// Changing weight of existing edge
+	SetEdgeWeight(node1, node2 VertexId, weight float64)
+}
+
+type WeightedUndirectedGraphReader interface {
+	UndirectedGraphReader
+	WeightedUndirectedGraphEdgesReader
+}
+
+// Interface representing undirected graph with edges weights
+type WeightedUndirectedGraph interface {
+	UndirectedGraph
+	WeightedUndirectedGraphEdgesWriter
+	WeightedUndirectedGraphEdgesReader
+}
+
 type MixedGraphSpecificReader interface {
 	CheckEdgeType(tail, head VertexId) MixedConnectionType
 	ConnectionsCnt() int
@@ -208,3 +341,47 @@ type MixedGraph interface {
 
 	MixedGraphSpecificReader
 }
+
+// Multigraph connections, each of them has it's own id.
+type MultiGraphConnectionsReader interface {
+	// Getting connection by id. Arcs have CT_DIRECTED type and edges have
+	// CT_UNDIRECTED type.
+	LookupConnection(id ConnectionId) (TypedConnection, bool)
+	// Iterating over all connections with their ids
+	IdentifiedConnectionsSeq() iter.Seq2[ConnectionId, TypedConnection]
+}
+
+type MultiGraphConnectionsRemover interface {
+	// Removing connection by id
+	RemoveConnection(id ConnectionId)
+}
+
+// Error-returning version of MultiGraphConnectionsRemover
+type MultiGraphConnectionsTryRemover interface {
+	// Removing connection by id
+	TryRemoveConnection(id ConnectionId) error
+}
+
+type WeightedMixedGraphSpecificReader interface {
+	// Getting weight of arc from tail to head or edge between them
+	//
+	// Connection must exist in graph or error will be returned
+	GetConnectionWeight(tail, head VertexId) float64
+}
+
+type WeightedMixedGraphReader interface {
+	MixedGraphReader
+	WeightedDirectedGraphArcsReader
+	WeightedUndirectedGraphEdgesReader
+	WeightedMixedGraphSpecificReader
+}
+
+// Interface representing mixed graph with arcs and edges weights
+type WeightedMixedGraph interface {
+	MixedGraph
+	WeightedDirectedGraphArcsWriter
+	WeightedDirectedGraphArcsReader
+	WeightedUndirectedGraphEdgesWriter
+	WeightedUndirectedGraphEdgesReader
+	WeightedMixedGraphSpecificReader
+}
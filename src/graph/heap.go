@@ -0,0 +1,441 @@
+package graph
+
+import (
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Indexed vertexes min-priority queue
+//
+// Each vertex can be stored in queue only once. Vertex with minimal priority
+// goes first. Stored vertexes priorities can be decreased (DecreaseKey) or
+// vertexes can be removed from the middle of the queue, which is necessary
+// for efficient Dijkstra-like algorithms.
+type VertexPriorityQueue interface {
+	// Add new vertex to queue
+	//
+	// Panic if vertex is already in queue.
+	Push(node VertexId, priority float64)
+	// Error-returning version of Push. Returns *VertexError with ErrNodeExists.
+	TryPush(node VertexId, priority float64) error
+	// Get vertex with min priority and remove it from the queue
+	//
+	// Panic if queue is empty.
+	Pop() (VertexId, float64)
+	// Get vertex with min priority without removing it from the queue
+	//
+	// Panic if queue is empty.
+	Peek() (VertexId, float64)
+	// Decrease priority of vertex in queue
+	//
+	// Panic if vertex isn't in queue or new priority is greater than current one.
+	DecreaseKey(node VertexId, priority float64)
+	// Error-returning version of DecreaseKey. Returns *VertexError with
+	// ErrNodeNotFound or ErrPriorityIncrease.
+	TryDecreaseKey(node VertexId, priority float64) error
+	// Remove vertex from queue
+	//
+	// Panic if vertex isn't in queue.
+	Remove(node VertexId)
+	// Error-returning version of Remove. Returns *VertexError with ErrNodeNotFound.
+	TryRemove(node VertexId) error
+	// Check if vertex is in queue
+	Contains(node VertexId) bool
+	// Get current vertex priority
+	Priority(node VertexId) (float64, bool)
+	// Total queue size
+	Len() int
+	// Check if queue is empty
+	Empty() bool
+}
+
+// Add vertex to queue or decrease it's priority
+//
+// Returns true if vertex was added or it's priority was decreased and false
+// if vertex is already in queue with less or equal priority.
+func PushOrDecreaseKey(q VertexPriorityQueue, node VertexId, priority float64) bool {
+	if curPriority, ok := q.Priority(node); ok {
+		if curPriority<=priority {
+			return false
+		}
+		q.DecreaseKey(node, priority)
+		return true
+	}
+	q.Push(node, priority)
+	return true
+}
+
+// Panicking wrappers over Try* queue methods share the same error message format.
+func panicQueueError(msg string, err error, node VertexId, priority float64) {
+	erxErr := erx.NewSequentLevel(msg, err, 2)
+	erxErr.AddV("node", node)
+	erxErr.AddV("priority", priority)
+	panic(erxErr)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// BinaryHeap
+
+type heapItem struct {
+	Node VertexId
+	Priority float64
+}
+
+// Indexed binary heap
+//
+// Push, Pop, DecreaseKey and Remove take O(log n) time, Peek, Contains and
+// Priority take O(1).
+type BinaryHeap struct {
+	items []heapItem
+	index map[VertexId]int
+}
+
+// Create new binary heap
+//
+// capacity is a hint for expected maximum number of vertexes in queue.
+func NewBinaryHeap(capacity int) *BinaryHeap {
+	if capacity<0 {
+		capacity = 0
+	}
+	return &BinaryHeap{
+		items: make([]heapItem, 0, capacity),
+		index: make(map[VertexId]int, capacity),
+	}
+}
+
+func (h *BinaryHeap) Push(node VertexId, priority float64) {
+	if err := h.TryPush(node, priority); err!=nil {
+		panicQueueError("Push vertex to binary heap.", err, node, priority)
+	}
+}
+
+func (h *BinaryHeap) TryPush(node VertexId, priority float64) error {
+	if _, ok := h.index[node]; ok {
+		return &VertexError{Op: "push", Node: node, Err: ErrNodeExists}
+	}
+	h.items = append(h.items, heapItem{Node: node, Priority: priority})
+	h.index[node] = len(h.items)-1
+	h.up(len(h.items)-1)
+	return nil
+}
+
+func (h *BinaryHeap) Pop() (VertexId, float64) {
+	if h.Empty() {
+		panic(erx.NewError("Can't pop from empty queue."))
+	}
+	top := h.items[0]
+	h.removeAt(0)
+	return top.Node, top.Priority
+}
+
+func (h *BinaryHeap) Peek() (VertexId, float64) {
+	if h.Empty() {
+		panic(erx.NewError("Can't peek from empty queue."))
+	}
+	return h.items[0].Node, h.items[0].Priority
+}
+
+func (h *BinaryHeap) DecreaseKey(node VertexId, priority float64) {
+	if err := h.TryDecreaseKey(node, priority); err!=nil {
+		panicQueueError("Decrease vertex priority in binary heap.", err, node, priority)
+	}
+}
+
+func (h *BinaryHeap) TryDecreaseKey(node VertexId, priority float64) error {
+	i, ok := h.index[node]
+	if !ok {
+		return &VertexError{Op: "decrease key", Node: node, Err: ErrNodeNotFound}
+	}
+	if priority > h.items[i].Priority {
+		return &VertexError{Op: "decrease key", Node: node, Err: ErrPriorityIncrease}
+	}
+	h.items[i].Priority = priority
+	h.up(i)
+	return nil
+}
+
+func (h *BinaryHeap) Remove(node VertexId) {
+	if err := h.TryRemove(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove vertex from binary heap.", err, 1)
+		erxErr.AddV("node", node)
+		panic(erxErr)
+	}
+}
+
+func (h *BinaryHeap) TryRemove(node VertexId) error {
+	i, ok := h.index[node]
+	if !ok {
+		return &VertexError{Op: "remove", Node: node, Err: ErrNodeNotFound}
+	}
+	h.removeAt(i)
+	return nil
+}
+
+func (h *BinaryHeap) Contains(node VertexId) bool {
+	_, ok := h.index[node]
+	return ok
+}
+
+func (h *BinaryHeap) Priority(node VertexId) (float64, bool) {
+	if i, ok := h.index[node]; ok {
+		return h.items[i].Priority, true
+	}
+	return 0.0, false
+}
+
+func (h *BinaryHeap) Len() int {
+	return len(h.items)
+}
+
+func (h *BinaryHeap) Empty() bool {
+	return len(h.items)==0
+}
+
+// Remove item with index i and restore heap property
+func (h *BinaryHeap) removeAt(i int) {
+	last := len(h.items)-1
+	delete(h.index, h.items[i].Node)
+	if i!=last {
+		h.items[i] = h.items[last]
+		h.index[h.items[i].Node] = i
+	}
+	h.items = h.items[:last]
+	if i<last {
+		if !h.up(i) {
+			h.down(i)
+		}
+	}
+}
+
+func (h *BinaryHeap) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.index[h.items[i].Node] = i
+	h.index[h.items[j].Node] = j
+}
+
+// Move item up while it's priority is less than parent's one
+//
+// Returns true if item was moved.
+func (h *BinaryHeap) up(i int) bool {
+	moved := false
+	for i>0 {
+		parent := (i-1)/2
+		if h.items[parent].Priority <= h.items[i].Priority {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+		moved = true
+	}
+	return moved
+}
+
+// Move item down while any of it's children has less priority
+func (h *BinaryHeap) down(i int) {
+	n := len(h.items)
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left<n && h.items[left].Priority < h.items[smallest].Priority {
+			smallest = left
+		}
+		if right<n && h.items[right].Priority < h.items[smallest].Priority {
+			smallest = right
+		}
+		if smallest==i {
+			return
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PairingHeap
+
+type pairingNode struct {
+	Node VertexId
+	Priority float64
+	child *pairingNode
+	sibling *pairingNode
+	// parent for the leftmost child, left sibling for the others
+	prev *pairingNode
+}
+
+// Indexed pairing heap
+//
+// Push and DecreaseKey take O(1) time (DecreaseKey is o(log n) amortized),
+// Pop and Remove take O(log n) amortized. Usually faster than BinaryHeap on
+// graphs with a lot of decrease key operations.
+type PairingHeap struct {
+	root *pairingNode
+	nodes map[VertexId]*pairingNode
+}
+
+// Create new empty pairing heap
+func NewPairingHeap() *PairingHeap {
+	return &PairingHeap{
+		nodes: make(map[VertexId]*pairingNode),
+	}
+}
+
+func (h *PairingHeap) Push(node VertexId, priority float64) {
+	if err := h.TryPush(node, priority); err!=nil {
+		panicQueueError("Push vertex to pairing heap.", err, node, priority)
+	}
+}
+
+func (h *PairingHeap) TryPush(node VertexId, priority float64) error {
+	if _, ok := h.nodes[node]; ok {
+		return &VertexError{Op: "push", Node: node, Err: ErrNodeExists}
+	}
+	n := &pairingNode{Node: node, Priority: priority}
+	h.nodes[node] = n
+	h.root = pairingMeld(h.root, n)
+	return nil
+}
+
+func (h *PairingHeap) Pop() (VertexId, float64) {
+	if h.Empty() {
+		panic(erx.NewError("Can't pop from empty queue."))
+	}
+	top := h.root
+	delete(h.nodes, top.Node)
+	h.root = pairingMergePairs(top.child)
+	return top.Node, top.Priority
+}
+
+func (h *PairingHeap) Peek() (VertexId, float64) {
+	if h.Empty() {
+		panic(erx.NewError("Can't peek from empty queue."))
+	}
+	return h.root.Node, h.root.Priority
+}
+
+func (h *PairingHeap) DecreaseKey(node VertexId, priority float64) {
+	if err := h.TryDecreaseKey(node, priority); err!=nil {
+		panicQueueError("Decrease vertex priority in pairing heap.", err, node, priority)
+	}
+}
+
+func (h *PairingHeap) TryDecreaseKey(node VertexId, priority float64) error {
+	n, ok := h.nodes[node]
+	if !ok {
+		return &VertexError{Op: "decrease key", Node: node, Err: ErrNodeNotFound}
+	}
+	if priority > n.Priority {
+		return &VertexError{Op: "decrease key", Node: node, Err: ErrPriorityIncrease}
+	}
+	n.Priority = priority
+	if n!=h.root {
+		pairingCut(n)
+		h.root = pairingMeld(h.root, n)
+	}
+	return nil
+}
+
+func (h *PairingHeap) Remove(node VertexId) {
+	if err := h.TryRemove(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove vertex from pairing heap.", err, 1)
+		erxErr.AddV("node", node)
+		panic(erxErr)
+	}
+}
+
+func (h *PairingHeap) TryRemove(node VertexId) error {
+	n, ok := h.nodes[node]
+	if !ok {
+		return &VertexError{Op: "remove", Node: node, Err: ErrNodeNotFound}
+	}
+	if n==h.root {
+		h.Pop()
+		return nil
+	}
+	delete(h.nodes, node)
+	pairingCut(n)
+	h.root = pairingMeld(h.root, pairingMergePairs(n.child))
+	return nil
+}
+
+func (h *PairingHeap) Contains(node VertexId) bool {
+	_, ok := h.nodes[node]
+	return ok
+}
+
+func (h *PairingHeap) Priority(node VertexId) (float64, bool) {
+	if n, ok := h.nodes[node]; ok {
+		return n.Priority, true
+	}
+	return 0.0, false
+}
+
+func (h *PairingHeap) Len() int {
+	return len(h.nodes)
+}
+
+func (h *PairingHeap) Empty() bool {
+	return h.root==nil
+}
+
+// Merge two heaps and return new root
+func pairingMeld(a, b *pairingNode) *pairingNode {
+	if a==nil {
+		return b
+	}
+	if b==nil {
+		return a
+	}
+	if b.Priority < a.Priority {
+		a, b = b, a
+	}
+	// b becomes the leftmost child of a
+	b.prev = a
+	b.sibling = a.child
+	if a.child!=nil {
+		a.child.prev = b
+	}
+	a.child = b
+	a.sibling = nil
+	a.prev = nil
+	return a
+}
+
+// Merge list of siblings into single heap with standard two-pass method
+func pairingMergePairs(first *pairingNode) *pairingNode {
+	if first==nil {
+		return nil
+	}
+	// first pass: meld pairs from left to right
+	pairs := make([]*pairingNode, 0)
+	for first!=nil {
+		a := first
+		b := a.sibling
+		if b==nil {
+			a.prev = nil
+			pairs = append(pairs, a)
+			break
+		}
+		first = b.sibling
+		a.sibling, a.prev = nil, nil
+		b.sibling, b.prev = nil, nil
+		pairs = append(pairs, pairingMeld(a, b))
+	}
+	// second pass: meld results from right to left
+	root := pairs[len(pairs)-1]
+	for i:=len(pairs)-2; i>=0; i-- {
+		root = pairingMeld(pairs[i], root)
+	}
+	return root
+}
+
+// Detach node (with it's subtree) from it's parent children list
+func pairingCut(n *pairingNode) {
+	if n.prev.child==n {
+		n.prev.child = n.sibling
+	} else {
+		n.prev.sibling = n.sibling
+	}
+	if n.sibling!=nil {
+		n.sibling.prev = n.prev
+	}
+	n.prev, n.sibling = nil, nil
+}
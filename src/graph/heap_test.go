@@ -0,0 +1,225 @@
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+var priorityQueues = []struct {
+	name string
+	create func() VertexPriorityQueue
+}{
+	{"binary heap", func() VertexPriorityQueue { return NewBinaryHeap(2) }},
+	{"pairing heap", func() VertexPriorityQueue { return NewPairingHeap() }},
+}
+
+func expectPop(t *testing.T, q VertexPriorityQueue, node VertexId, priority float64) {
+	t.Helper()
+	n, p := q.Pop()
+	expectEquals(t, n, node)
+	expectEquals(t, p, priority)
+}
+
+func TestVertexPriorityQueue(t *testing.T) {
+	for _, impl := range priorityQueues {
+		newQueue := func() VertexPriorityQueue {
+			q := impl.create()
+			q.Push(1, 1.0)
+			q.Push(2, 2.0)
+			q.Push(3, 0.5)
+			q.Push(4, 1.5)
+			return q
+		}
+
+		t.Run(impl.name, func(t *testing.T) {
+			t.Run("empty queue", func(t *testing.T) {
+				q := impl.create()
+				expectTrue(t, q.Empty(), "empty")
+				expectEquals(t, q.Len(), 0)
+				expectPanic(t, "pop from empty queue", func() { q.Pop() })
+				expectPanic(t, "peek from empty queue", func() { q.Peek() })
+				q.Push(1, 0.5)
+				node, priority := q.Peek()
+				expectEquals(t, node, VertexId(1))
+				expectEquals(t, priority, 0.5)
+				expectEquals(t, q.Len(), 1)
+				expectPop(t, q, 1, 0.5)
+				expectTrue(t, q.Empty(), "empty")
+			})
+
+			t.Run("order", func(t *testing.T) {
+				q := newQueue()
+				expectEquals(t, q.Len(), 4)
+				expectPop(t, q, 3, 0.5)
+				expectPop(t, q, 1, 1.0)
+				expectPop(t, q, 4, 1.5)
+				expectPop(t, q, 2, 2.0)
+			})
+
+			t.Run("push again after pop", func(t *testing.T) {
+				q := newQueue()
+				expectPop(t, q, 3, 0.5)
+				expectFalse(t, q.Contains(3), "popped vertex in queue")
+				q.Push(3, 1.7)
+				expectPop(t, q, 1, 1.0)
+				expectPop(t, q, 4, 1.5)
+				expectPop(t, q, 3, 1.7)
+			})
+
+			t.Run("decrease key", func(t *testing.T) {
+				q := newQueue()
+				q.DecreaseKey(2, 0.1)
+				q.DecreaseKey(4, 1.5)
+				priority, ok := q.Priority(2)
+				expectTrue(t, ok, "vertex in queue")
+				expectEquals(t, priority, 0.1)
+				expectPop(t, q, 2, 0.1)
+				expectPop(t, q, 3, 0.5)
+				expectPop(t, q, 1, 1.0)
+				expectPop(t, q, 4, 1.5)
+
+				q = newQueue()
+				expectErrorIs(t, q.TryDecreaseKey(1, 3.0), ErrPriorityIncrease)
+				expectErrorIs(t, q.TryDecreaseKey(10, 0.0), ErrNodeNotFound)
+				expectPanic(t, "increase priority", func() { q.DecreaseKey(1, 3.0) })
+			})
+
+			t.Run("push or decrease key", func(t *testing.T) {
+				q := newQueue()
+				expectFalse(t, PushOrDecreaseKey(q, 3, 0.7), "priority increased")
+				expectTrue(t, PushOrDecreaseKey(q, 4, 0.2), "priority decreased")
+				expectTrue(t, PushOrDecreaseKey(q, 5, 0.3), "vertex added")
+				expectEquals(t, q.Len(), 5)
+				expectPop(t, q, 4, 0.2)
+				expectPop(t, q, 5, 0.3)
+				expectPop(t, q, 3, 0.5)
+			})
+
+			t.Run("remove", func(t *testing.T) {
+				q := newQueue()
+				q.Remove(1)
+				q.Remove(3)
+				expectFalse(t, q.Contains(1), "removed vertex in queue")
+				_, ok := q.Priority(3)
+				expectFalse(t, ok, "removed vertex has priority")
+				expectEquals(t, q.Len(), 2)
+				expectErrorIs(t, q.TryRemove(1), ErrNodeNotFound)
+				expectPanic(t, "remove unknown vertex", func() { q.Remove(10) })
+				expectPop(t, q, 4, 1.5)
+				expectPop(t, q, 2, 2.0)
+			})
+
+			t.Run("duplicate push", func(t *testing.T) {
+				q := newQueue()
+				expectErrorIs(t, q.TryPush(1, 0.1), ErrNodeExists)
+				expectPanic(t, "push existing vertex", func() { q.Push(1, 0.1) })
+				expectEquals(t, q.Len(), 4)
+			})
+
+			t.Run("random operations", func(t *testing.T) {
+				rnd := rand.New(rand.NewSource(1))
+				q := impl.create()
+				expected := make(map[VertexId]float64)
+				for i:=0; i<3000; i++ {
+					node := VertexId(rnd.Intn(200))
+					switch rnd.Intn(4) {
+						case 0, 1:
+							priority := rnd.Float64()*100
+							if PushOrDecreaseKey(q, node, priority) {
+								expected[node] = priority
+							}
+						case 2:
+							if q.TryRemove(node)==nil {
+								delete(expected, node)
+							}
+						case 3:
+							if !q.Empty() {
+								node, priority := q.Pop()
+								for _, other := range expected {
+									expectTrue(t, priority<=other, "popped min priority")
+								}
+								expectEquals(t, expected[node], priority)
+								delete(expected, node)
+							}
+					}
+					expectEquals(t, q.Len(), len(expected))
+				}
+				priorities := make([]float64, 0, len(expected))
+				for _, priority := range expected {
+					priorities = append(priorities, priority)
+				}
+				sort.Float64s(priorities)
+				for _, priority := range priorities {
+					_, p := q.Pop()
+					expectEquals(t, p, priority)
+				}
+			})
+		})
+	}
+}
+
+func benchmarkPriorityQueue(b *testing.B, create func() VertexPriorityQueue, size int) {
+	rnd := rand.New(rand.NewSource(1))
+	priorities := make([]float64, size)
+	for i, _ := range priorities {
+		priorities[i] = rnd.Float64()*float64(size)
+	}
+	b.ResetTimer()
+	for i:=0; i<b.N; i++ {
+		q := create()
+		for node, priority := range priorities {
+			q.Push(VertexId(node), priority)
+		}
+		for node, priority := range priorities {
+			q.DecreaseKey(VertexId(node), priority/2)
+		}
+		for !q.Empty() {
+			q.Pop()
+		}
+	}
+}
+
+// Time per element should grow logarithmically with queue size.
+func BenchmarkBinaryHeap(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			benchmarkPriorityQueue(b, func() VertexPriorityQueue { return NewBinaryHeap(size) }, size)
+		})
+	}
+}
+
+func BenchmarkPairingHeap(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			benchmarkPriorityQueue(b, func() VertexPriorityQueue { return NewPairingHeap() }, size)
+		})
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	gr := NewWeightedDirectedMap()
+	size := 10000
+	for i:=0; i<size; i++ {
+		gr.AddNode(VertexId(i))
+	}
+	for i:=0; i<size*5; i++ {
+		tail, head := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+		if tail!=head && !gr.CheckArc(tail, head) {
+			gr.AddWeightedArc(tail, head, rnd.Float64())
+		}
+	}
+	// frozen graph marks vertexes in slices instead of maps
+	for name, extractor := range map[string]OutNeighboursExtractor{
+		"map": NewDgraphOutNeighboursExtractor(gr),
+		"csr": NewDgraphOutNeighboursExtractor(FreezeDirected(gr)),
+	} {
+		b.Run(name, func(b *testing.B) {
+			for i:=0; i<b.N; i++ {
+				DijkstraSingleSource(extractor, 0, ArcWeightFunc(gr))
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func sortedVertexes(nodes []VertexId) []VertexId {
+	res := make([]VertexId, len(nodes))
+	copy(res, nodes)
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// Check that actual contains exactly expected vertexes in any order.
+func expectVertexesExactly(t *testing.T, actual []VertexId, expected ...VertexId) {
+	t.Helper()
+	a := sortedVertexes(actual)
+	e := sortedVertexes(expected)
+	if fmt.Sprint(a) != fmt.Sprint(e) {
+		t.Errorf("expected exactly %v, but got %v", expected, actual)
+	}
+}
+
+// Check that actual is exactly expected path (order matters).
+func expectPath(t *testing.T, actual []VertexId, expected ...VertexId) {
+	t.Helper()
+	if len(actual) != len(expected) || fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("expected path %v, but got %v", expected, actual)
+	}
+}
+
+func containsVertex(nodes []VertexId, node VertexId) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
+// Check that actual contains node.
+func expectContains(t *testing.T, actual []VertexId, node VertexId) {
+	t.Helper()
+	if !containsVertex(actual, node) {
+		t.Errorf("expected %v to contain %v", actual, node)
+	}
+}
+
+// Check that actual doesn't contain node.
+func expectNotContains(t *testing.T, actual []VertexId, node VertexId) {
+	t.Helper()
+	if containsVertex(actual, node) {
+		t.Errorf("expected %v not to contain %v", actual, node)
+	}
+}
+
+func expectTrue(t *testing.T, value bool, what string) {
+	t.Helper()
+	if !value {
+		t.Errorf("expected true: %v", what)
+	}
+}
+
+func expectFalse(t *testing.T, value bool, what string) {
+	t.Helper()
+	if value {
+		t.Errorf("expected false: %v", what)
+	}
+}
+
+func expectEquals(t *testing.T, actual, expected interface{}) {
+	t.Helper()
+	if actual != expected {
+		t.Errorf("expected %v, but got %v", expected, actual)
+	}
+}
+
+// Check that function f panics.
+func expectPanic(t *testing.T, what string, f func()) {
+	t.Helper()
+	defer func() {
+		if e := recover(); e == nil {
+			t.Errorf("expected panic: %v", what)
+		}
+	}()
+	f()
+}
@@ -2,18 +2,19 @@ package graph
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
 	"io"
-	"os"
-	"regexp"
 	"strconv"
 	"strings"
-	
-	"github.com/StepLg/go-erx/src/erx"
+
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 type graphWriterGeneric interface {
 	AddNode(vertex VertexId)
-	AddConnection(tail, head VertexId)
+	// Add connection, described by delimiter between vertexes in line
+	AddConnection(tail, head VertexId, delimiter byte)
 }
 
 type graphWriterGeneric_ugraph struct {
@@ -24,7 +25,7 @@ func (writer *graphWriterGeneric_ugraph) AddNode(vertex VertexId) {
 	writer.gr.AddNode(vertex)
 }
 
-func (writer *graphWriterGeneric_ugraph) AddConnection(tail, head VertexId) {
+func (writer *graphWriterGeneric_ugraph) AddConnection(tail, head VertexId, delimiter byte) {
 	writer.gr.AddEdge(tail, head)
 }
 
@@ -36,202 +37,363 @@ func (writer *graphWriterGeneric_dgraph) AddNode(vertex VertexId) {
 	writer.gr.AddNode(vertex)
 }
 
-func (writer *graphWriterGeneric_dgraph) AddConnection(tail, head VertexId) {
+func (writer *graphWriterGeneric_dgraph) AddConnection(tail, head VertexId, delimiter byte) {
 	writer.gr.AddArc(tail, head)
 }
 
-func readGraphLine(gr graphWriterGeneric, line string, connectionDelimiter string) {
-	line = strings.Trim(line, " \t\n")
+type graphWriterGeneric_mgraph struct {
+	gr MixedGraphWriter
+}
+
+func (writer *graphWriterGeneric_mgraph) AddNode(vertex VertexId) {
+	writer.gr.AddNode(vertex)
+}
+
+func (writer *graphWriterGeneric_mgraph) AddConnection(tail, head VertexId, delimiter byte) {
+	if delimiter=='>' {
+		writer.gr.AddArc(tail, head)
+	} else {
+		writer.gr.AddEdge(tail, head)
+	}
+}
+
+// Vertex in graph line with delimiter before it.
+type graphLineToken struct {
+	node VertexId
+	chunk string
+	column int // starting from 1
+	delimiter byte // connection delimiter before vertex, 0 for first vertex
+}
+
+// Converts vertex chunk from graph line to vertex id.
+type vertexParser func(chunk string) (VertexId, error)
+
+// Parse vertex id as non-negative integer.
+func parseVertexId(chunk string) (VertexId, error) {
+	nodeAsInt, err := strconv.Atoi(chunk)
+	if err!=nil {
+		return 0, err
+	}
+	if nodeAsInt<0 {
+		return 0, fmt.Errorf("%w: negative vertex id", ErrSyntax)
+	}
+	return VertexId(nodeAsInt), nil
+}
+
+// Parse vertex as label with labeler.
+func labelVertexParser(labeler *VertexLabeler) vertexParser {
+	return func(chunk string) (VertexId, error) {
+		return labeler.Id(chunk), nil
+	}
+}
+
+// Split graph line to vertexes and delimiters between them.
+//
+// delimiters contains all possible connection delimiters. If spaceDelimiter
+// isn't 0, then vertexes, separated only with spaces, are connected with
+// spaceDelimiter connection.
+func tokenizeGraphLine(line string, delimiters string, spaceDelimiter byte, parseVertex vertexParser) ([]graphLineToken, error) {
 	if commentPos := strings.Index(line, "#"); commentPos!=-1 {
 		// truncate comments
-		line = strings.Trim(line[0:commentPos], " \t\n")
+		line = line[0:commentPos]
 	}
-	
-	if line=="" {
-		// skip empty lines
-		return
+
+	isSpace := func(c byte) bool {
+		return c==' ' || c=='\t' || c=='\n' || c=='\r'
 	}
-	
-	if isMatch, err := regexp.MatchString("^[0-9]+$", line); isMatch && err==nil {
-		// only one number - it's vertex id
-		vertexId, err := strconv.Atoi(line)
-		if err!=nil {
-			panic(err)
+
+	tokens := make([]graphLineToken, 0, 2)
+	pos := 0
+	for {
+		// reading delimiters and spaces before vertex
+		var delimiter byte
+		delimitersCnt := 0
+		delimiterPos := pos
+		for pos<len(line) && (isSpace(line[pos]) || strings.IndexByte(delimiters, line[pos])!=-1) {
+			if !isSpace(line[pos]) {
+				delimiter = line[pos]
+				delimiterPos = pos
+				delimitersCnt++
+			}
+			pos++
+		}
+
+		if pos==len(line) {
+			if delimitersCnt>0 {
+				return nil, &ParseError{Column: delimiterPos+1, Chunk: string(delimiter), Err: fmt.Errorf("%w: connection without head vertex", ErrSyntax)}
+			}
+			break
+		}
+
+		if len(tokens)==0 && delimitersCnt>0 {
+			return nil, &ParseError{Column: delimiterPos+1, Chunk: string(delimiter), Err: fmt.Errorf("%w: connection without tail vertex", ErrSyntax)}
+		}
+		if delimitersCnt>1 {
+			return nil, &ParseError{Column: delimiterPos+1, Chunk: string(delimiter), Err: fmt.Errorf("%w: too many delimiters between vertexes", ErrSyntax)}
 		}
-		
-		gr.AddNode(VertexId(vertexId))
-	} else if err!=nil {
-		panic(err)
-	}
-
-	{
-		// removing spaces between delimiter and vertexes
-		reg1 := regexp.MustCompile("[ \t]*" + connectionDelimiter + "[ \t]*")
-		line = reg1.ReplaceAllString(line, connectionDelimiter)
-		
-		// replace spaces to delimiter between vertexes
-		reg2 := regexp.MustCompile("[ \t]+")
-		line = reg2.ReplaceAllString(line, " ")
-		line = strings.Replace(line, " ", connectionDelimiter, -1)
-	}
-
-	var prevVertexId VertexId
-	hasPrev := false
-	for _, nodeAsStr := range strings.Split(line, connectionDelimiter, -1) {
-		nodeAsStr = strings.Trim(nodeAsStr, " \t\n")
-		nodeAsInt, err := strconv.Atoi(nodeAsStr)
+		if len(tokens)>0 && delimitersCnt==0 {
+			if spaceDelimiter==0 {
+				return nil, &ParseError{Column: pos+1, Err: fmt.Errorf("%w: missing connection delimiter", ErrSyntax)}
+			}
+			delimiter = spaceDelimiter
+		}
+
+		// reading vertex
+		start := pos
+		for pos<len(line) && !isSpace(line[pos]) && strings.IndexByte(delimiters, line[pos])==-1 {
+			pos++
+		}
+		chunk := line[start:pos]
+		node, err := parseVertex(chunk)
 		if err!=nil {
-			errErx := erx.NewSequent("Can't parse node id.", err)
-			errErx.AddV("chunk", nodeAsStr)
-			panic(errErx)
+			return nil, &ParseError{Column: start+1, Chunk: chunk, Err: err}
 		}
-		
-		VertexId := VertexId(nodeAsInt)
-		if hasPrev {
-			gr.AddConnection(prevVertexId, VertexId)
-		} else {
-			hasPrev = true
+
+		tokens = append(tokens, graphLineToken{
+			node: node,
+			chunk: chunk,
+			column: start+1,
+			delimiter: delimiter,
+		})
+	}
+
+	return tokens, nil
+}
+
+func readGraphLine(gr graphWriterGeneric, line string, delimiters string, spaceDelimiter byte, parseVertex vertexParser) error {
+	tokens, err := tokenizeGraphLine(line, delimiters, spaceDelimiter, parseVertex)
+	if err!=nil {
+		return err
+	}
+
+	if len(tokens)==1 {
+		// only one vertex - adding it to graph
+		if err := catchPanic(func() { gr.AddNode(tokens[0].node) }); err!=nil {
+			return &ParseError{Column: tokens[0].column, Chunk: tokens[0].chunk, Err: err}
+		}
+		return nil
+	}
+
+	for i:=1; i<len(tokens); i++ {
+		tail := tokens[i-1]
+		head := tokens[i]
+		err := catchPanic(func() {
+			gr.AddConnection(tail.node, head.node, head.delimiter)
+		})
+		if err!=nil {
+			return &ParseError{Column: head.column, Chunk: head.chunk, Err: err}
 		}
-		prevVertexId = VertexId
 	}
-	return
+	return nil
+}
+
+// Parse undirected graph edges from line.
+//
+// Returns *ParseError if line can't be parsed or edge can't be added to graph.
+func TryReadUgraphLine(gr UndirectedGraphWriter, line string) error {
+	return readGraphLine(&graphWriterGeneric_ugraph{gr:gr}, line, "-", '-', parseVertexId)
+}
+
+// Parse directed graph arcs from line.
+//
+// Returns *ParseError if line can't be parsed or arc can't be added to graph.
+func TryReadDgraphLine(gr DirectedGraphWriter, line string) error {
+	return readGraphLine(&graphWriterGeneric_dgraph{gr:gr}, line, ">", '>', parseVertexId)
+}
+
+// Parse mixed graph arcs and edges from line.
+//
+// Returns *ParseError if line can't be parsed or connection can't be added
+// to graph.
+func TryReadMgraphLine(gr MixedGraphWriter, line string) error {
+	return readGraphLine(&graphWriterGeneric_mgraph{gr:gr}, line, "->", 0, parseVertexId)
 }
 
 func ReadUgraphLine(gr UndirectedGraphWriter, line string) {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Parsing graph edges from line.", e)
-			err.AddV("line", line)
-			panic(err)
-		}
-	}()
-	
-	readGraphLine(&graphWriterGeneric_ugraph{gr:gr}, line, "-")
+	if err := TryReadUgraphLine(gr, line); err!=nil {
+		erxErr := erx.NewSequentLevel("Parsing graph edges from line.", err, 1)
+		erxErr.AddV("line", line)
+		panic(erxErr)
+	}
 }
 
 func ReadDgraphLine(gr DirectedGraphWriter, line string) {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Parsing graph arcs from line.", e)
-			err.AddV("line", line)
-			panic(err)
-		}
-	}()
-	
-	readGraphLine(&graphWriterGeneric_dgraph{gr:gr}, line, ">")
+	if err := TryReadDgraphLine(gr, line); err!=nil {
+		erxErr := erx.NewSequentLevel("Parsing graph arcs from line.", err, 1)
+		erxErr.AddV("line", line)
+		panic(erxErr)
+	}
 }
 
 func ReadMgraphLine(gr MixedGraphWriter, line string) {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Parsing graph arcs and edges from line.", e)
-			err.AddV("line", line)
-			panic(err)
-		}
-	}()
-	
-	line = strings.Trim(line, " \t\n")
-	if commentPos := strings.Index(line, "#"); commentPos!=-1 {
-		// truncate comments
-		line = strings.Trim(line[0:commentPos], " \t\n")
-	}
-	
-	if line=="" {
-		// skip empty lines
-		return
+	if err := TryReadMgraphLine(gr, line); err!=nil {
+		erxErr := erx.NewSequentLevel("Parsing graph arcs and edges from line.", err, 1)
+		erxErr.AddV("line", line)
+		panic(erxErr)
 	}
-	
-	if isMatch, err := regexp.MatchString("^[0-9]+$", line); isMatch && err==nil {
-		// only one number - it's vertex id
-		vertexId, err := strconv.Atoi(line)
-		if err!=nil {
-			panic(err)
+}
+
+func readGraphFile(f io.Reader, lineParser func(string) error) error {
+	reader := bufio.NewReader(f)
+	lineNumber := 0
+	for {
+		line, err := reader.ReadString('\n')
+		if err!=nil && err!=io.EOF {
+			return err
 		}
-		
-		gr.AddNode(VertexId(vertexId))
-	} else if err!=nil {
-		panic(err)
-	}
-	
-	var prevVertexId VertexId
-	hasPrev := false
-	for _, nodeAsStr := range strings.Split(line, "-", -1) {
-		nodeAsStr = strings.Trim(nodeAsStr, " \t\n")
-		
-		if strings.Index(nodeAsStr, ">")!=-1 {
-			for index, nodeAsStr1 := range strings.Split(nodeAsStr, ">", -1) {
-				nodeAsStr1 = strings.Trim(nodeAsStr1, " \t\n")
-				nodeAsInt, err := strconv.Atoi(nodeAsStr1)
-				if err!=nil {
-					errErx := erx.NewSequent("Can't parse node id.", err)
-					errErx.AddV("chunk", nodeAsStr1)
-					panic(errErx)
+		if line!="" {
+			lineNumber++
+			if parseErr := lineParser(line); parseErr!=nil {
+				var lineErr *ParseError
+				if errors.As(parseErr, &lineErr) {
+					lineErr.Line = lineNumber
 				}
-				
-				VertexId := VertexId(nodeAsInt)
-				if hasPrev {
-					if index!=0 {
-						gr.AddArc(prevVertexId, VertexId)
-					} else {
-						gr.AddEdge(prevVertexId, VertexId)
-					}
-				} else {
-					hasPrev = true
-				}
-				prevVertexId = VertexId				
-			}
-		} else {
-			nodeAsInt, err := strconv.Atoi(nodeAsStr)
-			if err!=nil {
-				errErx := erx.NewSequent("Can't parse node id.", err)
-				errErx.AddV("chunk", nodeAsStr)
-				panic(errErx)
+				return parseErr
 			}
-			
-			VertexId := VertexId(nodeAsInt)
-			if hasPrev {
-				gr.AddEdge(prevVertexId, VertexId)
-			} else {
-				hasPrev = true
-			}
-			prevVertexId = VertexId
+		}
+		if err==io.EOF {
+			return nil
 		}
 	}
-	
 }
 
-func readGraphFile(f io.Reader, lineParser func(string)) {
-	reader := bufio.NewReader(f)
-	var err os.Error
-	var line string
-	line, err = reader.ReadString('\n');
-	for err==nil || err==os.EOF {
-		lineParser(line)
-		if err==os.EOF {
-			break
-		}
-		line, err = reader.ReadString('\n');
+// Read undirected graph from text representation.
+//
+// Returns *ParseError with line and column if input can't be parsed.
+func TryReadUgraphFile(f io.Reader, gr UndirectedGraphWriter) error {
+	return readGraphFile(f, func(line string) error {
+		return TryReadUgraphLine(gr, line)
+	})
+}
+
+// Read directed graph from text representation.
+//
+// Returns *ParseError with line and column if input can't be parsed.
+func TryReadDgraphFile(f io.Reader, gr DirectedGraphWriter) error {
+	return readGraphFile(f, func(line string) error {
+		return TryReadDgraphLine(gr, line)
+	})
+}
+
+// Read mixed graph from text representation.
+//
+// Returns *ParseError with line and column if input can't be parsed.
+func TryReadMgraphFile(f io.Reader, gr MixedGraphWriter) error {
+	return readGraphFile(f, func(line string) error {
+		return TryReadMgraphLine(gr, line)
+	})
+}
+
+func ReadUgraphFile(f io.Reader, gr UndirectedGraphWriter) {
+	if err := TryReadUgraphFile(f, gr); err!=nil {
+		panic(erx.NewSequentLevel("Error while reading file.", err, 1))
+	}
+}
+
+func ReadDgraphFile(f io.Reader, gr DirectedGraphWriter) {
+	if err := TryReadDgraphFile(f, gr); err!=nil {
+		panic(erx.NewSequentLevel("Error while reading file.", err, 1))
+	}
+}
+
+func ReadMgraphFile(f io.Reader, gr MixedGraphWriter) {
+	if err := TryReadMgraphFile(f, gr); err!=nil {
+		panic(erx.NewSequentLevel("Error while reading file.", err, 1))
 	}
-	if err!=nil && err!=os.EOF {
-		erxErr := erx.NewSequent("Error while reading file.", err)
+}
+
+// Parse undirected graph edges with labeled vertexes from line.
+//
+// Vertexes are any tokens without spaces, '#' and '-' characters. Their ids
+// are taken from labeler. Returns *ParseError if line can't be parsed or
+// edge can't be added to graph.
+func TryReadLabeledUgraphLine(gr UndirectedGraphWriter, labeler *VertexLabeler, line string) error {
+	return readGraphLine(&graphWriterGeneric_ugraph{gr:gr}, line, "-", '-', labelVertexParser(labeler))
+}
+
+// Parse directed graph arcs with labeled vertexes from line.
+//
+// Vertexes are any tokens without spaces, '#' and '>' characters. Their ids
+// are taken from labeler. Returns *ParseError if line can't be parsed or
+// arc can't be added to graph.
+func TryReadLabeledDgraphLine(gr DirectedGraphWriter, labeler *VertexLabeler, line string) error {
+	return readGraphLine(&graphWriterGeneric_dgraph{gr:gr}, line, ">", '>', labelVertexParser(labeler))
+}
+
+// Parse mixed graph arcs and edges with labeled vertexes from line.
+//
+// Vertexes are any tokens without spaces, '#', '-' and '>' characters. Their
+// ids are taken from labeler. Returns *ParseError if line can't be parsed or
+// connection can't be added to graph.
+func TryReadLabeledMgraphLine(gr MixedGraphWriter, labeler *VertexLabeler, line string) error {
+	return readGraphLine(&graphWriterGeneric_mgraph{gr:gr}, line, "->", 0, labelVertexParser(labeler))
+}
+
+func ReadLabeledUgraphLine(gr UndirectedGraphWriter, labeler *VertexLabeler, line string) {
+	if err := TryReadLabeledUgraphLine(gr, labeler, line); err!=nil {
+		erxErr := erx.NewSequentLevel("Parsing graph edges from line.", err, 1)
+		erxErr.AddV("line", line)
 		panic(erxErr)
 	}
 }
 
-func ReadUgraphFile(f io.Reader, gr UndirectedGraphWriter) {
-	readGraphFile(f, func(line string) {
-		ReadUgraphLine(gr, line)
+func ReadLabeledDgraphLine(gr DirectedGraphWriter, labeler *VertexLabeler, line string) {
+	if err := TryReadLabeledDgraphLine(gr, labeler, line); err!=nil {
+		erxErr := erx.NewSequentLevel("Parsing graph arcs from line.", err, 1)
+		erxErr.AddV("line", line)
+		panic(erxErr)
+	}
+}
+
+func ReadLabeledMgraphLine(gr MixedGraphWriter, labeler *VertexLabeler, line string) {
+	if err := TryReadLabeledMgraphLine(gr, labeler, line); err!=nil {
+		erxErr := erx.NewSequentLevel("Parsing graph arcs and edges from line.", err, 1)
+		erxErr.AddV("line", line)
+		panic(erxErr)
+	}
+}
+
+// Read undirected graph with labeled vertexes from text representation.
+//
+// Returns *ParseError with line and column if input can't be parsed.
+func TryReadLabeledUgraphFile(f io.Reader, gr UndirectedGraphWriter, labeler *VertexLabeler) error {
+	return readGraphFile(f, func(line string) error {
+		return TryReadLabeledUgraphLine(gr, labeler, line)
 	})
 }
 
-func ReadDgraphFile(f io.Reader, gr DirectedGraphWriter) {
-	readGraphFile(f, func(line string) {
-		ReadDgraphLine(gr, line)
+// Read directed graph with labeled vertexes from text representation.
+//
+// Returns *ParseError with line and column if input can't be parsed.
+func TryReadLabeledDgraphFile(f io.Reader, gr DirectedGraphWriter, labeler *VertexLabeler) error {
+	return readGraphFile(f, func(line string) error {
+		return TryReadLabeledDgraphLine(gr, labeler, line)
 	})
 }
 
-func ReadMgraphFile(f io.Reader, gr MixedGraphWriter) {
-	readGraphFile(f, func(line string) {
-		ReadMgraphLine(gr, line)
-	})	
+// Read mixed graph with labeled vertexes from text representation.
+//
+// Returns *ParseError with line and column if input can't be parsed.
+func TryReadLabeledMgraphFile(f io.Reader, gr MixedGraphWriter, labeler *VertexLabeler) error {
+	return readGraphFile(f, func(line string) error {
+		return TryReadLabeledMgraphLine(gr, labeler, line)
+	})
+}
+
+func ReadLabeledUgraphFile(f io.Reader, gr UndirectedGraphWriter, labeler *VertexLabeler) {
+	if err := TryReadLabeledUgraphFile(f, gr, labeler); err!=nil {
+		panic(erx.NewSequentLevel("Error while reading file.", err, 1))
+	}
+}
+
+func ReadLabeledDgraphFile(f io.Reader, gr DirectedGraphWriter, labeler *VertexLabeler) {
+	if err := TryReadLabeledDgraphFile(f, gr, labeler); err!=nil {
+		panic(erx.NewSequentLevel("Error while reading file.", err, 1))
+	}
+}
+
+func ReadLabeledMgraphFile(f io.Reader, gr MixedGraphWriter, labeler *VertexLabeler) {
+	if err := TryReadLabeledMgraphFile(f, gr, labeler); err!=nil {
+		panic(erx.NewSequentLevel("Error while reading file.", err, 1))
+	}
 }
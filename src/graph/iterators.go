@@ -1,24 +1,237 @@
 package graph
 
 import (
-	. "exp/iterable"
+	"iter"
 
-	"github.com/StepLg/go-erx/src/erx"
+	"github.com/StepLg/go-graph/src/erx"
 )
 
+// Convert function iterator to channel.
+//
+// Used to implement channel iterators on top of function ones. Spawns
+// goroutine, which blocks forever if consumer doesn't read all the values.
+func seqToChan[T any](seq iter.Seq[T]) <-chan T {
+	ch := make(chan T)
+	go func() {
+		for item := range seq {
+			ch <- item
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+// Convert channel to function iterator.
+//
+// If consumer stops iteration early, rest of the channel is read in
+// background, so goroutine, which writes to channel, isn't blocked forever.
+func chanToSeq[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range ch {
+			if !yield(item) {
+				go func() {
+					for _ = range ch {
+					}
+				}()
+				return
+			}
+		}
+	}
+}
+
+// Function iterator of vertexes iterable.
+//
+// Returns VertexesSeq() of iterable, if it implements VertexesSeqIterable.
+// Otherwise channel iterator is wrapped, and it's called each time iteration
+// starts.
+func VertexesSeqOf(iterable VertexesIterable) iter.Seq[VertexId] {
+	if seqIterable, ok := iterable.(VertexesSeqIterable); ok {
+		return seqIterable.VertexesSeq()
+	}
+	return func(yield func(VertexId) bool) {
+		chanToSeq(iterable.VertexesIter())(yield)
+	}
+}
+
+// Function iterator of connections iterable.
+func ConnectionsSeqOf(iterable ConnectionsIterable) iter.Seq[Connection] {
+	if seqIterable, ok := iterable.(ConnectionsSeqIterable); ok {
+		return seqIterable.ConnectionsSeq()
+	}
+	return func(yield func(Connection) bool) {
+		chanToSeq(iterable.ConnectionsIter())(yield)
+	}
+}
+
+// Function iterator of edges iterable.
+func EdgesSeqOf(iterable EdgesIterable) iter.Seq[Connection] {
+	if seqIterable, ok := iterable.(EdgesSeqIterable); ok {
+		return seqIterable.EdgesSeq()
+	}
+	return func(yield func(Connection) bool) {
+		chanToSeq(iterable.EdgesIter())(yield)
+	}
+}
+
+// Function iterator of arcs iterable.
+func ArcsSeqOf(iterable ArcsIterable) iter.Seq[Connection] {
+	if seqIterable, ok := iterable.(ArcsSeqIterable); ok {
+		return seqIterable.ArcsSeq()
+	}
+	return func(yield func(Connection) bool) {
+		chanToSeq(iterable.ArcsIter())(yield)
+	}
+}
+
+// Function iterator of typed connections iterable.
+func TypedConnectionsSeqOf(iterable TypedConnectionsIterable) iter.Seq[TypedConnection] {
+	if seqIterable, ok := iterable.(TypedConnectionsSeqIterable); ok {
+		return seqIterable.TypedConnectionsSeq()
+	}
+	return func(yield func(TypedConnection) bool) {
+		chanToSeq(iterable.TypedConnectionsIter())(yield)
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Graph wrappers function iterators
+
+// Function iterators of wrapped directed graph.
+//
+// Wrappers, which embed graph interface, don't get function iterators of
+// wrapped graph, so they embed this helper.
+type directedGraphSeqs struct {
+	gr DirectedGraph
+}
+
+func (s directedGraphSeqs) VertexesSeq() iter.Seq[VertexId] {
+	return VertexesSeqOf(s.gr)
+}
+
+func (s directedGraphSeqs) ConnectionsSeq() iter.Seq[Connection] {
+	return ConnectionsSeqOf(s.gr)
+}
+
+func (s directedGraphSeqs) ArcsSeq() iter.Seq[Connection] {
+	return ArcsSeqOf(s.gr)
+}
+
+// Function iterators of wrapped undirected graph.
+//
+// See directedGraphSeqs for details.
+type undirectedGraphSeqs struct {
+	gr UndirectedGraph
+}
+
+func (s undirectedGraphSeqs) VertexesSeq() iter.Seq[VertexId] {
+	return VertexesSeqOf(s.gr)
+}
+
+func (s undirectedGraphSeqs) ConnectionsSeq() iter.Seq[Connection] {
+	return ConnectionsSeqOf(s.gr)
+}
+
+func (s undirectedGraphSeqs) EdgesSeq() iter.Seq[Connection] {
+	return EdgesSeqOf(s.gr)
+}
+
+// Function iterators of wrapped mixed graph.
+//
+// See directedGraphSeqs for details.
+type mixedGraphSeqs struct {
+	gr MixedGraph
+}
+
+func (s mixedGraphSeqs) VertexesSeq() iter.Seq[VertexId] {
+	return VertexesSeqOf(s.gr)
+}
+
+func (s mixedGraphSeqs) ConnectionsSeq() iter.Seq[Connection] {
+	return ConnectionsSeqOf(s.gr)
+}
+
+func (s mixedGraphSeqs) ArcsSeq() iter.Seq[Connection] {
+	return ArcsSeqOf(s.gr)
+}
+
+func (s mixedGraphSeqs) EdgesSeq() iter.Seq[Connection] {
+	return EdgesSeqOf(s.gr)
+}
+
+func (s mixedGraphSeqs) TypedConnectionsSeq() iter.Seq[TypedConnection] {
+	return TypedConnectionsSeqOf(s.gr)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Generic iterable object.
+//
+// Iterates over values of any type. Used to chain and transform iterables
+// of different kinds.
+type Iterable interface {
+	Iter() <-chan interface{}
+}
+
+type SeqIterable interface {
+	Seq() iter.Seq[interface{}]
+}
+
+// Function iterator of generic iterable.
+//
+// See VertexesSeqOf for details.
+func SeqOf(iterable Iterable) iter.Seq[interface{}] {
+	if seqIterable, ok := iterable.(SeqIterable); ok {
+		return seqIterable.Seq()
+	}
+	return func(yield func(interface{}) bool) {
+		chanToSeq(iterable.Iter())(yield)
+	}
+}
+
+type chainIterableHelper struct {
+	iters []Iterable
+}
+
+func (helper *chainIterableHelper) Iter() <-chan interface{} {
+	return seqToChan(helper.Seq())
+}
+
+func (helper *chainIterableHelper) Seq() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for _, iter := range helper.iters {
+			for item := range SeqOf(iter) {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Chain several iterables into one.
+//
+// Result iterable yields all items from first iterable, then all items from
+// second one and so on.
+func ChainIterables(iters ...Iterable) Iterable {
+	return Iterable(&chainIterableHelper{iters:iters})
+}
+
 type connectionsIterableHelper struct {
 	connIter ConnectionsIterable
 }
 
 func (helper *connectionsIterableHelper) Iter() <-chan interface{} {
-	ch := make(chan interface{})
-	go func() {
-		for arr := range helper.connIter.ConnectionsIter() {
-			ch <- arr
+	return seqToChan(helper.Seq())
+}
+
+func (helper *connectionsIterableHelper) Seq() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for arr := range ConnectionsSeqOf(helper.connIter) {
+			if !yield(arr) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 type connectionsGenericIterableHelper struct {
@@ -26,14 +239,17 @@ type connectionsGenericIterableHelper struct {
 }
 
 func (helper *connectionsGenericIterableHelper) ConnectionsIter() <-chan Connection {
-	ch := make(chan Connection)
-	go func() {
-		for arr := range helper.iter.Iter() {
-			ch <- arr.(Connection)
+	return seqToChan(helper.ConnectionsSeq())
+}
+
+func (helper *connectionsGenericIterableHelper) ConnectionsSeq() iter.Seq[Connection] {
+	return func(yield func(Connection) bool) {
+		for arr := range SeqOf(helper.iter) {
+			if !yield(arr.(Connection)) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 // Transform connections iterable to generic iterable object.
@@ -51,14 +267,17 @@ type nodesIterableHelper struct {
 }
 
 func (helper *nodesIterableHelper) Iter() <-chan interface{} {
-	ch := make(chan interface{})
-	go func() {
-		for node := range helper.nodesIter.VertexesIter() {
-			ch <- node
+	return seqToChan(helper.Seq())
+}
+
+func (helper *nodesIterableHelper) Seq() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		for node := range VertexesSeqOf(helper.nodesIter) {
+			if !yield(node) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 type nodesGenericIterableHelper struct {
@@ -66,14 +285,17 @@ type nodesGenericIterableHelper struct {
 }
 
 func (helper *nodesGenericIterableHelper) VertexesIter() <-chan VertexId {
-	ch := make(chan VertexId)
-	go func() {
-		for node := range helper.iter.Iter() {
-			ch <- node.(VertexId)
+	return seqToChan(helper.VertexesSeq())
+}
+
+func (helper *nodesGenericIterableHelper) VertexesSeq() iter.Seq[VertexId] {
+	return func(yield func(VertexId) bool) {
+		for node := range SeqOf(helper.iter) {
+			if !yield(node.(VertexId)) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 // Transform vertexes iterable to generic iterable object.
@@ -86,11 +308,29 @@ func GenericToVertexesIter(iter Iterable) VertexesIterable {
 	return VertexesIterable(&nodesGenericIterableHelper{iter:iter})
 }
 
+// Chain several vertexes iterables into one.
+//
+// Result iterable yields all vertexes from first iterable, then all vertexes
+// from second one and so on. Unlike ChainIterables, there are no type
+// conversions.
+func ChainVertexes(iters ...VertexesIterable) VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for _, iter := range iters {
+			for node := range VertexesSeqOf(iter) {
+				if !yield(node) {
+					return
+				}
+			}
+		}
+	}
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
 // Collect all vertexes from iterator to slice.
 func CollectVertexes(iter VertexesIterable) []VertexId {
 	res := make([]VertexId, 10)
 	i := 0
-	for node := range iter.VertexesIter() {
+	for node := range VertexesSeqOf(iter) {
 		if i==len(res) {
 			tmp := make([]VertexId, 2*i)
 			copy(tmp, res)
@@ -108,7 +348,7 @@ func CollectVertexes(iter VertexesIterable) []VertexId {
 // For all connections from iterator check isCorrectOrder function 
 // and add to directed graph connection in correct order
 func BuildDirectedGraph(gr DirectedGraph, connIterable ConnectionsIterable , isCorrectOrder func(Connection) bool) {
-	for arr := range connIterable.ConnectionsIter() {
+	for arr := range ConnectionsSeqOf(connIterable) {
 		if isCorrectOrder(arr) {
 			gr.AddArc(arr.Tail, arr.Head)
 		} else {
@@ -119,31 +359,51 @@ func BuildDirectedGraph(gr DirectedGraph, connIterable ConnectionsIterable , isC
 
 // Copy all arcs from iterator to directed graph
 //
+// If both iterator and graph are AttributedGraph, then attributes of
+// copied arcs and their vertexes are copied too.
+//
 // todo: merge with CopyUndirectedGraph
 func CopyDirectedGraph(connIter ConnectionsIterable, gr DirectedGraphArcsWriter) {
 	// wheel := erx.NewError("Can't copy directed graph")
-	for arrow := range connIter.ConnectionsIter() {
+	fromAttrs, toAttrs := attributesForCopy(connIter, gr)
+	for arrow := range ConnectionsSeqOf(connIter) {
 		gr.AddArc(arrow.Tail, arrow.Head)
+		if toAttrs!=nil {
+			toAttrs.copyConnection(fromAttrs, NewDirectedConnection(arrow.Tail, arrow.Head),
+				NewDirectedConnection(arrow.Tail, arrow.Head), NewUndirectedConnection(arrow.Tail, arrow.Head))
+		}
 	}
 	return
 }
 
 // Copy all arcs from iterator to directed graph
 //
+// If both iterator and graph are AttributedGraph, then attributes of
+// copied edges and their vertexes are copied too.
+//
 // todo: add VertexesIterable interface and copy all nodes before copying arcs
 func CopyUndirectedGraph(connIter ConnectionsIterable, gr UndirectedGraphEdgesWriter) {
 	// wheel := erx.NewError("Can't copy directed graph")
-	for arrow := range connIter.ConnectionsIter() {
+	fromAttrs, toAttrs := attributesForCopy(connIter, gr)
+	for arrow := range ConnectionsSeqOf(connIter) {
 		gr.AddEdge(arrow.Tail, arrow.Head)
+		if toAttrs!=nil {
+			toAttrs.copyConnection(fromAttrs, NewUndirectedConnection(arrow.Tail, arrow.Head),
+				NewUndirectedConnection(arrow.Tail, arrow.Head), NewDirectedConnection(arrow.Tail, arrow.Head))
+		}
 	}
 	return
 }
 
 // Copy all connections from iterator to mixed graph
 //
+// If both iterator and graph are AttributedGraph, then attributes of
+// copied connections and their vertexes are copied too.
+//
 // todo: merge with CopyDirectedGraph
 func CopyMixedGraph(from TypedConnectionsIterable, to MixedGraphWriter) {
-	for conn := range from.TypedConnectionsIter() {
+	fromAttrs, toAttrs := attributesForCopy(from, to)
+	for conn := range TypedConnectionsSeqOf(from) {
 		switch conn.Type {
 			case CT_UNDIRECTED:
 				to.AddEdge(conn.Tail, conn.Head)
@@ -153,6 +413,9 @@ func CopyMixedGraph(from TypedConnectionsIterable, to MixedGraphWriter) {
 				err := erx.NewError("Internal error: unknown connection type")
 				panic(err)
 		}
+		if toAttrs!=nil {
+			toAttrs.copyConnection(fromAttrs, conn, conn)
+		}
 	}
 }
 
@@ -165,6 +428,10 @@ func (helper *arcsToConnIterable_helper) ConnectionsIter() <-chan Connection {
 	return helper.gr.ArcsIter()
 }
 
+func (helper *arcsToConnIterable_helper) ConnectionsSeq() iter.Seq[Connection] {
+	return ArcsSeqOf(helper.gr)
+}
+
 // Convert arcs iterator to connections iterator.
 func ArcsToConnIterable(gr DirectedGraphArcsReader) ConnectionsIterable {
 	return &arcsToConnIterable_helper{gr}
@@ -179,6 +446,10 @@ func (helper *edgesToConnIterable_helper) ConnectionsIter() <-chan Connection {
 	return helper.gr.EdgesIter()
 }
 
+func (helper *edgesToConnIterable_helper) ConnectionsSeq() iter.Seq[Connection] {
+	return EdgesSeqOf(helper.gr)
+}
+
 // Convert edges iterator to connections iterator.
 func EdgesToConnIterable(gr UndirectedGraphEdgesReader) ConnectionsIterable {
 	return &edgesToConnIterable_helper{gr}
@@ -190,14 +461,17 @@ type arcsToTypedConnIterable_helper struct {
 }
 
 func (helper *arcsToTypedConnIterable_helper) TypedConnectionsIter() <-chan TypedConnection {
-	ch := make(chan TypedConnection)
-	go func() {
-		for conn := range helper.gr.ArcsIter() {
-			ch <- TypedConnection{Connection: conn, Type: CT_DIRECTED}
+	return seqToChan(helper.TypedConnectionsSeq())
+}
+
+func (helper *arcsToTypedConnIterable_helper) TypedConnectionsSeq() iter.Seq[TypedConnection] {
+	return func(yield func(TypedConnection) bool) {
+		for conn := range ArcsSeqOf(helper.gr) {
+			if !yield(TypedConnection{Connection: conn, Type: CT_DIRECTED}) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 // Convert arcs iterator to typed connections iterator.
@@ -211,14 +485,17 @@ type edgesToTypedConnIterable_helper struct {
 }
 
 func (helper *edgesToTypedConnIterable_helper) TypedConnectionsIter() <-chan TypedConnection {
-	ch := make(chan TypedConnection)
-	go func() {
-		for conn := range helper.gr.EdgesIter() {
-			ch <- TypedConnection{Connection: conn, Type: CT_UNDIRECTED}
+	return seqToChan(helper.TypedConnectionsSeq())
+}
+
+func (helper *edgesToTypedConnIterable_helper) TypedConnectionsSeq() iter.Seq[TypedConnection] {
+	return func(yield func(TypedConnection) bool) {
+		for conn := range EdgesSeqOf(helper.gr) {
+			if !yield(TypedConnection{Connection: conn, Type: CT_UNDIRECTED}) {
+				return
+			}
 		}
-		close(ch)
-	}()
-	return ch
+	}
 }
 
 // Convert edges iterator to typed connections iterator.
@@ -227,14 +504,18 @@ func EdgesToTypedConnIterable(gr UndirectedGraphEdgesReader) TypedConnectionsIte
 }
 
 
-// Vertexes iterable object for function, which returns VertexId channel.
+// Vertexes iterable object for function iterator.
 //
-// Internal use only at this moment. Don't know what for it could be used
-// outside.
+// Internal use only at this moment. Channel iterator is built on top of
+// function one.
 type nodesIterableLambdaHelper struct {
-	iterFunc func() <-chan VertexId
+	seq iter.Seq[VertexId]
 }
 
 func (helper *nodesIterableLambdaHelper) VertexesIter() <-chan VertexId {
-	return helper.iterFunc()
+	return seqToChan(helper.seq)
+}
+
+func (helper *nodesIterableLambdaHelper) VertexesSeq() iter.Seq[VertexId] {
+	return helper.seq
 }
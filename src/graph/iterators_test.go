@@ -2,74 +2,64 @@ package graph
 
 import (
 	"fmt"
-	"os"
+	"runtime"
 	"testing"
-	"github.com/orfjackal/gospec/src/gospec"
-	. "github.com/orfjackal/gospec/src/gospec"
+	"time"
 )
 
-func DirectedGraphEquals(actual interface{}, expected interface{}) (match bool, pos os.Error, neg os.Error, err os.Error) {
-	match = false
-	neg = Errorf("Didn't expect that directed graphs are equal.")
-	if aGr, ok := actual.(DirectedGraph); ok {
-		if eGr, ok1 := expected.(DirectedGraph); ok1 {
-			match = true
-			missed := ""
-			for arrow := range eGr.ConnectionsIter() {
-				isExist := aGr.CheckArc(arrow.Tail, arrow.Head)
-				if !isExist {
-					match = false
-					if missed != "" {
-						missed += ", "
-					}
-					missed += string(arrow.Tail) + "->" + string(arrow.Head) 
-				}
-			}
-			
-			phantom := ""
-			for arrow := range aGr.ConnectionsIter() {
-				isExist := eGr.CheckArc(arrow.Tail, arrow.Head)
-				if !isExist {
-					match = false
-					if phantom!="" {
-						phantom += ", "
-					}
-					phantom += fmt.Sprintf("%v->%v", arrow.Tail, arrow.Head) 
-				}
-			}
-			
-			errorText := "Actual graph"
-			if missed!="" {
-				errorText += " miss " + missed + " arrows"
-			}
-			if missed!="" && phantom!="" {
-				errorText += " and"
+// Check that two directed graphs contain the same arcs.
+func expectDirectedGraphEquals(t *testing.T, actual, expected DirectedGraph) {
+	t.Helper()
+	missed := ""
+	for arrow := range expected.ConnectionsIter() {
+		isExist := actual.CheckArc(arrow.Tail, arrow.Head)
+		if !isExist {
+			if missed != "" {
+				missed += ", "
 			}
+			missed += fmt.Sprintf("%v->%v", arrow.Tail, arrow.Head)
+		}
+	}
+
+	phantom := ""
+	for arrow := range actual.ConnectionsIter() {
+		isExist := expected.CheckArc(arrow.Tail, arrow.Head)
+		if !isExist {
 			if phantom!="" {
-				errorText += " contain " + phantom + " phantom arrows"
+				phantom += ", "
 			}
-			errorText += "."
-			pos = Errorf(errorText)
-		} else {
-			err = Errorf("Expected DirectedGraph in actual, but was '%v' of type '%T'", expected, expected) 
+			phantom += fmt.Sprintf("%v->%v", arrow.Tail, arrow.Head)
 		}
-	} else {
-		err = Errorf("Expected DirectedGraph in actual, but was '%v' of type '%T'", actual, actual) 
 	}
-	
-	return
+
+	if missed=="" && phantom=="" {
+		return
+	}
+
+	errorText := "Actual graph"
+	if missed!="" {
+		errorText += " miss " + missed + " arrows"
+	}
+	if missed!="" && phantom!="" {
+		errorText += " and"
+	}
+	if phantom!="" {
+		errorText += " contain " + phantom + " phantom arrows"
+	}
+	errorText += "."
+	t.Error(errorText)
 }
 
-func ArrowsIteratorSpec(c gospec.Context) {
-	gr := DirectedGraph(NewDirectedMap())
-	
-	c.Specify("Copy empty graph", func() {
+func TestArrowsIterator(t *testing.T) {
+	t.Run("Copy empty graph", func(t *testing.T) {
+		gr := NewDirectedMap()
 		gr1 := NewDirectedMap()
 		CopyDirectedGraph(gr, gr1)
-		c.Expect(gr1, DirectedGraphEquals, gr)
+		expectDirectedGraphEquals(t, gr1, gr)
 	})
-	
-	c.Specify("Copy simple directed graph", func() {
+
+	t.Run("Copy simple directed graph", func(t *testing.T) {
+		gr := NewDirectedMap()
 		gr1 := NewDirectedMap()
 		gr.AddArc(1, 2)
 		gr.AddArc(2, 3)
@@ -77,12 +67,110 @@ func ArrowsIteratorSpec(c gospec.Context) {
 		gr.AddArc(5, 1)
 
 		CopyDirectedGraph(gr, gr1)
-		c.Expect(gr1, DirectedGraphEquals, gr)
+		expectDirectedGraphEquals(t, gr1, gr)
 	})
 }
 
-func TestArrowsIteratorSpec(t *testing.T) {
-	r := gospec.NewRunner()
-	r.AddSpec(ArrowsIteratorSpec)
-	gospec.MainGoTest(r, t)
+func TestFunctionIterators(t *testing.T) {
+	newDgraph := func() *DirectedMap {
+		gr := NewDirectedMap()
+		for i:=1; i<=100; i++ {
+			gr.AddArc(0, VertexId(i))
+		}
+		return gr
+	}
+
+	t.Run("Seq yields the same as channel iterator", func(t *testing.T) {
+		gr := newDgraph()
+		seqNodes := make([]VertexId, 0)
+		for node := range VertexesSeqOf(gr.GetAccessors(0)) {
+			seqNodes = append(seqNodes, node)
+		}
+		expectVertexesExactly(t, seqNodes, CollectVertexes(gr.GetAccessors(0))...)
+		expectEquals(t, len(seqNodes), 100)
+	})
+
+	t.Run("Early break doesn't leak goroutines", func(t *testing.T) {
+		gr := newDgraph()
+		before := runtime.NumGoroutine()
+		for i:=0; i<100; i++ {
+			for range gr.VertexesSeq() {
+				break
+			}
+			for range gr.ArcsSeq() {
+				break
+			}
+			for range VertexesSeqOf(NewDgraphOutNeighboursExtractor(gr).GetOutNeighbours(0)) {
+				break
+			}
+		}
+		expectEquals(t, runtime.NumGoroutine(), before)
+	})
+
+	t.Run("Callback stops iteration", func(t *testing.T) {
+		gr := newDgraph()
+		calls := 0
+		gr.ArcsSeq()(func(conn Connection) bool {
+			calls++
+			return calls<3
+		})
+		expectEquals(t, calls, 3)
+	})
+
+	t.Run("Chain vertexes", func(t *testing.T) {
+		gr := NewMixedMap()
+		gr.AddArc(1, 2)
+		gr.AddEdge(1, 3)
+		gr.AddArc(4, 1)
+		expectVertexesExactly(t, CollectVertexes(NewMgraphOutNeighboursExtractor(gr).GetOutNeighbours(1)), 2, 3)
+		expectVertexesExactly(t, CollectVertexes(NewMgraphInNeighboursExtractor(gr).GetInNeighbours(1)), 3, 4)
+	})
+
+	t.Run("Channel only iterables", func(t *testing.T) {
+		// embedded interface hides function iterators of graph, as in
+		// graphs implemented outside the package
+		gr := struct{ DirectedGraph }{newDgraph()}
+		_, isSeqIterable := interface{}(gr).(ArcsSeqIterable)
+		expectFalse(t, isSeqIterable, "channel only graph has function iterator")
+
+		arcsCnt := 0
+		for _ = range ArcsSeqOf(gr) {
+			arcsCnt++
+		}
+		expectEquals(t, arcsCnt, 100)
+		expectEquals(t, len(CollectVertexes(gr)), 101)
+		_, hasCycles := TopologicalSort(gr)
+		expectFalse(t, hasCycles, "graph has cycles")
+
+		// rest of channel is read after early break
+		before := runtime.NumGoroutine()
+		for range VertexesSeqOf(gr) {
+			break
+		}
+		for i:=0; i<1000 && runtime.NumGoroutine()>before; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		expectEquals(t, runtime.NumGoroutine(), before)
+	})
+
+	t.Run("Wrappers keep function iterators", func(t *testing.T) {
+		var labeled interface{} = NewLabeledDirectedGraph(newDgraph())
+		_, isSeqIterable := labeled.(ArcsSeqIterable)
+		expectTrue(t, isSeqIterable, "labeled graph has function iterator")
+		var attributed interface{} = NewAttributedMixedGraph(NewMixedMap())
+		_, isSeqIterable = attributed.(TypedConnectionsSeqIterable)
+		expectTrue(t, isSeqIterable, "attributed graph has function iterator")
+	})
+
+	t.Run("Mixed map arcs", func(t *testing.T) {
+		gr := NewMixedMap()
+		gr.AddArc(1, 2)
+		gr.AddEdge(2, 3)
+		arcs := make([]Connection, 0)
+		for conn := range gr.ArcsSeq() {
+			arcs = append(arcs, conn)
+		}
+		expectEquals(t, len(arcs), gr.ArcsCnt())
+		expectEquals(t, arcs[0], Connection{1, 2})
+	})
 }
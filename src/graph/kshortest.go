@@ -0,0 +1,203 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Path with it's weight.
+type WeightedPath struct {
+	Path []VertexId
+	Weight float64
+}
+
+// Find k shortest simple paths with Yen's algorithm
+//
+// Returns no more than k paths without repeated vertexes in order of their
+// weights. Each next path is searched with Dijkstra algorithm from vertexes of
+// previous paths (spur vertexes), with masked connections, which lead to
+// already found paths. Masking uses DirectedGraphArcsFilter,
+// UndirectedGraphEdgesFilter and MixedGraphConnectionsFilter for extractors,
+// created with NewDgraphOutNeighboursExtractor, NewUgraphOutNeighboursExtractor
+// and NewMgraphOutNeighboursExtractor, other extractors are filtered as is.
+//
+// All weights must be non-negative, otherwise function panics.
+func KShortestPaths(extractor OutNeighboursExtractor, from, to VertexId, k int, weightFunc ConnectionWeightFunc) []WeightedPath {
+	paths, err := TryKShortestPaths(extractor, from, to, k, weightFunc)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Find k shortest paths.", err, 1)
+		erxErr.AddV("from", from)
+		erxErr.AddV("to", to)
+		erxErr.AddV("k", k)
+		panic(erxErr)
+	}
+	return paths
+}
+
+// Error-returning version of KShortestPaths.
+//
+// Returns *ConnectionError with ErrNegativeWeight if weightFunc returns
+// negative value for any checked connection. Panics from extractor are
+// returned as errors too.
+func TryKShortestPaths(extractor OutNeighboursExtractor, from, to VertexId, k int, weightFunc ConnectionWeightFunc) (paths []WeightedPath, err error) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			paths, err = nil, recoveredToError(e)
+		}
+	}()
+	
+	paths = make([]WeightedPath, 0, k)
+	if k<=0 {
+		return paths, nil
+	}
+	marks, err := TryDijkstraSingleSource(extractor, from, weightFunc, to)
+	if err!=nil {
+		return nil, err
+	}
+	if _, ok := marks[to]; !ok {
+		return paths, nil
+	}
+	paths = append(paths, WeightedPath{Path: PathFromMarks(marks, to), Weight: marks[to].Weight})
+	
+	candidates := make([]WeightedPath, 0)
+	known := map[string]bool{pathKey(paths[0].Path): true}
+	for len(paths)<k {
+		prevPath := paths[len(paths)-1].Path
+		rootWeight := 0.0
+		for i:=0; i<len(prevPath)-1; i++ {
+			spurVertex := prevPath[i]
+			rootPath := prevPath[:i+1]
+			
+			// connections from spur vertex to already found paths with the same root
+			masked := make([]Connection, 0)
+			for _, path := range paths {
+				if len(path.Path)>i+1 && equalPaths(path.Path[:i+1], rootPath) {
+					masked = append(masked, Connection{Tail: path.Path[i], Head: path.Path[i+1]})
+				}
+			}
+			// root path vertexes can't be used in spur path
+			removed := make(map[VertexId]bool, i)
+			for _, vertex := range rootPath[:i] {
+				removed[vertex] = true
+			}
+			spurExtractor := &vertexesFilterExtractor{
+				OutNeighboursExtractor: maskConnections(extractor, masked),
+				removed: removed,
+			}
+			
+			spurMarks, err := TryDijkstraSingleSource(spurExtractor, spurVertex, weightFunc, to)
+			if err!=nil {
+				return nil, err
+			}
+			if spurMark, ok := spurMarks[to]; ok {
+				path := make([]VertexId, 0, len(rootPath))
+				path = append(path, rootPath[:i]...)
+				path = append(path, PathFromMarks(spurMarks, to)...)
+				if key := pathKey(path); !known[key] {
+					known[key] = true
+					candidates = append(candidates, WeightedPath{Path: path, Weight: rootWeight + spurMark.Weight})
+				}
+			}
+			rootWeight += weightFunc(prevPath[i], prevPath[i+1])
+		}
+		
+		if len(candidates)==0 {
+			break
+		}
+		// stable sort keeps candidates with equal weights in order of finding
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Weight < candidates[j].Weight })
+		paths = append(paths, candidates[0])
+		candidates = candidates[1:]
+	}
+	return paths, nil
+}
+
+// Unique string representation of path
+func pathKey(path []VertexId) string {
+	return fmt.Sprint(path)
+}
+
+func equalPaths(path1, path2 []VertexId) bool {
+	if len(path1)!=len(path2) {
+		return false
+	}
+	for i, vertex := range path1 {
+		if path2[i]!=vertex {
+			return false
+		}
+	}
+	return true
+}
+
+// Neighbours extractor without given connections
+//
+// Uses graph filters for extractors of this package.
+func maskConnections(extractor OutNeighboursExtractor, conns []Connection) OutNeighboursExtractor {
+	if len(conns)==0 {
+		return extractor
+	}
+	// undirected graph filter modifies connections
+	edges := make([]Connection, len(conns))
+	copy(edges, conns)
+	switch e := extractor.(type) {
+		case *dgraphOutNeighboursExtractor:
+			return NewDgraphOutNeighboursExtractor(NewDirectedGraphArcsFilter(e.dgraph, conns))
+		case *ugraphOutNeighboursExtractor:
+			return NewUgraphOutNeighboursExtractor(NewUndirectedGraphEdgesFilter(e.ugraph, edges))
+		case *mgraphOutNeighboursExtractor:
+			return &mixedFilterExtractor{NewMixedGraphArcsFilter(e.mgraph, conns, edges)}
+	}
+	return &connectionsFilterExtractor{OutNeighboursExtractor: extractor, conns: conns}
+}
+
+// Accessors and neighbours in filtered mixed graph.
+type mixedFilterExtractor struct {
+	filter *MixedGraphConnectionsFilter
+}
+
+func (e *mixedFilterExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	return ChainVertexes(e.filter.DirectedGraphArcsFilter.GetAccessors(node), e.filter.UndirectedGraphEdgesFilter.GetNeighbours(node))
+}
+
+// Generic neighbours extractor without given connections.
+type connectionsFilterExtractor struct {
+	OutNeighboursExtractor
+	conns []Connection
+}
+
+func (e *connectionsFilterExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for next := range VertexesSeqOf(e.OutNeighboursExtractor.GetOutNeighbours(node)) {
+			filtered := false
+			for _, conn := range e.conns {
+				if conn.Tail==node && conn.Head==next {
+					filtered = true
+					break
+				}
+			}
+			if !filtered && !yield(next) {
+				return
+			}
+		}
+	}
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
+
+// Neighbours extractor without given vertexes.
+type vertexesFilterExtractor struct {
+	OutNeighboursExtractor
+	removed map[VertexId]bool
+}
+
+func (e *vertexesFilterExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	iterator := func(yield func(VertexId) bool) {
+		for next := range VertexesSeqOf(e.OutNeighboursExtractor.GetOutNeighbours(node)) {
+			if !e.removed[next] && !yield(next) {
+				return
+			}
+		}
+	}
+	return VertexesIterable(&nodesIterableLambdaHelper{seq:iterator})
+}
@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// Generic extractor, unknown to connections masking.
+type genericExtractor struct {
+	gr DirectedGraphArcsReader
+}
+
+func (e genericExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	return e.gr.GetAccessors(node)
+}
+
+func pathWeight(path []VertexId, weightFunc ConnectionWeightFunc) float64 {
+	weight := 0.0
+	for i:=1; i<len(path); i++ {
+		weight += weightFunc(path[i-1], path[i])
+	}
+	return weight
+}
+
+func expectKShortestPaths(t *testing.T, extractor OutNeighboursExtractor, from, to VertexId, k int, weightFunc ConnectionWeightFunc) {
+	t.Helper()
+	allWeights := make([]float64, 0)
+	for path := range GetAllPaths(extractor, from, to) {
+		allWeights = append(allWeights, pathWeight(path, weightFunc))
+	}
+	sort.Float64s(allWeights)
+	if len(allWeights)>k {
+		allWeights = allWeights[:k]
+	}
+
+	paths := KShortestPaths(extractor, from, to, k, weightFunc)
+	expectEquals(t, len(paths), len(allWeights))
+	known := make(map[string]bool)
+	for i, path := range paths {
+		expectEquals(t, path.Weight, allWeights[i])
+		expectEquals(t, pathWeight(path.Path, weightFunc), path.Weight)
+		expectEquals(t, path.Path[0], from)
+		expectEquals(t, path.Path[len(path.Path)-1], to)
+		visited := make(map[VertexId]bool)
+		for j, vertex := range path.Path {
+			expectFalse(t, visited[vertex], "path is simple")
+			visited[vertex] = true
+			if j>0 {
+				expectTrue(t, containsVertex(CollectVertexes(extractor.GetOutNeighbours(path.Path[j-1])), vertex), "path connection exists")
+			}
+		}
+		key := pathKey(path.Path)
+		expectFalse(t, known[key], "path is unique")
+		known[key] = true
+	}
+}
+
+func TestKShortestPaths(t *testing.T) {
+	t.Run("directed graph", func(t *testing.T) {
+		gr := generateDirectedGraph1()
+		extractor := NewDgraphOutNeighboursExtractor(gr)
+		paths := KShortestPaths(extractor, 1, 4, 5, SimpleWeightFunc)
+		expectEquals(t, len(paths), 2)
+		expectPath(t, paths[0].Path, 1, 2, 4)
+		expectEquals(t, paths[0].Weight, 2.0)
+		expectPath(t, paths[1].Path, 1, 2, 3, 4)
+		expectEquals(t, paths[1].Weight, 3.0)
+
+		expectEquals(t, len(KShortestPaths(extractor, 5, 1, 3, SimpleWeightFunc)), 0)
+		expectEquals(t, len(KShortestPaths(extractor, 1, 4, 0, SimpleWeightFunc)), 0)
+		expectEquals(t, len(KShortestPaths(extractor, 1, 4, 1, SimpleWeightFunc)), 1)
+	})
+
+	t.Run("random graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		size := 9
+		for i:=0; i<30; i++ {
+			dgr := NewDirectedMap()
+			ugr := NewUndirectedMap()
+			mgr := NewMixedMap()
+			for node:=0; node<size; node++ {
+				dgr.AddNode(VertexId(node))
+				ugr.AddNode(VertexId(node))
+				mgr.AddNode(VertexId(node))
+			}
+			for j:=0; j<size*2; j++ {
+				tail, head := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+				if tail==head {
+					continue
+				}
+				if !dgr.CheckArc(tail, head) {
+					dgr.AddArc(tail, head)
+				}
+				if !ugr.CheckEdge(tail, head) {
+					ugr.AddEdge(tail, head)
+				}
+				if mgr.CheckEdgeType(tail, head)==CT_NONE {
+					if j%2==0 {
+						mgr.AddArc(tail, head)
+					} else {
+						mgr.AddEdge(tail, head)
+					}
+				}
+			}
+			weightFunc := symmetricWeightFunc(int64(i))
+			from, to := VertexId(0), VertexId(size-1)
+			expectKShortestPaths(t, NewDgraphOutNeighboursExtractor(dgr), from, to, 6, weightFunc)
+			expectKShortestPaths(t, genericExtractor{dgr}, from, to, 6, weightFunc)
+			expectKShortestPaths(t, NewUgraphOutNeighboursExtractor(ugr), from, to, 6, weightFunc)
+			expectKShortestPaths(t, NewMgraphOutNeighboursExtractor(mgr), from, to, 6, weightFunc)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		gr := generateDirectedGraph1()
+		negative := func(tail, head VertexId) float64 { return -1.0 }
+		_, err := TryKShortestPaths(NewDgraphOutNeighboursExtractor(gr), 1, 4, 3, negative)
+		expectErrorIs(t, err, ErrNegativeWeight)
+		expectPanic(t, "negative weight", func() { KShortestPaths(NewDgraphOutNeighboursExtractor(gr), 1, 4, 3, negative) })
+	})
+}
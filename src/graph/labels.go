@@ -0,0 +1,391 @@
+package graph
+
+import (
+	"context"
+	"iter"
+
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Bidirectional mapping between string labels and vertexes ids (intern table).
+//
+// Ids are assigned sequentially starting from 0 in order of first label
+// occurrence, so graph should be filled only through labeler, otherwise
+// labeler ids could collide with existing ones.
+type VertexLabeler struct {
+	ids map[string]VertexId
+	labels []string // label of vertex id is labels[id]
+}
+
+func NewVertexLabeler() *VertexLabeler {
+	return &VertexLabeler{
+		ids: make(map[string]VertexId),
+		labels: make([]string, 0),
+	}
+}
+
+// Getting vertex id by label.
+//
+// New id is assigned if there is no such label yet.
+func (l *VertexLabeler) Id(label string) VertexId {
+	if id, ok := l.ids[label]; ok {
+		return id
+	}
+	id := VertexId(len(l.labels))
+	l.ids[label] = id
+	l.labels = append(l.labels, label)
+	return id
+}
+
+// Getting vertex id by label without assigning new one.
+func (l *VertexLabeler) LookupId(label string) (VertexId, bool) {
+	id, ok := l.ids[label]
+	return id, ok
+}
+
+// Getting vertex label.
+func (l *VertexLabeler) Label(node VertexId) string {
+	label, err := l.TryLabel(node)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Get vertex label.", err, 1)
+		erxErr.AddV("node", node)
+		panic(erxErr)
+	}
+	return label
+}
+
+// Getting vertex label.
+//
+// Returns ErrNodeNotFound if vertex id wasn't assigned by labeler.
+func (l *VertexLabeler) TryLabel(node VertexId) (string, error) {
+	if uint(node)>=uint(len(l.labels)) {
+		return "", &VertexError{Op: "get label", Node: node, Err: ErrNodeNotFound}
+	}
+	return l.labels[node], nil
+}
+
+// Getting labels of vertexes list.
+//
+// Useful to resolve results of TopologicalSort, PathFromMarks and so on.
+func (l *VertexLabeler) Labels(nodes []VertexId) []string {
+	labels, err := l.TryLabels(nodes)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Get vertexes labels.", err, 1)
+		erxErr.AddV("nodes", nodes)
+		panic(erxErr)
+	}
+	return labels
+}
+
+// Getting labels of vertexes list.
+//
+// Returns ErrNodeNotFound if one of vertexes ids wasn't assigned by labeler.
+func (l *VertexLabeler) TryLabels(nodes []VertexId) ([]string, error) {
+	labels := make([]string, len(nodes))
+	for i, node := range nodes {
+		label, err := l.TryLabel(node)
+		if err!=nil {
+			return nil, err
+		}
+		labels[i] = label
+	}
+	return labels, nil
+}
+
+// Getting vertexes ids by labels.
+//
+// New ids are assigned for unknown labels.
+func (l *VertexLabeler) Ids(labels ...string) Vertexes {
+	nodes := make(Vertexes, len(labels))
+	for i, label := range labels {
+		nodes[i] = l.Id(label)
+	}
+	return nodes
+}
+
+// Resolving paths from channel (for example from GetAllPathsContext) to labels.
+//
+// Result channel is closed after source channel or when ctx is done, so
+// consumer could stop reading at any moment by cancelling ctx. Usually the
+// same ctx should be passed to source paths generator. Channel is closed too
+// on the first path with vertex id, which wasn't assigned by labeler.
+func (l *VertexLabeler) LabelPaths(ctx context.Context, paths <-chan []VertexId) <-chan []string {
+	ch := make(chan []string)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+				case path, ok := <-paths:
+					if !ok {
+						return
+					}
+					labels, err := l.TryLabels(path)
+					if err!=nil {
+						return
+					}
+					select {
+						case ch <- labels:
+						case <-ctx.Done():
+							return
+					}
+				case <-ctx.Done():
+					return
+			}
+		}
+	}()
+	return ch
+}
+
+// Resolving paths from iterator (for example from AllPathsSeq) to labels.
+//
+// Iteration stops on the first path with vertex id, which wasn't assigned by
+// labeler.
+func (l *VertexLabeler) LabelPathsSeq(paths iter.Seq[[]VertexId]) iter.Seq[[]string] {
+	return func(yield func([]string) bool) {
+		for path := range paths {
+			labels, err := l.TryLabels(path)
+			if err!=nil || !yield(labels) {
+				return
+			}
+		}
+	}
+}
+
+// Labels count.
+func (l *VertexLabeler) Len() int {
+	return len(l.labels)
+}
+
+// Calling add with ids of labels, which are assigned only if add succeeds.
+//
+// Ids of new labels are the same, as Id would assign.
+func (l *VertexLabeler) tryWithIds(add func(ids Vertexes) error, labels ...string) error {
+	ids := make(Vertexes, len(labels))
+	newLabels := make([]string, 0, len(labels))
+	newIds := make(map[string]VertexId)
+	for i, label := range labels {
+		if id, ok := l.ids[label]; ok {
+			ids[i] = id
+		} else if id, ok := newIds[label]; ok {
+			ids[i] = id
+		} else {
+			ids[i] = VertexId(len(l.labels)+len(newLabels))
+			newIds[label] = ids[i]
+			newLabels = append(newLabels, label)
+		}
+	}
+	if err := add(ids); err!=nil {
+		return err
+	}
+	for _, label := range newLabels {
+		l.Id(label)
+	}
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Directed graph with string vertexes labels.
+//
+// All VertexLabeler methods are available to convert labels to ids and back.
+type LabeledDirectedGraph struct {
+	DirectedGraph
+	directedGraphSeqs
+	*VertexLabeler
+}
+
+func NewLabeledDirectedGraph(gr DirectedGraph) *LabeledDirectedGraph {
+	return &LabeledDirectedGraph{
+		DirectedGraph: gr,
+		directedGraphSeqs: directedGraphSeqs{gr},
+		VertexLabeler: NewVertexLabeler(),
+	}
+}
+
+// Adding node by label
+func (g *LabeledDirectedGraph) AddNodeByLabel(label string) {
+	g.AddNode(g.Id(label))
+}
+
+// Adding node by label
+//
+// Returns ErrNodeExists if node with such label is already in graph.
+func (g *LabeledDirectedGraph) TryAddNodeByLabel(label string) error {
+	return g.tryWithIds(func(ids Vertexes) error {
+		return tryAddNode(g.DirectedGraph, ids[0])
+	}, label)
+}
+
+// Adding arc between labeled nodes
+func (g *LabeledDirectedGraph) AddArcByLabel(from, to string) {
+	g.AddArc(g.Id(from), g.Id(to))
+}
+
+// Adding arc between labeled nodes
+//
+// Returns ErrDuplicateConnection if arc already exists.
+func (g *LabeledDirectedGraph) TryAddArcByLabel(from, to string) error {
+	return g.tryWithIds(func(ids Vertexes) error {
+		return tryAddArc(g.DirectedGraph, ids[0], ids[1])
+	}, from, to)
+}
+
+// Checking arc existance between labeled nodes
+func (g *LabeledDirectedGraph) CheckArcByLabel(from, to string) bool {
+	fromId, fromOk := g.LookupId(from)
+	toId, toOk := g.LookupId(to)
+	return fromOk && toOk && g.CheckArc(fromId, toId)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Undirected graph with string vertexes labels.
+//
+// All VertexLabeler methods are available to convert labels to ids and back.
+type LabeledUndirectedGraph struct {
+	UndirectedGraph
+	undirectedGraphSeqs
+	*VertexLabeler
+}
+
+func NewLabeledUndirectedGraph(gr UndirectedGraph) *LabeledUndirectedGraph {
+	return &LabeledUndirectedGraph{
+		UndirectedGraph: gr,
+		undirectedGraphSeqs: undirectedGraphSeqs{gr},
+		VertexLabeler: NewVertexLabeler(),
+	}
+}
+
+// Adding node by label
+func (g *LabeledUndirectedGraph) AddNodeByLabel(label string) {
+	g.AddNode(g.Id(label))
+}
+
+// Adding node by label
+//
+// Returns ErrNodeExists if node with such label is already in graph.
+func (g *LabeledUndirectedGraph) TryAddNodeByLabel(label string) error {
+	return g.tryWithIds(func(ids Vertexes) error {
+		return tryAddNode(g.UndirectedGraph, ids[0])
+	}, label)
+}
+
+// Adding edge between labeled nodes
+func (g *LabeledUndirectedGraph) AddEdgeByLabel(node1, node2 string) {
+	g.AddEdge(g.Id(node1), g.Id(node2))
+}
+
+// Adding edge between labeled nodes
+//
+// Returns ErrDuplicateConnection if edge already exists.
+func (g *LabeledUndirectedGraph) TryAddEdgeByLabel(node1, node2 string) error {
+	return g.tryWithIds(func(ids Vertexes) error {
+		return tryAddEdge(g.UndirectedGraph, ids[0], ids[1])
+	}, node1, node2)
+}
+
+// Checking edge existance between labeled nodes
+func (g *LabeledUndirectedGraph) CheckEdgeByLabel(node1, node2 string) bool {
+	id1, ok1 := g.LookupId(node1)
+	id2, ok2 := g.LookupId(node2)
+	return ok1 && ok2 && g.CheckEdge(id1, id2)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Mixed graph with string vertexes labels.
+//
+// All VertexLabeler methods are available to convert labels to ids and back.
+type LabeledMixedGraph struct {
+	MixedGraph
+	mixedGraphSeqs
+	*VertexLabeler
+}
+
+func NewLabeledMixedGraph(gr MixedGraph) *LabeledMixedGraph {
+	return &LabeledMixedGraph{
+		MixedGraph: gr,
+		mixedGraphSeqs: mixedGraphSeqs{gr},
+		VertexLabeler: NewVertexLabeler(),
+	}
+}
+
+// Adding node by label
+func (g *LabeledMixedGraph) AddNodeByLabel(label string) {
+	g.AddNode(g.Id(label))
+}
+
+// Adding node by label
+//
+// Returns ErrNodeExists if node with such label is already in graph.
+func (g *LabeledMixedGraph) TryAddNodeByLabel(label string) error {
+	return g.tryWithIds(func(ids Vertexes) error {
+		return tryAddNode(g.MixedGraph, ids[0])
+	}, label)
+}
+
+// Adding arc between labeled nodes
+func (g *LabeledMixedGraph) AddArcByLabel(from, to string) {
+	g.AddArc(g.Id(from), g.Id(to))
+}
+
+// Adding arc between labeled nodes
+//
+// Returns ErrDuplicateConnection if arc already exists.
+func (g *LabeledMixedGraph) TryAddArcByLabel(from, to string) error {
+	return g.tryWithIds(func(ids Vertexes) error {
+		return tryAddArc(g.MixedGraph, ids[0], ids[1])
+	}, from, to)
+}
+
+// Adding edge between labeled nodes
+func (g *LabeledMixedGraph) AddEdgeByLabel(node1, node2 string) {
+	g.AddEdge(g.Id(node1), g.Id(node2))
+}
+
+// Adding edge between labeled nodes
+//
+// Returns ErrDuplicateConnection if edge already exists.
+func (g *LabeledMixedGraph) TryAddEdgeByLabel(node1, node2 string) error {
+	return g.tryWithIds(func(ids Vertexes) error {
+		return tryAddEdge(g.MixedGraph, ids[0], ids[1])
+	}, node1, node2)
+}
+
+// Checking arc existance between labeled nodes
+func (g *LabeledMixedGraph) CheckArcByLabel(from, to string) bool {
+	fromId, fromOk := g.LookupId(from)
+	toId, toOk := g.LookupId(to)
+	return fromOk && toOk && g.CheckArc(fromId, toId)
+}
+
+// Checking edge existance between labeled nodes
+func (g *LabeledMixedGraph) CheckEdgeByLabel(node1, node2 string) bool {
+	id1, ok1 := g.LookupId(node1)
+	id2, ok2 := g.LookupId(node2)
+	return ok1 && ok2 && g.CheckEdge(id1, id2)
+}
+
+// Adding node with error-returning API, if graph supports it.
+func tryAddNode(gr GraphVertexesWriter, node VertexId) error {
+	if writer, ok := gr.(GraphVertexesTryWriter); ok {
+		return writer.TryAddNode(node)
+	}
+	return catchPanic(func() { gr.AddNode(node) })
+}
+
+// Adding arc with error-returning API, if graph supports it.
+func tryAddArc(gr DirectedGraphArcsWriter, from, to VertexId) error {
+	if writer, ok := gr.(DirectedGraphArcsTryWriter); ok {
+		return writer.TryAddArc(from, to)
+	}
+	return catchPanic(func() { gr.AddArc(from, to) })
+}
+
+// Adding edge with error-returning API, if graph supports it.
+func tryAddEdge(gr UndirectedGraphEdgesWriter, node1, node2 VertexId) error {
+	if writer, ok := gr.(UndirectedGraphEdgesTryWriter); ok {
+		return writer.TryAddEdge(node1, node2)
+	}
+	return catchPanic(func() { gr.AddEdge(node1, node2) })
+}
@@ -0,0 +1,167 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestVertexLabeler(t *testing.T) {
+	l := NewVertexLabeler()
+	expectEquals(t, l.Id("a"), VertexId(0))
+	expectEquals(t, l.Id("b"), VertexId(1))
+	expectEquals(t, l.Id("a"), VertexId(0))
+	expectEquals(t, l.Len(), 2)
+	expectEquals(t, l.Label(1), "b")
+
+	_, ok := l.LookupId("c")
+	expectFalse(t, ok, "unknown label exists")
+	expectEquals(t, l.Len(), 2)
+
+	_, err := l.TryLabel(5)
+	expectErrorIs(t, err, ErrNodeNotFound)
+	expectPanic(t, "unknown vertex label", func() { l.Labels(Vertexes{0, 5}) })
+}
+
+func TestLabeledGraphs(t *testing.T) {
+	t.Run("Directed", func(t *testing.T) {
+		gr := NewLabeledDirectedGraph(NewDirectedMap())
+		gr.AddArcByLabel("app", "lib")
+		gr.AddArcByLabel("lib", "runtime")
+		gr.AddArcByLabel("app", "runtime")
+		gr.AddNodeByLabel("standalone")
+
+		expectTrue(t, gr.CheckArcByLabel("app", "lib"), "arc app->lib")
+		expectFalse(t, gr.CheckArcByLabel("lib", "app"), "arc lib->app")
+		expectFalse(t, gr.CheckArcByLabel("app", "unknown"), "arc to unknown node")
+		expectEquals(t, gr.Order(), 4)
+
+		nodes, hasCycles := TopologicalSort(gr)
+		expectFalse(t, hasCycles, "graph has cycles")
+		labels := gr.Labels(nodes)
+		pos := make(map[string]int)
+		for i, label := range labels {
+			pos[label] = i
+		}
+		expectTrue(t, pos["app"]<pos["lib"] && pos["lib"]<pos["runtime"], "topological order "+strings.Join(labels, ","))
+
+		marks := BellmanFordSingleSource(gr, gr.Id("app"), SimpleWeightFunc)
+		expectEquals(t, strings.Join(gr.Labels(PathFromMarks(marks, gr.Id("runtime"))), ","), "app,runtime")
+
+		pathsCnt := 0
+		for path := range gr.LabelPaths(context.Background(), GetAllDirectedPaths(gr, gr.Id("app"), gr.Id("runtime"))) {
+			expectEquals(t, path[0], "app")
+			expectEquals(t, path[len(path)-1], "runtime")
+			pathsCnt++
+		}
+		expectEquals(t, pathsCnt, 2)
+
+		// consumer stops reading after the first path
+		ctx, cancel := context.WithCancel(context.Background())
+		paths := GetAllPathsContext(ctx, NewDgraphOutNeighboursExtractor(gr), gr.Id("app"), gr.Id("runtime"), PathsOptions{})
+		labeledPaths := gr.LabelPaths(ctx, paths)
+		<-labeledPaths
+		cancel()
+		for _ = range labeledPaths {
+		}
+
+		pathsCnt = 0
+		for path := range gr.LabelPathsSeq(AllPathsSeq(NewDgraphOutNeighboursExtractor(gr), gr.Id("app"), gr.Id("runtime"), PathsOptions{})) {
+			expectEquals(t, path[0], "app")
+			pathsCnt++
+			break
+		}
+		expectEquals(t, pathsCnt, 1)
+
+		// resolving stops on vertex, unknown to labeler
+		unknownPaths := [][]VertexId{{gr.Id("app"), 100}, {gr.Id("app")}}
+		pathsCnt = 0
+		for _ = range gr.LabelPathsSeq(func(yield func([]VertexId) bool) {
+			for _, path := range unknownPaths {
+				if !yield(path) {
+					return
+				}
+			}
+		}) {
+			pathsCnt++
+		}
+		expectEquals(t, pathsCnt, 0)
+		unknownCh := make(chan []VertexId, len(unknownPaths))
+		for _, path := range unknownPaths {
+			unknownCh <- path
+		}
+		close(unknownCh)
+		pathsCnt = 0
+		for _ = range gr.LabelPaths(context.Background(), unknownCh) {
+			pathsCnt++
+		}
+		expectEquals(t, pathsCnt, 0)
+
+		expectErrorIs(t, gr.TryAddNodeByLabel("standalone"), ErrNodeExists)
+		expectErrorIs(t, gr.TryAddArcByLabel("app", "lib"), ErrDuplicateConnection)
+		expectEquals(t, gr.TryAddNodeByLabel("new"), nil)
+		expectEquals(t, gr.TryAddArcByLabel("new", "app"), nil)
+		expectTrue(t, gr.CheckArcByLabel("new", "app"), "arc new->app")
+	})
+
+	t.Run("Undirected", func(t *testing.T) {
+		gr := NewLabeledUndirectedGraph(NewUndirectedMap())
+		gr.AddEdgeByLabel("a", "b")
+		expectTrue(t, gr.CheckEdgeByLabel("b", "a"), "edge b-a")
+		expectErrorIs(t, gr.TryAddNodeByLabel("a"), ErrNodeExists)
+		expectErrorIs(t, gr.TryAddEdgeByLabel("b", "a"), ErrDuplicateConnection)
+	})
+
+	t.Run("Mixed", func(t *testing.T) {
+		gr := NewLabeledMixedGraph(NewMixedMatrix(3))
+		gr.AddArcByLabel("a", "b")
+		gr.AddEdgeByLabel("b", "c")
+		expectTrue(t, gr.CheckArcByLabel("a", "b"), "arc a->b")
+		expectTrue(t, gr.CheckEdgeByLabel("c", "b"), "edge c-b")
+		expectFalse(t, gr.CheckEdgeByLabel("a", "b"), "edge a-b")
+		expectErrorIs(t, gr.TryAddNodeByLabel("c"), ErrNodeExists)
+		expectErrorIs(t, gr.TryAddArcByLabel("a", "b"), ErrDuplicateConnection)
+		expectErrorIs(t, gr.TryAddEdgeByLabel("b", "c"), ErrDuplicateConnection)
+
+		// labels of failed connections aren't interned
+		expectErrorIs(t, gr.TryAddEdgeByLabel("d", "d"), ErrLoop)
+		expectErrorIs(t, gr.TryAddArcByLabel("e", "e"), ErrLoop)
+		_, known := gr.LookupId("d")
+		expectFalse(t, known, "label of failed edge is known")
+		expectEquals(t, gr.Len(), 3)
+		expectEquals(t, gr.TryAddArcByLabel("d", "a"), nil)
+		expectEquals(t, gr.Id("d"), VertexId(3))
+		expectTrue(t, gr.CheckArcByLabel("d", "a"), "arc d->a")
+	})
+}
+
+func TestReadLabeledGraph(t *testing.T) {
+	t.Run("Directed", func(t *testing.T) {
+		gr := NewDirectedMap()
+		labeler := NewVertexLabeler()
+		input := "net/http > io > errors # comment\nmain>net/http\n42\n"
+		err := TryReadLabeledDgraphFile(strings.NewReader(input), gr, labeler)
+		expectEquals(t, err, nil)
+		expectEquals(t, gr.ArcsCnt(), 3)
+		expectTrue(t, gr.CheckArc(labeler.Id("main"), labeler.Id("net/http")), "arc main->net/http")
+		expectTrue(t, gr.CheckNode(labeler.Id("42")), "node 42")
+	})
+
+	t.Run("Mixed", func(t *testing.T) {
+		gr := NewMixedMap()
+		labeler := NewVertexLabeler()
+		ReadLabeledMgraphLine(gr, labeler, "a > b - c_1")
+		expectTrue(t, gr.CheckArc(labeler.Id("a"), labeler.Id("b")), "arc a->b")
+		expectTrue(t, gr.CheckEdge(labeler.Id("c_1"), labeler.Id("b")), "edge c_1-b")
+	})
+
+	t.Run("Syntax error", func(t *testing.T) {
+		gr := NewUndirectedMap()
+		labeler := NewVertexLabeler()
+		err := TryReadLabeledUgraphFile(strings.NewReader("a - b\nb - - c\n"), gr, labeler)
+		expectErrorIs(t, err, ErrSyntax)
+		parseErr, ok := err.(*ParseError)
+		expectTrue(t, ok, "parse error")
+		expectEquals(t, parseErr.Line, 2)
+	})
+}
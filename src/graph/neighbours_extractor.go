@@ -1,9 +1,5 @@
 package graph
 
-import (
-	. "exp/iterable"
-)	
-
 // Extract all vertexes, which are accessible from given node.
 type OutNeighboursExtractor interface {
 	GetOutNeighbours(node VertexId) VertexesIterable
@@ -44,10 +40,7 @@ type mgraphOutNeighboursExtractor struct {
 }
 
 func (e *mgraphOutNeighboursExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
-	return GenericToVertexesIter(Chain(&[...]Iterable{
-		VertexesToGenericIter(e.mgraph.GetAccessors(node)), 
-		VertexesToGenericIter(e.mgraph.GetNeighbours(node)),
-	}))
+	return ChainVertexes(e.mgraph.GetAccessors(node), e.mgraph.GetNeighbours(node))
 }
 
 // Extract all vertexes, accessible from given node in mixed graph.
@@ -101,10 +94,7 @@ type mgraphInNeighboursExtractor struct {
 }
 
 func (e *mgraphInNeighboursExtractor) GetInNeighbours(node VertexId) VertexesIterable {
-	return GenericToVertexesIter(Chain(&[...]Iterable{
-		VertexesToGenericIter(e.mgraph.GetPredecessors(node)), 
-		VertexesToGenericIter(e.mgraph.GetNeighbours(node)),
-	}))
+	return ChainVertexes(e.mgraph.GetPredecessors(node), e.mgraph.GetNeighbours(node))
 }
 
 // Extract all vertexes, accessible from given node in mixed graph.
@@ -114,3 +104,40 @@ func (e *mgraphInNeighboursExtractor) GetInNeighbours(node VertexId) VertexesIte
 func NewMgraphInNeighboursExtractor(gr MixedGraphConnectionsReader) InNeighboursExtractor {
 	return InNeighboursExtractor(&mgraphInNeighboursExtractor{mgraph:gr})
 }
+
+////////////////////////////////////////////////////////////////////////////////
+
+
+// Extract vertexes in both directions.
+//
+// Used by bidirectional search algorithms.
+type NeighboursExtractor interface {
+	OutNeighboursExtractor
+	InNeighboursExtractor
+}
+
+type neighboursExtractorPair struct {
+	OutNeighboursExtractor
+	InNeighboursExtractor
+}
+
+// Combine out and in neighbours extractors into one.
+func NewNeighboursExtractor(out OutNeighboursExtractor, in InNeighboursExtractor) NeighboursExtractor {
+	return NeighboursExtractor(&neighboursExtractorPair{OutNeighboursExtractor: out, InNeighboursExtractor: in})
+}
+
+// Extract accessors and predecessors of given node in directed graph.
+func NewDgraphNeighboursExtractor(gr DirectedGraphArcsReader) NeighboursExtractor {
+	return NewNeighboursExtractor(NewDgraphOutNeighboursExtractor(gr), NewDgraphInNeighboursExtractor(gr))
+}
+
+// Extract neighbours of given node in undirected graph in both directions.
+func NewUgraphNeighboursExtractor(gr UndirectedGraphEdgesReader) NeighboursExtractor {
+	return NewNeighboursExtractor(NewUgraphOutNeighboursExtractor(gr), NewUgraphInNeighboursExtractor(gr))
+}
+
+// Extract vertexes, accessible from given node and from which given node is
+// accessible, in mixed graph.
+func NewMgraphNeighboursExtractor(gr MixedGraphConnectionsReader) NeighboursExtractor {
+	return NewNeighboursExtractor(NewMgraphOutNeighboursExtractor(gr), NewMgraphInNeighboursExtractor(gr))
+}
@@ -3,6 +3,7 @@ package graph
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"strconv"
 )
@@ -38,11 +39,15 @@ func (conn TypedConnection) String() string {
 }
 
 func styleMapToString(style map[string]string) string {
-	chunks := make([]string, len(style))
-	i := 0
-	for k, v := range style {
-		chunks[i] = fmt.Sprintf("%v=\"%v\"", k, v)
-		i++
+	keys := make([]string, 0, len(style))
+	for k, _ := range style {
+		keys = append(keys, k)
+	}
+	// sorting properties to make output stable
+	sort.Strings(keys)
+	chunks := make([]string, len(keys))
+	for i, k := range keys {
+		chunks[i] = fmt.Sprintf("%v=\"%v\"", k, style[k])
 	}
 	return "[" + strings.Join(chunks, ",") + "]"
 }
@@ -81,7 +86,7 @@ func PlotVertexesToDot(nodesIter VertexesIterable, wr io.Writer, styleFunc DotNo
 	if styleFunc==nil {
 		styleFunc = SimpleNodeStyle
 	}
-	for node := range nodesIter.VertexesIter() {
+	for node := range VertexesSeqOf(nodesIter) {
 		wr.Write([]byte("n" + node.String() + styleMapToString(styleFunc(node)) + ";\n"))
 	}
 }
@@ -96,7 +101,7 @@ func PlotConnectionsToDot(connIter TypedConnectionsIterable, separator string, w
 	if styleFunc==nil {
 		styleFunc = SimpleConnectionStyle
 	}
-	for conn := range connIter.TypedConnectionsIter() {
+	for conn := range TypedConnectionsSeqOf(connIter) {
 		wr.Write([]byte(fmt.Sprintf("n%v" + separator + "n%v%v;\n", 
 			conn.Tail.String(),
 			conn.Head.String(),
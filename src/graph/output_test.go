@@ -4,12 +4,9 @@ package graph
 import (
 	"testing"
 	"fmt"
-
-	"github.com/orfjackal/gospec/src/gospec"
-	// . "github.com/orfjackal/gospec/src/gospec"
 )
 
-func PlotDirectedGraphToDotSpec(c gospec.Context) {
+func TestPlotDirectedGraphToDot(t *testing.T) {
 	gr := NewDirectedMap()
 	gr.AddArc(1, 2)
 	gr.AddArc(2, 3)
@@ -23,10 +20,4 @@ func PlotDirectedGraphToDotSpec(c gospec.Context) {
 	PlotDirectedGraphToDot(gr, writer, SimpleNodeStyle, SimpleArcStyle)
 	fmt.Println(writer.Str)
 }
-
-func TestOutput(t *testing.T) {
-	r := gospec.NewRunner()
-	r.AddSpec(PlotDirectedGraphToDotSpec)
-	gospec.MainGoTest(r, t)
-}
 */
@@ -0,0 +1,213 @@
+package graph
+
+import (
+	"iter"
+)
+
+// Strongly connected components with Tarjan algorithm
+//
+// Algorithm is iterative, so it's safe for deep graphs. Components are
+// returned in reverse topological order: there are no arcs from component to
+// any of the next ones.
+func TarjanSCC(gr DirectedGraphReader) []Vertexes {
+	return tarjanSCC(NewDgraphOutNeighboursExtractor(gr), CollectVertexes(gr))
+}
+
+// Strongly connected components of mixed graph with Tarjan algorithm
+//
+// Edges are treated as pairs of opposite arcs, so their vertexes always
+// belong to the same component. See TarjanSCC for details.
+func TarjanSCC_mixed(gr MixedGraphReader) []Vertexes {
+	return tarjanSCC(NewMgraphOutNeighboursExtractor(gr), CollectVertexes(gr))
+}
+
+// Strongly connected components with Kosaraju algorithm
+//
+// Components are returned in topological order: there are no arcs from
+// component to any of the previous ones.
+func KosarajuSCC(gr DirectedGraphReader) []Vertexes {
+	return kosarajuSCC(NewDgraphNeighboursExtractor(gr), CollectVertexes(gr))
+}
+
+// Strongly connected components of mixed graph with Kosaraju algorithm
+//
+// Edges are treated as pairs of opposite arcs. See KosarajuSCC for details.
+func KosarajuSCC_mixed(gr MixedGraphReader) []Vertexes {
+	return kosarajuSCC(NewMgraphNeighboursExtractor(gr), CollectVertexes(gr))
+}
+
+// Tarjan algorithm stack frame.
+type tarjanFrame struct {
+	node VertexId
+	neighbours Vertexes
+	pos int
+}
+
+func tarjanSCC(gr OutNeighboursExtractor, vertexes Vertexes) []Vertexes {
+	index := make(map[VertexId]int, len(vertexes))
+	lowLink := make(map[VertexId]int, len(vertexes))
+	onStack := make(map[VertexId]bool)
+	stack := make(Vertexes, 0)
+	frames := make([]tarjanFrame, 0)
+	components := make([]Vertexes, 0)
+	
+	visit := func(node VertexId) {
+		index[node] = len(index)
+		lowLink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+		frames = append(frames, tarjanFrame{node: node, neighbours: CollectVertexes(gr.GetOutNeighbours(node))})
+	}
+	
+	for _, root := range vertexes {
+		if _, ok := index[root]; ok {
+			continue
+		}
+		visit(root)
+		for len(frames)>0 {
+			frame := &frames[len(frames)-1]
+			if frame.pos<len(frame.neighbours) {
+				next := frame.neighbours[frame.pos]
+				frame.pos++
+				if _, ok := index[next]; !ok {
+					visit(next)
+				} else if onStack[next] && index[next]<lowLink[frame.node] {
+					lowLink[frame.node] = index[next]
+				}
+				continue
+			}
+			
+			// all accessors are visited
+			node := frame.node
+			frames = frames[:len(frames)-1]
+			if len(frames)>0 {
+				parent := frames[len(frames)-1].node
+				if lowLink[node]<lowLink[parent] {
+					lowLink[parent] = lowLink[node]
+				}
+			}
+			if lowLink[node]==index[node] {
+				// node is the root of component
+				i := len(stack)-1
+				for stack[i]!=node {
+					i--
+				}
+				component := make(Vertexes, len(stack)-i)
+				copy(component, stack[i:])
+				for _, vertex := range component {
+					onStack[vertex] = false
+				}
+				stack = stack[:i]
+				components = append(components, component)
+			}
+		}
+	}
+	return components
+}
+
+func kosarajuSCC(gr NeighboursExtractor, vertexes Vertexes) []Vertexes {
+	// vertexes in reverse finishing order
+	order := make(Vertexes, len(vertexes))
+	pos := len(order)
+	DepthFirst(gr, vertexes, Visitor{
+		FinishVertex: func(node VertexId) bool {
+			pos--
+			order[pos] = node
+			return true
+		},
+	})
+	
+	components := make([]Vertexes, 0)
+	reversed := &inAsOutExtractor{gr}
+	DepthFirst(reversed, order, Visitor{
+		StartVertex: func(node VertexId) bool {
+			components = append(components, make(Vertexes, 0))
+			return true
+		},
+		DiscoverVertex: func(node VertexId) bool {
+			components[len(components)-1] = append(components[len(components)-1], node)
+			return true
+		},
+	})
+	return components
+}
+
+// Traverse graph against connections direction.
+type inAsOutExtractor struct {
+	gr InNeighboursExtractor
+}
+
+func (e *inAsOutExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	return e.gr.GetInNeighbours(node)
+}
+
+// Condensation of graph
+//
+// Each strongly connected component is replaced with single vertex, which id
+// is component index. Components are numbered in topological order, so
+// condensed graph is acyclic and arcs go from smaller ids to greater ones.
+type CondensedGraph struct {
+	DirectedGraph
+	directedGraphSeqs
+	components []Vertexes
+	componentOf map[VertexId]VertexId
+}
+
+// Vertexes of component
+func (g *CondensedGraph) Vertexes(component VertexId) Vertexes {
+	return g.components[component]
+}
+
+// All components in topological order
+func (g *CondensedGraph) Components() []Vertexes {
+	return g.components
+}
+
+// Component of original graph vertex
+//
+// ok is false if vertex doesn't belong to original graph.
+func (g *CondensedGraph) Component(node VertexId) (component VertexId, ok bool) {
+	component, ok = g.componentOf[node]
+	return
+}
+
+// Build condensation of directed graph
+func Condensation(gr DirectedGraphReader) *CondensedGraph {
+	return newCondensedGraph(TarjanSCC(gr), ArcsSeqOf(gr))
+}
+
+// Build condensation of mixed graph
+//
+// Edges are treated as pairs of opposite arcs, so they are always inside
+// components.
+func Condensation_mixed(gr MixedGraphReader) *CondensedGraph {
+	return newCondensedGraph(TarjanSCC_mixed(gr), ArcsSeqOf(gr))
+}
+
+// Build condensed graph from components in reverse topological order
+func newCondensedGraph(components []Vertexes, arcs iter.Seq[Connection]) *CondensedGraph {
+	// reverse to topological order
+	for i, j := 0, len(components)-1; i<j; i, j = i+1, j-1 {
+		components[i], components[j] = components[j], components[i]
+	}
+	gr := NewDirectedMap()
+	g := &CondensedGraph{
+		DirectedGraph: gr,
+		directedGraphSeqs: directedGraphSeqs{gr},
+		components: components,
+		componentOf: make(map[VertexId]VertexId),
+	}
+	for i, component := range components {
+		g.AddNode(VertexId(i))
+		for _, vertex := range component {
+			g.componentOf[vertex] = VertexId(i)
+		}
+	}
+	for arc := range arcs {
+		tail, head := g.componentOf[arc.Tail], g.componentOf[arc.Head]
+		if tail!=head && !g.CheckArc(tail, head) {
+			g.AddArc(tail, head)
+		}
+	}
+	return g
+}
@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// Components as sorted string to compare results of different algorithms.
+func componentsKey(components []Vertexes) string {
+	keys := make([]string, 0, len(components))
+	for _, component := range components {
+		keys = append(keys, fmt.Sprint(sortedVertexes(component)))
+	}
+	sort.Strings(keys)
+	return fmt.Sprint(keys)
+}
+
+// Check that there are no arcs from component to any of previous ones
+func expectTopologicalComponents(t *testing.T, gr DirectedGraphArcsReader, components []Vertexes) {
+	t.Helper()
+	position := make(map[VertexId]int)
+	for i, component := range components {
+		for _, vertex := range component {
+			position[vertex] = i
+		}
+	}
+	for arc := range ArcsSeqOf(gr) {
+		expectTrue(t, position[arc.Tail]<=position[arc.Head], "arc goes forward")
+	}
+}
+
+func reversedComponents(components []Vertexes) []Vertexes {
+	res := make([]Vertexes, len(components))
+	for i, component := range components {
+		res[len(components)-1-i] = component
+	}
+	return res
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "1>2>3>1")
+	ReadDgraphLine(gr, "3>4>5>6>4")
+	ReadDgraphLine(gr, "7>7")
+	gr.AddArc(2, 8)
+	expected := "[[1 2 3] [4 5 6] [7] [8]]"
+
+	t.Run("tarjan", func(t *testing.T) {
+		components := TarjanSCC(gr)
+		expectEquals(t, componentsKey(components), expected)
+		expectTopologicalComponents(t, gr, reversedComponents(components))
+	})
+
+	t.Run("kosaraju", func(t *testing.T) {
+		components := KosarajuSCC(gr)
+		expectEquals(t, componentsKey(components), expected)
+		expectTopologicalComponents(t, gr, components)
+	})
+
+	t.Run("mixed graph", func(t *testing.T) {
+		mgr := NewMixedMap()
+		ReadMgraphLine(mgr, "1>2>3-4>5")
+		ReadMgraphLine(mgr, "5>3")
+		mgr.AddNode(6)
+		expected := "[[1] [2] [3 4 5] [6]]"
+		expectEquals(t, componentsKey(TarjanSCC_mixed(mgr)), expected)
+		expectEquals(t, componentsKey(KosarajuSCC_mixed(mgr)), expected)
+
+		condensed := Condensation_mixed(mgr)
+		expectEquals(t, condensed.Order(), 4)
+		component, _ := condensed.Component(4)
+		expectVertexesExactly(t, condensed.Vertexes(component), 3, 4, 5)
+		expectEquals(t, condensed.ArcsCnt(), 2)
+	})
+
+	t.Run("random graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		size := 50
+		for i:=0; i<30; i++ {
+			gr := NewDirectedMap()
+			for node:=0; node<size; node++ {
+				gr.AddNode(VertexId(node))
+			}
+			for j:=0; j<size+i*2; j++ {
+				tail, head := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+				if !gr.CheckArc(tail, head) {
+					gr.AddArc(tail, head)
+				}
+			}
+			tarjan := TarjanSCC(gr)
+			kosaraju := KosarajuSCC(gr)
+			expectEquals(t, componentsKey(tarjan), componentsKey(kosaraju))
+			expectTopologicalComponents(t, gr, reversedComponents(tarjan))
+			expectTopologicalComponents(t, gr, kosaraju)
+			// vertexes are in the same component iff they are mutually reachable
+			marks := make(map[VertexId]PathMarks)
+			for node:=0; node<size; node++ {
+				marks[VertexId(node)] = DijkstraSingleSource(NewDgraphOutNeighboursExtractor(gr), VertexId(node), SimpleWeightFunc)
+			}
+			for _, component := range tarjan {
+				for _, vertex := range component {
+					for _, other := range component {
+						_, ok := marks[vertex][other]
+						expectTrue(t, ok, "vertexes in component are reachable")
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("deep graph", func(t *testing.T) {
+		gr := NewDirectedMap()
+		depth := 100000
+		for i:=0; i<depth; i++ {
+			gr.AddArc(VertexId(i), VertexId(i+1))
+		}
+		gr.AddArc(VertexId(depth), 0)
+		expectEquals(t, len(TarjanSCC(gr)), 1)
+		expectEquals(t, len(KosarajuSCC(gr)), 1)
+	})
+}
+
+func TestCondensation(t *testing.T) {
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "1>2>3>1")
+	ReadDgraphLine(gr, "3>4>5>4")
+	ReadDgraphLine(gr, "2>5")
+	gr.AddArc(6, 1)
+
+	condensed := Condensation(gr)
+	expectEquals(t, condensed.Order(), 3)
+	expectEquals(t, condensed.ArcsCnt(), 2)
+	expectEquals(t, len(condensed.Components()), 3)
+
+	c1, ok := condensed.Component(1)
+	expectTrue(t, ok, "vertex has component")
+	c4, _ := condensed.Component(4)
+	c6, _ := condensed.Component(6)
+	expectVertexesExactly(t, condensed.Vertexes(c1), 1, 2, 3)
+	expectVertexesExactly(t, condensed.Vertexes(c4), 4, 5)
+	expectTrue(t, condensed.CheckArc(c1, c4), "arc between components")
+	expectTrue(t, condensed.CheckArc(c6, c1), "arc between components")
+	_, ok = condensed.Component(100)
+	expectFalse(t, ok, "unknown vertex has component")
+
+	nodes, hasCycles := TopologicalSort(condensed)
+	expectFalse(t, hasCycles, "condensed graph has cycles")
+	expectPath(t, nodes, 0, 1, 2)
+	expectPath(t, []VertexId{c6, c1, c4}, 0, 1, 2)
+}
@@ -1,12 +1,16 @@
 package graph
 
 import (
+	"context"
+	"iter"
 	"math"
 
-	"github.com/StepLg/go-erx/src/erx"
+	"github.com/StepLg/go-graph/src/erx"
 )
 
 // Path mark, set by some of search algorithms.
+//
+// Source vertexes are marked with PrevVertex equal to themselves.
 type VertexPathMark struct {
 	Weight float64 // Weight from one of source nodes to current node.
 	PrevVertex VertexId // Previous node in path.
@@ -18,14 +22,38 @@ type VertexPathMark struct {
 // To get real path from this marks map use PathFromMarks function. 
 type PathMarks map[VertexId]*VertexPathMark
 
-type ConnectionWeightFunc func(head, tail VertexId) float64
+// Weight of connection from tail to head.
+type ConnectionWeightFunc func(tail, head VertexId) float64
 
 type StopFunc func(node VertexId, sumWeight float64) bool
 
-func SimpleWeightFunc(head, tail VertexId) float64 {
+func SimpleWeightFunc(tail, head VertexId) float64 {
 	return float64(1.0)
 }
 
+// Weight function, which takes arcs weights from weighted graph.
+func ArcWeightFunc(gr WeightedDirectedGraphArcsReader) ConnectionWeightFunc {
+	return func(tail, head VertexId) float64 {
+		return gr.GetArcWeight(tail, head)
+	}
+}
+
+// Weight function, which takes edges weights from weighted graph.
+func EdgeWeightFunc(gr WeightedUndirectedGraphEdgesReader) ConnectionWeightFunc {
+	return func(tail, head VertexId) float64 {
+		return gr.GetEdgeWeight(tail, head)
+	}
+}
+
+// Weight function, which takes arcs and edges weights from weighted mixed graph.
+//
+// Could be used with NewMgraphOutNeighboursExtractor.
+func MixedWeightFunc(gr WeightedMixedGraphSpecificReader) ConnectionWeightFunc {
+	return func(tail, head VertexId) float64 {
+		return gr.GetConnectionWeight(tail, head)
+	}
+}
+
 // Generic check path algorithm for all graph types
 // 
 // Checking path between from and to nodes, using getNeighbours function
@@ -37,46 +65,60 @@ func SimpleWeightFunc(head, tail VertexId) float64 {
 // 
 // As a result CheckPathDijkstra returns total weight of path, if it exists.
 func CheckPathDijkstra(neighboursExtractor OutNeighboursExtractor, from, to VertexId, stopFunc StopFunc, weightFunction ConnectionWeightFunc) (float64, bool) {
+	weight, pathExists, err := TryCheckPathDijkstra(neighboursExtractor, from, to, stopFunc, weightFunction)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Check path graph with Dijkstra algorithm", err, 1)
+		erxErr.AddV("from", from)
+		erxErr.AddV("to", to)
+		panic(erxErr)
+	}
+	return weight, pathExists
+}
+
+// Error-returning version of CheckPathDijkstra.
+//
+// Returns *ConnectionError with ErrNegativeWeight if weightFunction returns
+// negative value for any checked connection. Panics from neighboursExtractor
+// are returned as errors too.
+func TryCheckPathDijkstra(neighboursExtractor OutNeighboursExtractor, from, to VertexId, stopFunc StopFunc, weightFunction ConnectionWeightFunc) (weight float64, pathExists bool, err error) {
 	defer func() {
 		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Check path graph with Dijkstra algorithm", e)
-			err.AddV("from", from)
-			err.AddV("to", to)
-			panic(err)
+			weight, pathExists, err = -1.0, false, recoveredToError(e)
 		}
 	}()
 	
 	if from==to {
-		return 0.0, true
+		return 0.0, true, nil
 	}
 	
-	q := newPriorityQueueSimple(10)
-	q.Add(from, 0.0)
+	q := NewBinaryHeap(10)
+	q.Push(from, 0.0)
+	visited := newVertexesMarks[bool](neighboursExtractor)
 	
 	for !q.Empty() {
-		curNode, curWeight := q.Next()
-		curWeight = -curWeight // because we inverse weight in priority queue
+		curNode, curWeight := q.Pop()
+		if curNode==to {
+			// path weight is final only when node is taken from queue
+			return curWeight, true, nil
+		}
+		visited.set(curNode, true)
 	
-		for nextNode := range neighboursExtractor.GetOutNeighbours(curNode).VertexesIter() {
+		for nextNode := range VertexesSeqOf(neighboursExtractor.GetOutNeighbours(curNode)) {
 			arcWeight := weightFunction(curNode, nextNode)
 			if arcWeight < 0 {
-				err := erx.NewError("Negative weight detected")
-				err.AddV("head", curNode)
-				err.AddV("tail", nextNode)
-				err.AddV("weight", arcWeight)
-				panic(err)
+				return -1.0, false, &ConnectionError{Op: "check path", Tail: curNode, Head: nextNode, Err: ErrNegativeWeight}
 			}
-			nextWeight := curWeight + arcWeight
-			if nextNode==to {
-				return nextWeight, true
+			if visited.has(nextNode) {
+				continue
 			}
+			nextWeight := curWeight + arcWeight
 			if stopFunc==nil || !stopFunc(nextNode, nextWeight) {
-				q.Add(nextNode, -nextWeight)
+				PushOrDecreaseKey(q, nextNode, nextWeight)
 			}
 		}
 	}
 	
-	return -1.0, false
+	return -1.0, false, nil
 }
 
 type CheckDirectedPath func(gr DirectedGraphArcsReader, from, to VertexId, stopFunc StopFunc, weightFunction ConnectionWeightFunc) bool
@@ -103,47 +145,98 @@ func CheckMixedPathDijkstra(gr MixedGraphConnectionsReader, from, to VertexId, s
 // Get all paths from one node to another
 //
 // This algorithms doesn't take any loops into paths.
+//
+// Paths are searched in separate goroutine, which exits only after all paths
+// are read from channel. Use GetAllPathsContext to stop it earlier.
 func GetAllPaths(neighboursExtractor OutNeighboursExtractor, from, to VertexId) <-chan []VertexId {
-	curPath := make([]VertexId, 10)
-	nodesStatus := make(map[VertexId]bool)
-	ch := make(chan []VertexId)
-	go getAllPaths_helper(neighboursExtractor, from, to, curPath, 0, nodesStatus, ch, true)
-	return ch
+	return GetAllPathsContext(context.Background(), neighboursExtractor, from, to, PathsOptions{})
 }
 
-func getAllPaths_helper(neighboursExtractor OutNeighboursExtractor, from, to VertexId, curPath []VertexId, pathPos int, nodesStatus map[VertexId]bool, ch chan []VertexId, closeChannel bool) {
-	if _, ok := nodesStatus[from]; ok {
-		return
-	}
-	if pathPos==len(curPath) {
-		// reallocate curPath slice to add new elements
-		tmp := make([]VertexId, 2*pathPos)
-		copy(tmp, curPath)
-		curPath = tmp
-	}
-	
-	curPath[pathPos] = from
+// Limits of paths enumeration.
+type PathsOptions struct {
+	MaxLength int // maximum number of connections in path, 0 for unlimited
+	MaxCount int // maximum number of paths, 0 for unlimited
+	// Optional predicate: is it allowed to go from the last vertex of path
+	// to next vertex. path is reused, so it mustn't be stored.
+	Step func(path []VertexId, next VertexId) bool
+}
 
-	if from==to { 
-		if pathPos>0 {
-			pathCopy := make([]VertexId, pathPos+1)
-			copy(pathCopy, curPath[0:pathPos+1])
-			ch <- pathCopy
+// Get all paths from one node to another with cancellation and limits
+//
+// Channel is closed when all paths are found, limits are reached or ctx is
+// done. In the last case goroutine exits as soon as possible, even if the
+// consumer stopped reading.
+func GetAllPathsContext(ctx context.Context, neighboursExtractor OutNeighboursExtractor, from, to VertexId, options PathsOptions) <-chan []VertexId {
+	userStep := options.Step
+	options.Step = func(path []VertexId, next VertexId) bool {
+		if ctx.Err()!=nil {
+			return false
 		}
-		return
+		return userStep==nil || userStep(path, next)
 	}
-	nodesStatus[from] = true
 	
-	for nextNode := range neighboursExtractor.GetOutNeighbours(from).VertexesIter() {
-		getAllPaths_helper(neighboursExtractor, nextNode, to, curPath, pathPos+1, nodesStatus, ch, false)
-	}
-	
-	nodesStatus[from] = false, false
-	
-	if closeChannel {
-		close(ch)
+	ch := make(chan []VertexId)
+	go func() {
+		defer close(ch)
+		for path := range AllPathsSeq(neighboursExtractor, from, to, options) {
+			select {
+				case ch <- path:
+				case <-ctx.Done():
+					return
+			}
+		}
+	}()
+	return ch
+}
+
+// Iterate all paths from one node to another
+//
+// Paths don't contain loops. Search is stopped when the consumer breaks the
+// loop or options limits are reached. Each path is a new slice.
+func AllPathsSeq(neighboursExtractor OutNeighboursExtractor, from, to VertexId, options PathsOptions) iter.Seq[[]VertexId] {
+	return func(yield func([]VertexId) bool) {
+		curPath := make([]VertexId, 0, 10)
+		onPath := make(map[VertexId]bool)
+		pathsCnt := 0
+		
+		// returns false if search must be stopped
+		var step func(node VertexId) bool
+		step = func(node VertexId) bool {
+			curPath = append(curPath, node)
+			defer func() {
+				curPath = curPath[:len(curPath)-1]
+			}()
+			
+			if node==to {
+				if len(curPath)==1 {
+					return true
+				}
+				pathCopy := make([]VertexId, len(curPath))
+				copy(pathCopy, curPath)
+				pathsCnt++
+				return yield(pathCopy) && (options.MaxCount<=0 || pathsCnt<options.MaxCount)
+			}
+			if options.MaxLength>0 && len(curPath)>options.MaxLength {
+				return true
+			}
+			
+			onPath[node] = true
+			defer delete(onPath, node)
+			for nextNode := range VertexesSeqOf(neighboursExtractor.GetOutNeighbours(node)) {
+				if onPath[nextNode] {
+					continue
+				}
+				if options.Step!=nil && !options.Step(curPath, nextNode) {
+					continue
+				}
+				if !step(nextNode) {
+					return false
+				}
+			}
+			return true
+		}
+		step(from)
 	}
-	return
 }
 
 func GetAllDirectedPaths(gr DirectedGraphArcsReader, from, to VertexId) <-chan []VertexId {
@@ -158,20 +251,41 @@ func GetAllMixedPaths(gr MixedGraphConnectionsReader, from, to VertexId) <-chan
 	return GetAllPaths(NewMgraphOutNeighboursExtractor(gr), from, to)
 }
 
+func GetAllDirectedPathsContext(ctx context.Context, gr DirectedGraphArcsReader, from, to VertexId, options PathsOptions) <-chan []VertexId {
+	return GetAllPathsContext(ctx, NewDgraphOutNeighboursExtractor(gr), from, to, options)
+}
+
+func GetAllUndirectedPathsContext(ctx context.Context, gr UndirectedGraphEdgesReader, from, to VertexId, options PathsOptions) <-chan []VertexId {
+	return GetAllPathsContext(ctx, NewUgraphOutNeighboursExtractor(gr), from, to, options)
+}
+
+func GetAllMixedPathsContext(ctx context.Context, gr MixedGraphConnectionsReader, from, to VertexId, options PathsOptions) <-chan []VertexId {
+	return GetAllPathsContext(ctx, NewMgraphOutNeighboursExtractor(gr), from, to, options)
+}
+
 // Retrieving path from path marks.
 func PathFromMarks(marks PathMarks, destination VertexId) Vertexes {
-	defer func() {
-		if e:=recover(); e!=nil {
-			err := erx.NewSequent("Retrieving path from path marks.", e)
-			err.AddV("marks", marks)
-			err.AddV("destination", destination)
-			panic(err)
-		}
-	}()
+	path, err := TryPathFromMarks(marks, destination)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Retrieving path from path marks.", err, 1)
+		erxErr.AddV("marks", marks)
+		erxErr.AddV("destination", destination)
+		panic(erxErr)
+	}
+	return path
+}
+
+// Retrieving path from path marks.
+//
+// Path is followed back from destination until source vertex, which mark
+// points to itself. Returns nil path and nil error if there is no path to
+// destination and *VertexError with ErrPathMarkNotFound if marks are
+// inconsistent.
+func TryPathFromMarks(marks PathMarks, destination VertexId) (Vertexes, error) {
 	destInfo, ok := marks[destination]
 	if !ok || destInfo.Weight==math.MaxFloat64 {
 		// no path from any source to destination
-		return nil
+		return nil, nil
 	}
 	
 	curVertexInfo := destInfo
@@ -179,22 +293,24 @@ func PathFromMarks(marks PathMarks, destination VertexId) Vertexes {
 	curPathPos := 0
 	path[curPathPos] = destination
 	curPathPos++
-	for curVertexInfo.Weight > 0.0 {
+	for curVertexInfo.PrevVertex!=path[curPathPos-1] {
+		if curPathPos>len(marks) {
+			// marks contain a cycle
+			return nil, &VertexError{Op: "retrieve path", Node: destination, Err: ErrPathMarkNotFound}
+		}
 		if len(path)==curPathPos {
 			// reallocate memory for path
 			tmp := make(Vertexes, 2*curPathPos)
 			copy(tmp, path)
 			path = tmp
 		}
-		path[curPathPos] = curVertexInfo.PrevVertex
+		prevVertex := curVertexInfo.PrevVertex
+		path[curPathPos] = prevVertex
 		curPathPos++
 		var ok bool
-		curVertexInfo, ok = marks[curVertexInfo.PrevVertex]
+		curVertexInfo, ok = marks[prevVertex]
 		if !ok {
-			err := erx.NewError("Can't find path mark info for vertex in path.")
-			err.AddV("vertex", curVertexInfo.PrevVertex)
-			err.AddV("cur path", path)
-			panic(err)
+			return nil, &VertexError{Op: "retrieve path", Node: prevVertex, Err: ErrPathMarkNotFound}
 		}
 	}
 	
@@ -206,9 +322,104 @@ func PathFromMarks(marks PathMarks, destination VertexId) Vertexes {
 		path[i], path[pathLen-i-1] = path[pathLen-i-1], path[i]
 	}
 	
-	return path
+	return path, nil
+}
+
+
+// Compute multi-source shortest paths with Dijkstra algorithm
+//
+// Returns marks of all vertexes, reachable from sources. If targets are
+// given, search stops as soon as any of them is reached, and marks contain
+// only vertexes with final path weights (target included).
+//
+// All weights must be non-negative, otherwise function panics.
+func DijkstraMultiSource(gr OutNeighboursExtractor, sources Vertexes, weightFunc ConnectionWeightFunc, targets ...VertexId) PathMarks {
+	marks, err := TryDijkstraMultiSource(gr, sources, weightFunc, targets...)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Compute shortest paths with Dijkstra algorithm.", err, 1)
+		erxErr.AddV("sources", sources)
+		erxErr.AddV("targets", targets)
+		panic(erxErr)
+	}
+	return marks
 }
 
+// Error-returning version of DijkstraMultiSource.
+//
+// Returns *ConnectionError with ErrNegativeWeight if weightFunc returns
+// negative value for any checked connection. Panics from gr are returned as
+// errors too.
+func TryDijkstraMultiSource(gr OutNeighboursExtractor, sources Vertexes, weightFunc ConnectionWeightFunc, targets ...VertexId) (marks PathMarks, err error) {
+	defer func() {
+		if e:=recover(); e!=nil {
+			marks, err = nil, recoveredToError(e)
+		}
+	}()
+	
+	isTarget := make(map[VertexId]bool, len(targets))
+	for _, target := range targets {
+		isTarget[target] = true
+	}
+	
+	marks = make(PathMarks)
+	q := NewBinaryHeap(len(sources))
+	for _, source := range sources {
+		marks[source] = &VertexPathMark{Weight: 0.0, PrevVertex: source}
+		PushOrDecreaseKey(q, source, 0.0)
+	}
+	
+	settled := newVertexesMarks[bool](gr)
+	for !q.Empty() {
+		curNode, _ := q.Pop()
+		settled.set(curNode, true)
+		if isTarget[curNode] {
+			// removing vertexes with not final weights
+			for node, _ := range marks {
+				if !settled.has(node) {
+					delete(marks, node)
+				}
+			}
+			break
+		}
+		
+		curWeight := marks[curNode].Weight
+		for nextNode := range VertexesSeqOf(gr.GetOutNeighbours(curNode)) {
+			arcWeight := weightFunc(curNode, nextNode)
+			if arcWeight < 0 {
+				return nil, &ConnectionError{Op: "dijkstra", Tail: curNode, Head: nextNode, Err: ErrNegativeWeight}
+			}
+			if settled.has(nextNode) {
+				continue
+			}
+			nextWeight := curWeight + arcWeight
+			if mark, ok := marks[nextNode]; !ok || nextWeight < mark.Weight {
+				marks[nextNode] = &VertexPathMark{Weight: nextWeight, PrevVertex: curNode}
+				PushOrDecreaseKey(q, nextNode, nextWeight)
+			}
+		}
+	}
+	
+	return marks, nil
+}
+
+// Compute single-source shortest paths with Dijkstra algorithm
+//
+// See DijkstraMultiSource for details.
+func DijkstraSingleSource(gr OutNeighboursExtractor, source VertexId, weightFunc ConnectionWeightFunc, targets ...VertexId) PathMarks {
+	marks, err := TryDijkstraMultiSource(gr, Vertexes{source}, weightFunc, targets...)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Compute shortest paths with Dijkstra algorithm.", err, 1)
+		erxErr.AddV("source", source)
+		erxErr.AddV("targets", targets)
+		panic(erxErr)
+	}
+	return marks
+}
+
+// Error-returning version of DijkstraSingleSource.
+func TryDijkstraSingleSource(gr OutNeighboursExtractor, source VertexId, weightFunc ConnectionWeightFunc, targets ...VertexId) (PathMarks, error) {
+	return TryDijkstraMultiSource(gr, Vertexes{source}, weightFunc, targets...)
+}
 
 // Compute multi-source shortest paths with Bellman-Ford algorithm
 //
@@ -218,26 +429,40 @@ func PathFromMarks(marks PathMarks, destination VertexId) Vertexes {
 // Returns nil if there are negative cycles. 
 func BellmanFordMultiSource(gr DirectedGraphReader, sources Vertexes, weightFunc ConnectionWeightFunc) PathMarks {
 	marks := make(PathMarks)
-	for vertex := range gr.VertexesIter() {
+	for vertex := range VertexesSeqOf(gr) {
 		marks[vertex] = &VertexPathMark{Weight: math.MaxFloat64, PrevVertex: 0}
 	}
 	
 	for _, vertex := range sources {
 		marks[vertex].Weight = 0.0
+		marks[vertex].PrevVertex = vertex
 	}
 	
 	nodesCnt := gr.Order()
 	for i:=0; i<nodesCnt; i++ {
-		for conn := range gr.ArcsIter() {
+		relaxed := false
+		for conn := range ArcsSeqOf(gr) {
+			if marks[conn.Tail].Weight==math.MaxFloat64 {
+				// vertex isn't reachable yet
+				continue
+			}
 			possibleWeight := marks[conn.Tail].Weight + weightFunc(conn.Tail, conn.Head)
 			if marks[conn.Head].Weight > possibleWeight {
 				marks[conn.Head].PrevVertex = conn.Tail
 				marks[conn.Head].Weight = possibleWeight
+				relaxed = true
 			}
 		}
+		if !relaxed {
+			// all weights are final
+			return marks
+		}
 	}
 	
-	for conn := range gr.ArcsIter() {
+	for conn := range ArcsSeqOf(gr) {
+		if marks[conn.Tail].Weight==math.MaxFloat64 {
+			continue
+		}
 		if marks[conn.Head].Weight > marks[conn.Tail].Weight + weightFunc(conn.Tail, conn.Head) {
 			return nil
 		}
@@ -255,36 +480,124 @@ func BellmanFordSingleSource(gr DirectedGraphReader, source VertexId, weightFunc
 // Returs map, contains all accessiable vertexes from sources vertexes with
 // minimal path weight.
 //
-// Returns nil if there are negative cycles. 
+// Returns nil if there are negative cycles. Use SPFAMultiSource to get the
+// cycle itself.
 func BellmanFordLightMultiSource(gr OutNeighboursExtractor, sources Vertexes, weightFunc ConnectionWeightFunc) PathMarks {
+	marks, negativeCycle := SPFAMultiSource(gr, sources, weightFunc)
+	if negativeCycle!=nil {
+		return nil
+	}
+	return marks
+}
+
+func BellmanFordLightSingleSource(gr OutNeighboursExtractor, source VertexId, weightFunc ConnectionWeightFunc) PathMarks {
+	return BellmanFordLightMultiSource(gr, Vertexes{source}, weightFunc)
+}
+
+// Compute multi-source shortest paths with queue-based Bellman-Ford algorithm (SPFA)
+//
+// Only vertexes, which weights were changed on previous pass, are checked on
+// the next one, and search stops as soon as there are no such vertexes.
+// Negative weights are allowed.
+//
+// Returns marks of all vertexes, reachable from sources, and nil. If there is
+// negative cycle, reachable from sources, returns nil marks and the cycle:
+// cycle[i]->cycle[i+1] are graph connections, and so is last->first.
+func SPFAMultiSource(gr OutNeighboursExtractor, sources Vertexes, weightFunc ConnectionWeightFunc) (PathMarks, Vertexes) {
 	marks := make(PathMarks)
+	queue := make(Vertexes, 0, len(sources))
+	inQueue := make(map[VertexId]bool)
 	for _, vertex := range sources {
-		marks[vertex] = &VertexPathMark{Weight: 0.0, PrevVertex: 0}
+		if inQueue[vertex] {
+			continue
+		}
+		marks[vertex] = &VertexPathMark{Weight: 0.0, PrevVertex: vertex}
+		queue = append(queue, vertex)
+		inQueue[vertex] = true
 	}
 	
-	for i:=0; i<len(marks); i++ {
-		for vertex, vertexInfo := range marks {
-			for nextVertex := range gr.GetOutNeighbours(vertex).VertexesIter() {
-				possibleWeight := vertexInfo.Weight + weightFunc(vertex, nextVertex)
-				if nextVertexInfo, ok := marks[nextVertex]; !ok || nextVertexInfo.Weight > possibleWeight {
-					marks[vertex] = &VertexPathMark{Weight: possibleWeight, PrevVertex: vertex}
-				}
+	// Pass is processing of all vertexes, queued during previous pass. Without
+	// negative cycles there are no more passes than reached vertexes, because
+	// after k-th pass weights of all paths with k connections are final. So
+	// after that marks are checked for cycles, which are always negative.
+	for pass:=0; len(queue)>0; pass++ {
+		if pass>=len(marks) {
+			if cycle := cycleFromMarks(marks); cycle!=nil {
+				return nil, cycle
 			}
 		}
-	}
-	
-	// checking for negative cycles
-	for vertex, vertexInfo := range marks {
-		for nextVertex := range gr.GetOutNeighbours(vertex).VertexesIter() {
-			if marks[nextVertex].Weight > vertexInfo.Weight + weightFunc(vertex, nextVertex) {
-				return nil
+		nextQueue := make(Vertexes, 0)
+		for _, vertex := range queue {
+			inQueue[vertex] = false
+		}
+		for _, vertex := range queue {
+			vertexWeight := marks[vertex].Weight
+			for nextVertex := range VertexesSeqOf(gr.GetOutNeighbours(vertex)) {
+				possibleWeight := vertexWeight + weightFunc(vertex, nextVertex)
+				if nextVertexInfo, ok := marks[nextVertex]; ok && nextVertexInfo.Weight <= possibleWeight {
+					continue
+				}
+				if nextVertex==vertex {
+					// negative loop can't be stored in marks, because
+					// self-referencing marks are sources
+					return nil, Vertexes{vertex}
+				}
+				marks[nextVertex] = &VertexPathMark{Weight: possibleWeight, PrevVertex: vertex}
+				if !inQueue[nextVertex] {
+					inQueue[nextVertex] = true
+					nextQueue = append(nextQueue, nextVertex)
+				}
 			}
 		}
+		queue = nextQueue
 	}
 	
-	return marks
+	return marks, nil
 }
 
-func BellmanFordLightSingleSource(gr OutNeighboursExtractor, source VertexId, weightFunc ConnectionWeightFunc) PathMarks {
-	return BellmanFordLightMultiSource(gr, Vertexes{source}, weightFunc)
+// Compute single-source shortest paths with queue-based Bellman-Ford algorithm
+//
+// See SPFAMultiSource for details.
+func SPFASingleSource(gr OutNeighboursExtractor, source VertexId, weightFunc ConnectionWeightFunc) (PathMarks, Vertexes) {
+	return SPFAMultiSource(gr, Vertexes{source}, weightFunc)
+}
+
+// Find cycle in marks
+//
+// Returns cycle in connections direction or nil if marks are acyclic.
+func cycleFromMarks(marks PathMarks) Vertexes {
+	const (
+		onWalk = 1
+		checked = 2
+	)
+	state := make(map[VertexId]int, len(marks))
+	walk := make(Vertexes, 0)
+	for vertex, _ := range marks {
+		walk = walk[:0]
+		cur := vertex
+		for state[cur]==0 {
+			state[cur] = onWalk
+			walk = append(walk, cur)
+			if marks[cur].PrevVertex==cur {
+				break
+			}
+			cur = marks[cur].PrevVertex
+		}
+		if state[cur]==onWalk && marks[cur].PrevVertex!=cur {
+			// walk returned to itself: cur is on the cycle
+			cycle := Vertexes{cur}
+			for prev:=marks[cur].PrevVertex; prev!=cur; prev=marks[prev].PrevVertex {
+				cycle = append(cycle, prev)
+			}
+			// cycle is collected against connections direction
+			for i, j := 0, len(cycle)-1; i<j; i, j = i+1, j-1 {
+				cycle[i], cycle[j] = cycle[j], cycle[i]
+			}
+			return cycle
+		}
+		for _, node := range walk {
+			state[node] = checked
+		}
+	}
+	return nil
 }
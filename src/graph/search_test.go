@@ -1,140 +1,373 @@
 package graph
 
 import (
+	"context"
+	"math"
+	"math/rand"
 	"testing"
-	"github.com/orfjackal/gospec/src/gospec"
-	. "github.com/orfjackal/gospec/src/gospec"
+	"time"
 )
 
-func CheckDirectedPathSpec(c gospec.Context, checkPathFunction CheckDirectedPath) {
+func CheckDirectedPathSpec(t *testing.T, checkPathFunction CheckDirectedPath) {
 	gr := generateDirectedGraph1()
-	
-	c.Specify("Check path to self", func() {
-		c.Expect(checkPathFunction(gr, 1, 1, nil, SimpleWeightFunc), IsTrue) 
-		c.Expect(checkPathFunction(gr, 6, 6, nil, SimpleWeightFunc), IsTrue)
-	})
-	
-	c.Specify("Check neighbours path", func() {
-		c.Expect(checkPathFunction(gr, 1, 2, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 2, 4, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 1, 6, nil, SimpleWeightFunc), IsTrue)
-	})
-	
-	c.Specify("Check reversed neighbours", func() {
-		c.Expect(checkPathFunction(gr, 6, 1, nil, SimpleWeightFunc), IsFalse)
-		c.Expect(checkPathFunction(gr, 4, 3, nil, SimpleWeightFunc), IsFalse)
-		c.Expect(checkPathFunction(gr, 5, 4, nil, SimpleWeightFunc), IsFalse)
-	})
-	
-	c.Specify("Check long path", func() {
-		c.Expect(checkPathFunction(gr, 1, 6, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 1, 5, nil, SimpleWeightFunc), IsTrue)
-	})
-	
-	c.Specify("Check weight limit", func() {
-		c.Expect(checkPathFunction(gr, 1, 5, func(node VertexId, weight float64) bool {
+
+	t.Run("Check path to self", func(t *testing.T) {
+		expectTrue(t, checkPathFunction(gr, 1, 1, nil, SimpleWeightFunc), "1->1")
+		expectTrue(t, checkPathFunction(gr, 6, 6, nil, SimpleWeightFunc), "6->6")
+	})
+
+	t.Run("Check neighbours path", func(t *testing.T) {
+		expectTrue(t, checkPathFunction(gr, 1, 2, nil, SimpleWeightFunc), "1->2")
+		expectTrue(t, checkPathFunction(gr, 2, 4, nil, SimpleWeightFunc), "2->4")
+		expectTrue(t, checkPathFunction(gr, 1, 6, nil, SimpleWeightFunc), "1->6")
+	})
+
+	t.Run("Check reversed neighbours", func(t *testing.T) {
+		expectFalse(t, checkPathFunction(gr, 6, 1, nil, SimpleWeightFunc), "6->1")
+		expectFalse(t, checkPathFunction(gr, 4, 3, nil, SimpleWeightFunc), "4->3")
+		expectFalse(t, checkPathFunction(gr, 5, 4, nil, SimpleWeightFunc), "5->4")
+	})
+
+	t.Run("Check long path", func(t *testing.T) {
+		expectTrue(t, checkPathFunction(gr, 1, 6, nil, SimpleWeightFunc), "1->6")
+		expectTrue(t, checkPathFunction(gr, 1, 5, nil, SimpleWeightFunc), "1->5")
+	})
+
+	t.Run("Check weight limit", func(t *testing.T) {
+		expectFalse(t, checkPathFunction(gr, 1, 5, func(node VertexId, weight float64) bool {
 			return weight < 2.0
-		}, SimpleWeightFunc), IsFalse)
+		}, SimpleWeightFunc), "1->5 with weight limit")
 	})
 }
 
-func CheckMixedPathSpec(c gospec.Context, checkPathFunction CheckMixedPath) {
+func CheckMixedPathSpec(t *testing.T, checkPathFunction CheckMixedPath) {
 	gr := generateMixedGraph1()
-	
-	c.Specify("Check path to self", func() {
-		c.Expect(checkPathFunction(gr, 1, 1, nil, SimpleWeightFunc), IsTrue) 
-		c.Expect(checkPathFunction(gr, 6, 6, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 4, 4, nil, SimpleWeightFunc), IsTrue)
-	})
-	
-	c.Specify("Check directed neighbours path", func() {
-		c.Expect(checkPathFunction(gr, 1, 2, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 2, 4, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 1, 6, nil, SimpleWeightFunc), IsTrue)
-	})
-
-	c.Specify("Check undirected neighbours path", func() {
-		c.Expect(checkPathFunction(gr, 4, 6, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 6, 4, nil, SimpleWeightFunc), IsTrue)
-	})
-		
-	c.Specify("Check reversed directed neighbours", func() {
-		c.Expect(checkPathFunction(gr, 6, 1, nil, SimpleWeightFunc), IsFalse)
-		c.Expect(checkPathFunction(gr, 4, 3, nil, SimpleWeightFunc), IsFalse)
-		c.Expect(checkPathFunction(gr, 5, 4, nil, SimpleWeightFunc), IsFalse)
-	})
-	
-	c.Specify("Check long path", func() {
-		c.Expect(checkPathFunction(gr, 1, 6, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 1, 5, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 6, 5, nil, SimpleWeightFunc), IsTrue)
-		c.Expect(checkPathFunction(gr, 3, 6, nil, SimpleWeightFunc), IsTrue)
-		
-		c.Expect(checkPathFunction(gr, 6, 3, nil, SimpleWeightFunc), IsFalse)
-	})
-	
-	c.Specify("Check weight limit", func() {
-		c.Expect(checkPathFunction(gr, 1, 5, func(node VertexId, weight float64) bool {
+
+	t.Run("Check path to self", func(t *testing.T) {
+		expectTrue(t, checkPathFunction(gr, 1, 1, nil, SimpleWeightFunc), "1->1")
+		expectTrue(t, checkPathFunction(gr, 6, 6, nil, SimpleWeightFunc), "6->6")
+		expectTrue(t, checkPathFunction(gr, 4, 4, nil, SimpleWeightFunc), "4->4")
+	})
+
+	t.Run("Check directed neighbours path", func(t *testing.T) {
+		expectTrue(t, checkPathFunction(gr, 1, 2, nil, SimpleWeightFunc), "1->2")
+		expectTrue(t, checkPathFunction(gr, 2, 4, nil, SimpleWeightFunc), "2->4")
+		expectTrue(t, checkPathFunction(gr, 1, 6, nil, SimpleWeightFunc), "1->6")
+	})
+
+	t.Run("Check undirected neighbours path", func(t *testing.T) {
+		expectTrue(t, checkPathFunction(gr, 4, 6, nil, SimpleWeightFunc), "4->6")
+		expectTrue(t, checkPathFunction(gr, 6, 4, nil, SimpleWeightFunc), "6->4")
+	})
+
+	t.Run("Check reversed directed neighbours", func(t *testing.T) {
+		expectFalse(t, checkPathFunction(gr, 6, 1, nil, SimpleWeightFunc), "6->1")
+		expectFalse(t, checkPathFunction(gr, 4, 3, nil, SimpleWeightFunc), "4->3")
+		expectFalse(t, checkPathFunction(gr, 5, 4, nil, SimpleWeightFunc), "5->4")
+	})
+
+	t.Run("Check long path", func(t *testing.T) {
+		expectTrue(t, checkPathFunction(gr, 1, 6, nil, SimpleWeightFunc), "1->6")
+		expectTrue(t, checkPathFunction(gr, 1, 5, nil, SimpleWeightFunc), "1->5")
+		expectTrue(t, checkPathFunction(gr, 6, 5, nil, SimpleWeightFunc), "6->5")
+		expectTrue(t, checkPathFunction(gr, 3, 6, nil, SimpleWeightFunc), "3->6")
+
+		expectFalse(t, checkPathFunction(gr, 6, 3, nil, SimpleWeightFunc), "6->3")
+	})
+
+	t.Run("Check weight limit", func(t *testing.T) {
+		expectFalse(t, checkPathFunction(gr, 1, 5, func(node VertexId, weight float64) bool {
 			return weight < 2.0
-		}, SimpleWeightFunc), IsFalse)
+		}, SimpleWeightFunc), "1->5 with weight limit")
 	})
 }
 
-func GetAllMixedPathsSpec(c gospec.Context) {
+func TestCheckPath(t *testing.T) {
+	t.Run("CheckDirectedPath(Dijkstra)", func(t *testing.T) {
+		CheckDirectedPathSpec(t, CheckDirectedPathDijkstra)
+	})
+	t.Run("CheckMixedPath(Dijkstra)", func(t *testing.T) {
+		CheckMixedPathSpec(t, CheckMixedPathDijkstra)
+	})
+}
+
+func TestGetAllMixedPaths(t *testing.T) {
 	gr := generateMixedGraph1()
 	/*
-	[1 2 4 6]          
-	[1 2 6]            
-	[1 2 3 4 6]        
+	[1 2 4 6]
+	[1 2 6]
+	[1 2 3 4 6]
 	[1 6]
 	*/
 
 	pathsCnt := 0
-	
+
 	for path := range GetAllMixedPaths(gr, 1, 6) {
 		pathsCnt++
-		c.Expect(ContainMixedPath(gr, path, true), IsTrue)
+		expectTrue(t, ContainMixedPath(gr, path, true), "path exists in graph")
 	}
-	
-	c.Expect(pathsCnt, Equals, 4)
+
+	expectEquals(t, pathsCnt, 4)
+}
+
+func TestGetAllPathsContext(t *testing.T) {
+	gr := generateMixedGraph1()
+	collect := func(ch <-chan []VertexId) [][]VertexId {
+		paths := make([][]VertexId, 0)
+		for path := range ch {
+			paths = append(paths, path)
+		}
+		return paths
+	}
+
+	t.Run("limits", func(t *testing.T) {
+		paths := collect(GetAllMixedPathsContext(context.Background(), gr, 1, 6, PathsOptions{MaxLength: 2}))
+		expectEquals(t, len(paths), 2)
+		for _, path := range paths {
+			expectTrue(t, len(path)<=3, "path length")
+		}
+		paths = collect(GetAllMixedPathsContext(context.Background(), gr, 1, 6, PathsOptions{MaxCount: 3}))
+		expectEquals(t, len(paths), 3)
+		paths = collect(GetAllMixedPathsContext(context.Background(), gr, 1, 6, PathsOptions{MaxCount: 10}))
+		expectEquals(t, len(paths), 4)
+	})
+
+	t.Run("step predicate", func(t *testing.T) {
+		paths := collect(GetAllMixedPathsContext(context.Background(), gr, 1, 6, PathsOptions{
+			Step: func(path []VertexId, next VertexId) bool {
+				return next!=4
+			},
+		}))
+		expectEquals(t, len(paths), 2)
+		for _, path := range paths {
+			expectFalse(t, containsVertex(path, 4), "path through filtered vertex")
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := GetAllMixedPathsContext(ctx, gr, 1, 6, PathsOptions{})
+		<-ch
+		cancel()
+		// consumer stopped reading, but channel must be closed anyway
+		done := make(chan bool)
+		go func() {
+			for _ = range ch {
+			}
+			done <- true
+		}()
+		select {
+			case <-done:
+			case <-time.After(5*time.Second):
+				t.Fatal("channel isn't closed after cancel")
+		}
+	})
+
+	t.Run("sequence", func(t *testing.T) {
+		cnt := 0
+		for path := range AllPathsSeq(NewMgraphOutNeighboursExtractor(gr), 1, 6, PathsOptions{}) {
+			expectTrue(t, ContainMixedPath(gr, path, true), "path exists in graph")
+			cnt++
+			if cnt==2 {
+				break
+			}
+		}
+		expectEquals(t, cnt, 2)
+
+		dgr := generateDirectedGraph1()
+		cnt = 0
+		for _ = range GetAllDirectedPathsContext(context.Background(), dgr, 1, 5, PathsOptions{}) {
+			cnt++
+		}
+		expectEquals(t, cnt, 2)
+		cnt = 0
+		for _ = range AllPathsSeq(NewDgraphOutNeighboursExtractor(dgr), 3, 3, PathsOptions{}) {
+			cnt++
+		}
+		expectEquals(t, cnt, 0)
+	})
 }
 
-func BellmanFordSingleSourceSpec(c gospec.Context) {
+func TestBellmanFordSingleSource(t *testing.T) {
 	gr := generateDirectedGraph1()
-	
+
 	marks := BellmanFordSingleSource(gr, VertexId(2), SimpleWeightFunc)
-	c.Expect(len(marks), Equals, gr.Order())
+	expectEquals(t, len(marks), gr.Order())
 
-	c.Expect(PathFromMarks(marks, VertexId(6)), ContainsExactly, Values(VertexId(2), VertexId(6)))
-	c.Expect(PathFromMarks(marks, VertexId(5)), ContainsExactly, Values(VertexId(2), VertexId(4), VertexId(5)))
-	c.Expect(PathFromMarks(marks, VertexId(1)), ContainsExactly, Values())
+	expectPath(t, PathFromMarks(marks, VertexId(6)), 2, 6)
+	expectPath(t, PathFromMarks(marks, VertexId(5)), 2, 4, 5)
+	expectPath(t, PathFromMarks(marks, VertexId(1)))
 }
 
-func TestSearch(t *testing.T) {
-	r := gospec.NewRunner()
+func TestDijkstra(t *testing.T) {
+	gr := NewWeightedDirectedMap()
+	gr.AddWeightedArc(1, 2, 1.0)
+	gr.AddWeightedArc(2, 3, 1.0)
+	gr.AddWeightedArc(1, 3, 3.0)
+	gr.AddWeightedArc(3, 4, 0.0)
+	gr.AddWeightedArc(4, 5, 2.0)
+	gr.AddWeightedArc(6, 5, 1.0)
+	gr.AddNode(7)
+	extractor := NewDgraphOutNeighboursExtractor(gr)
 
-	{
-		// paramenerized test creator
-		cr := func(checkPathFunction CheckDirectedPath) func (c gospec.Context) {
-			return func(c gospec.Context){
-				CheckDirectedPathSpec(c, checkPathFunction)
-			}
-		}
-		r.AddNamedSpec("CheckDirectedPath(Dijkstra)", cr(CheckDirectedPathDijkstra))
+	t.Run("single source", func(t *testing.T) {
+		marks := DijkstraSingleSource(extractor, 1, ArcWeightFunc(gr))
+		expectEquals(t, len(marks), 5)
+		expectEquals(t, marks[5].Weight, 4.0)
+		expectPath(t, PathFromMarks(marks, 5), 1, 2, 3, 4, 5)
+		expectPath(t, PathFromMarks(marks, 1), 1)
+		expectPath(t, PathFromMarks(marks, 7))
+	})
+
+	t.Run("multi source", func(t *testing.T) {
+		marks := DijkstraMultiSource(extractor, Vertexes{1, 6}, ArcWeightFunc(gr))
+		expectEquals(t, marks[5].Weight, 1.0)
+		expectPath(t, PathFromMarks(marks, 5), 6, 5)
+		expectPath(t, PathFromMarks(marks, 4), 1, 2, 3, 4)
+	})
+
+	t.Run("early exit", func(t *testing.T) {
+		marks := DijkstraSingleSource(extractor, 1, ArcWeightFunc(gr), 3)
+		expectPath(t, PathFromMarks(marks, 3), 1, 2, 3)
+		expectEquals(t, marks[3].Weight, 2.0)
+		_, ok := marks[5]
+		expectFalse(t, ok, "vertex after target is marked")
+	})
+
+	t.Run("undirected graph", func(t *testing.T) {
+		ugr := NewWeightedUndirectedMap()
+		ugr.AddWeightedEdge(1, 2, 5.0)
+		ugr.AddWeightedEdge(3, 2, 1.0)
+		ugr.AddWeightedEdge(1, 3, 1.0)
+		marks := DijkstraSingleSource(NewUgraphOutNeighboursExtractor(ugr), 2, EdgeWeightFunc(ugr))
+		expectPath(t, PathFromMarks(marks, 1), 2, 3, 1)
+	})
+
+	t.Run("negative weight", func(t *testing.T) {
+		gr := NewWeightedDirectedMap()
+		gr.AddWeightedArc(1, 2, 1.0)
+		gr.AddWeightedArc(2, 3, -1.0)
+		_, err := TryDijkstraSingleSource(NewDgraphOutNeighboursExtractor(gr), 1, ArcWeightFunc(gr))
+		expectErrorIs(t, err, ErrNegativeWeight)
+		expectPanic(t, "negative weight", func() {
+			DijkstraSingleSource(NewDgraphOutNeighboursExtractor(gr), 1, ArcWeightFunc(gr))
+		})
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		_, err := TryDijkstraSingleSource(extractor, 10, ArcWeightFunc(gr))
+		expectTrue(t, err!=nil, "error for unknown source")
+	})
+}
+
+func TestBellmanFordNegativeWeights(t *testing.T) {
+	gr := NewWeightedDirectedMap()
+	gr.AddWeightedArc(1, 2, 1.0)
+	gr.AddWeightedArc(2, 3, -2.0)
+	gr.AddWeightedArc(1, 3, 0.5)
+
+	marks := BellmanFordSingleSource(gr, 1, ArcWeightFunc(gr))
+	expectEquals(t, marks[3].Weight, -1.0)
+	expectPath(t, PathFromMarks(marks, 3), 1, 2, 3)
+}
+
+func TestBellmanFordLight(t *testing.T) {
+	gr := generateDirectedGraph1()
+	marks := BellmanFordLightSingleSource(NewDgraphOutNeighboursExtractor(gr), 2, SimpleWeightFunc)
+	expectEquals(t, len(marks), 5)
+	_, ok := marks[1]
+	expectFalse(t, ok, "unreachable vertex is marked")
+	expectPath(t, PathFromMarks(marks, 5), 2, 4, 5)
+	expectEquals(t, marks[5].Weight, 2.0)
+
+	gr.AddArc(5, 2)
+	negative := func(tail, head VertexId) float64 { return -1.0 }
+	expectTrue(t, BellmanFordLightSingleSource(NewDgraphOutNeighboursExtractor(gr), 2, negative)==nil, "negative cycle")
+}
+
+func expectNegativeCycle(t *testing.T, gr DirectedGraphArcsReader, cycle Vertexes, weightFunc ConnectionWeightFunc) {
+	t.Helper()
+	expectTrue(t, len(cycle)>0, "cycle is found")
+	weight := 0.0
+	for i, tail := range cycle {
+		head := cycle[(i+1)%len(cycle)]
+		expectTrue(t, gr.CheckArc(tail, head), "cycle arc exists")
+		weight += weightFunc(tail, head)
 	}
-	{
-		// paramenerized test creator
-		cr := func(checkPathFunction CheckMixedPath) func (c gospec.Context) {
-			return func(c gospec.Context){
-				CheckMixedPathSpec(c, checkPathFunction)
+	expectTrue(t, weight<0, "cycle is negative")
+}
+
+func TestSPFA(t *testing.T) {
+	t.Run("negative weights", func(t *testing.T) {
+		gr := NewWeightedDirectedMap()
+		gr.AddWeightedArc(1, 2, 1.0)
+		gr.AddWeightedArc(2, 3, -2.0)
+		gr.AddWeightedArc(1, 3, 0.5)
+		gr.AddWeightedArc(4, 1, -5.0)
+		marks, cycle := SPFASingleSource(NewDgraphOutNeighboursExtractor(gr), 1, ArcWeightFunc(gr))
+		expectEquals(t, len(cycle), 0)
+		expectEquals(t, len(marks), 3)
+		expectEquals(t, marks[3].Weight, -1.0)
+		expectPath(t, PathFromMarks(marks, 3), 1, 2, 3)
+	})
+
+	t.Run("negative cycle", func(t *testing.T) {
+		gr := NewWeightedDirectedMap()
+		ReadDgraphLine(gr, "1>2>3>4>2")
+		gr.AddArc(4, 5)
+		weightFunc := func(tail, head VertexId) float64 {
+			if tail==4 && head==2 {
+				return -3.0
 			}
+			return 1.0
 		}
-		r.AddNamedSpec("CheckMixedPath(Dijkstra)", cr(CheckMixedPathDijkstra))
-	}
-	
-	r.AddSpec(GetAllMixedPathsSpec)
-	r.AddSpec(BellmanFordSingleSourceSpec)
+		marks, cycle := SPFASingleSource(NewDgraphOutNeighboursExtractor(gr), 1, weightFunc)
+		expectTrue(t, marks==nil, "no marks with negative cycle")
+		expectVertexesExactly(t, cycle, 2, 3, 4)
+		expectNegativeCycle(t, gr, cycle, weightFunc)
 
+		// cycle isn't reachable from source
+		_, cycle = SPFASingleSource(NewDgraphOutNeighboursExtractor(gr), 5, weightFunc)
+		expectEquals(t, len(cycle), 0)
+	})
 
-	gospec.MainGoTest(r, t)
+	t.Run("negative loop", func(t *testing.T) {
+		gr := NewDirectedMatrix(0)
+		ReadDgraphLine(gr, "1>2>2")
+		marks, cycle := SPFASingleSource(NewDgraphOutNeighboursExtractor(gr), 1, func(tail, head VertexId) float64 { return -1.0 })
+		expectTrue(t, marks==nil, "no marks with negative cycle")
+		expectPath(t, cycle, 2)
+	})
+
+	t.Run("random graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		size := 40
+		for i:=0; i<50; i++ {
+			gr := NewWeightedDirectedMap()
+			for node:=0; node<size; node++ {
+				gr.AddNode(VertexId(node))
+			}
+			for j:=0; j<size*2; j++ {
+				tail, head := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+				if tail!=head && !gr.CheckArc(tail, head) {
+					gr.AddWeightedArc(tail, head, float64(rnd.Intn(20)-3))
+				}
+			}
+			weightFunc := ArcWeightFunc(gr)
+			expected := BellmanFordSingleSource(gr, 0, weightFunc)
+			marks, cycle := SPFASingleSource(NewDgraphOutNeighboursExtractor(gr), 0, weightFunc)
+			if expected==nil {
+				expectNegativeCycle(t, gr, cycle, weightFunc)
+				continue
+			}
+			expectEquals(t, len(cycle), 0)
+			for node, mark := range expected {
+				spfaMark, ok := marks[node]
+				expectEquals(t, ok, mark.Weight!=math.MaxFloat64)
+				if ok {
+					expectEquals(t, spfaMark.Weight, mark.Weight)
+				}
+			}
+		}
+	})
 }
@@ -1,7 +1,7 @@
 package graph
 
 import (
-	"github.com/StepLg/go-erx/src/erx"
+	"sort"
 )
 
 // Connection type.
@@ -56,229 +56,96 @@ func NewDirectedConnection(tail, head VertexId) TypedConnection {
 	}
 }
 
-// internal struct to store node with it's priority for priority queue
-type priority_data_t struct {
-	Node VertexId
-	Priority float64
-}
-
-type nodesPriority []priority_data_t
-
-func (d nodesPriority) Less(i, j int) bool {
-	return d[i].Priority < d[j].Priority 
-}
-
-func (d nodesPriority) Swap(i, j int) {
-	d[i], d[j] = d[j], d[i]
-}
-
-func (d nodesPriority) Len() int {
-	return len(d)
-}
-
-// Vertexes priority queue
+// Internal vertexes ids of matrix graphs.
 //
-// Note: internal use only! while there is lack of standart containers in golang.
-type nodesPriorityQueue interface {
-	// Add new item to queue
-	Add(node VertexId, priority float64)
-	// Get item with max priority and remove it from the queue
-	//
-	// Panic if queue is empty
-	Next() (VertexId, float64)
-	// Get item with max priority without removing it from the queue
-	//
-	// Panic if queue is empty
-	Pick() (VertexId, float64)
-	// Total queue size
-	Size() int
-	// Check if queue is empty
-	Empty() bool
-}
-
-// Very simple nodes priority queue
-//
-// Warning! It's very UNEFFICIENT!!!
-type nodesPriorityQueueSimple struct {
-	data nodesPriority
-	nodesIndex map[VertexId]int
-	size int
+// Ids of removed vertexes are reused for new ones. If there are no free ids,
+// matrix capacity (size) is doubled.
+type matrixIds struct {
+	VertexIds map[VertexId]int // internal node ids, used in matrix
+	freeIds []int // internal ids of removed nodes
+	size int // matrix capacity
+}
+
+func newMatrixIds(size int) matrixIds {
+	return matrixIds{
+		VertexIds: make(map[VertexId]int),
+		freeIds: make([]int, 0),
+		size: size,
+	}
 }
 
-// Create new simple nodes priority queue
+// Getting internal id of node, allocating new one if node doesn't exist.
 //
-// size is maximum number of nodes, which queue can store simultaneously
-func newPriorityQueueSimple(initialSize int) *nodesPriorityQueueSimple {
-	if initialSize<=0 {
-		err := erx.NewError("Can't create priority queue with non-positive size.")
-		err.AddV("size", initialSize)
-		panic(err)
+// grown is true if matrix size was increased to fit the new id.
+func (m *matrixIds) allocId(node VertexId) (id int, grown bool) {
+	if id, ok := m.VertexIds[node]; ok {
+		return id, false
 	}
-	
-	q := &nodesPriorityQueueSimple {
-		data: make(nodesPriority, initialSize),
-		nodesIndex: make(map[VertexId]int),
-		size: 0,
-	}
-	return q
-}
-
-// Add new item to queue
-func (q *nodesPriorityQueueSimple) Add(node VertexId, priority float64) {
-	defer func() {
-		if e := recover(); e!=nil {
-			err := erx.NewSequent("", e)
-			err.AddV("node", node)
-			err.AddV("priority", priority)
-			panic(err)
-		}
-	}()
-	
-	found := false
-	if id, ok := q.nodesIndex[node]; ok {
-		if priority > q.data[id].Priority { 
-			q.data[id].Priority = priority
-			// changing position
-			newId := id+1
-			for q.data[newId].Priority<priority && newId<q.size {
-				newId++
-			}
-			
-			if newId > id+1 {
-				// need to move
-				copy(q.data[id:newId-1], q.data[id+1:newId])
-				q.data[newId-1].Node = node
-				q.data[newId-1].Priority = priority
+	if len(m.freeIds)>0 {
+		id = m.freeIds[len(m.freeIds)-1]
+		m.freeIds = m.freeIds[:len(m.freeIds)-1]
+	} else {
+		id = len(m.VertexIds)
+		if id>=m.size {
+			// 2 is just a magic number
+			m.size = 2*m.size
+			if m.size==0 {
+				m.size = 1
 			}
+			grown = true
 		}
-		found = true
 	}
+	m.VertexIds[node] = id
+	return id, grown
+}
 
-	if !found {
-		if q.size==len(q.data) {
-			// resize
-			// 2 is just a magic number
-			newData := make(nodesPriority, 2*len(q.data))
-			copy(newData, q.data)
-			q.data = newData
-		}
-		id := 0
-		for q.data[id].Priority<priority && id<q.size {
-			id++
-		}
-		if id<q.size {
-			copy(q.data[id+1:q.size+1], q.data[id:q.size])
-		}
-		q.data[id].Node = node
-		q.data[id].Priority = priority
-		q.nodesIndex[node] = id
-		q.size++
-	}
+// Releasing internal id of removed node.
+func (m *matrixIds) releaseId(node VertexId) {
+	m.freeIds = append(m.freeIds, m.VertexIds[node])
+	delete(m.VertexIds, node)
 }
 
-// Get item with max priority and remove it from the queue
+// Renumbering internal ids without gaps and shrinking size to nodes count.
 //
-// Panic if queue is empty
-func (q *nodesPriorityQueueSimple) Next() (VertexId, float64) {
-	if q.Empty() {
-		panic("Can't pick from empty queue.")
+// Returns mapping from old ids to new ones.
+func (m *matrixIds) compactIds() map[int]int {
+	oldIds := make([]int, 0, len(m.VertexIds))
+	for _, id := range m.VertexIds {
+		oldIds = append(oldIds, id)
 	}
-	node := q.data[q.size-1].Node
-	prior := q.data[q.size-1].Priority
-	q.size--
-	
-	return node, prior
+	sort.Ints(oldIds)
+	mapping := make(map[int]int, len(oldIds))
+	for newId, oldId := range oldIds {
+		mapping[oldId] = newId
+	}
+	for node, oldId := range m.VertexIds {
+		m.VertexIds[node] = mapping[oldId]
+	}
+	m.freeIds = m.freeIds[:0]
+	m.size = len(m.VertexIds)
+	return mapping
 }
 
-// Get item with max priority without removing it from the queue
+// Position of connection between two different internal ids in one-dimential
+// array, representing triangle matrix.
 //
-// Panic if queue is empty
-func (q *nodesPriorityQueueSimple) Pick() (VertexId, float64) {
-	if q.Empty() {
-		panic("Can't pick from empty queue.")
+// Position doesn't depend on matrix size, so matrix could grow by appending
+// new cells to the end of array. Matrix of size n uses n*(n-1)/2 cells.
+func matrixTriangleIndex(id1, id2 int) int {
+	if id1>id2 {
+		id1, id2 = id2, id1
 	}
-	node := q.data[q.size-1].Node
-	prior := q.data[q.size-1].Priority
-	return node, prior
-}
-
-// Total queue size
-func (q *nodesPriorityQueueSimple) Size() int {
-	return q.size
+	return id2*(id2-1)/2 + id1
 }
 
-// Check if queue is empty
-func (q *nodesPriorityQueueSimple) Empty() bool {
-	return q.Size()==0
-}
-
-// Index function for matrix storage.
+// Position of connection from one internal id to another in one-dimential
+// array, representing square matrix.
 //
-// node1, node2 - vertexes
-// VertexIds - map with vertexes as keys and their positions in matrix as values
-// size - total size of matrix
-// craeate - flag. Set True, if you need to create indexes for vertexes, if they
-//           doesn't exist in vertexIds
-//
-// As a result function returns position of connection in one-dimential array,
-// representing upper-diagonal matrix.
-func matrixConnectionsIndexer(node1, node2 VertexId, vertexIds map[VertexId]int, size int, create bool) int {
-	defer func() {
-		if e := recover(); e!=nil {
-			err := erx.NewSequent("Calculating connection id.", e)
-			err.AddV("node 1", node1)
-			err.AddV("node 2", node2)
-			panic(err)
-		}
-	}()
-	
-	var id1, id2 int
-	node1Exist := false
-	node2Exist := false
-	id1, node1Exist = vertexIds[node1]
-	id2, node2Exist = vertexIds[node2]
-	
-	// checking for errors
-	{
-		if node1==node2 {
-			panic(erx.NewError("Equal nodes."))
-		}
-		if !create {
-			if !node1Exist {
-				panic(erx.NewError("First node doesn't exist in graph"))
-			}
-			if !node2Exist {
-				panic(erx.NewError("Second node doesn't exist in graph"))
-			}
-		} else if !node1Exist || !node2Exist {
-			if node1Exist && node2Exist {
-				if size - len(vertexIds) < 2 {
-					panic(erx.NewError("Not enough space to create two new nodes."))
-				}
-			} else {
-				if size - len(vertexIds) < 1 {
-					panic(erx.NewError("Not enough space to create new node."))
-				}
-			}
-		}
+// Position doesn't depend on matrix size, so matrix could grow by appending
+// new cells to the end of array. Matrix of size n uses n*n cells.
+func matrixSquareIndex(from, to int) int {
+	if from<to {
+		return to*to + from
 	}
-	
-	if !node1Exist {
-		id1 = int(len(vertexIds))
-		vertexIds[node1] = id1
-	}
-
-	if !node2Exist {
-		id2 = int(len(vertexIds))
-		vertexIds[node2] = id2
-	}
-	
-	// switching id1, id2 in order to id1 < id2
-	if id1>id2 {
-		id1, id2 = id2, id1
-	}
-	
-	// id from upper triangle matrix, stored in vector
-	connId := id1*(size-1) + id2 - 1 - id1*(id1+1)/2
-	return connId 
+	return from*from + from + to
 }
@@ -0,0 +1,293 @@
+package graph
+
+import (
+	"container/heap"
+	"iter"
+	"sort"
+)
+
+// Decide which of simultaneously ready vertexes goes first in topological order
+//
+// Returns true if a must go before b. Function must be a strict weak order,
+// and it's recommended to break all ties (e.g. by vertex id), otherwise
+// order of equal vertexes is undefined.
+type TopologicalTieBreaker func(a, b VertexId) bool
+
+// Vertex with lowest id goes first
+//
+// With this tie-breaker KahnTopologicalSort returns lexicographically smallest
+// topological order.
+func LowestIdFirst(a, b VertexId) bool {
+	return a<b
+}
+
+// Vertex with lowest priority goes first, equal priorities are ordered by id
+func PriorityTieBreaker(priority func(node VertexId) float64) TopologicalTieBreaker {
+	return func(a, b VertexId) bool {
+		pa, pb := priority(a), priority(b)
+		if pa!=pb {
+			return pa<pb
+		}
+		return a<b
+	}
+}
+
+// Vertexes go in order of their position in given slice
+//
+// Usually it's the order in which vertexes were added to graph. Vertexes,
+// which are absent in slice, go after all others ordered by id.
+func InsertionOrderTieBreaker(order Vertexes) TopologicalTieBreaker {
+	position := make(map[VertexId]int, len(order))
+	for i, node := range order {
+		if _, ok := position[node]; !ok {
+			position[node] = i
+		}
+	}
+	return func(a, b VertexId) bool {
+		pa, okA := position[a]
+		pb, okB := position[b]
+		switch {
+			case okA && okB:
+				return pa<pb
+			case okA!=okB:
+				return okA
+		}
+		return a<b
+	}
+}
+
+// Topological sort of directed graph with Kahn algorithm
+//
+// Unlike TopologicalSort result doesn't depend on graph internals: when
+// several vertexes are ready, the first one according to less goes first.
+// If less is nil, LowestIdFirst is used. If graph has cycles, then
+// hasCycles==true and nodes==nil in function result.
+func KahnTopologicalSort(gr DirectedGraphReader, less TopologicalTieBreaker) (nodes []VertexId, hasCycles bool) {
+	if less==nil {
+		less = LowestIdFirst
+	}
+	inDegree := topologicalInDegrees(gr)
+	ready := &readyVertexes{less: less}
+	for node, degree := range inDegree {
+		if degree==0 {
+			ready.nodes = append(ready.nodes, node)
+		}
+	}
+	heap.Init(ready)
+
+	nodes = make([]VertexId, 0, len(inDegree))
+	for ready.Len()>0 {
+		node := heap.Pop(ready).(VertexId)
+		nodes = append(nodes, node)
+		for next := range VertexesSeqOf(gr.GetAccessors(node)) {
+			inDegree[next]--
+			if inDegree[next]==0 {
+				heap.Push(ready, next)
+			}
+		}
+	}
+	if len(nodes)!=len(inDegree) {
+		return nil, true
+	}
+	return nodes, false
+}
+
+// Lexicographically smallest topological order of directed graph
+//
+// See KahnTopologicalSort for details.
+func LexicographicTopologicalSort(gr DirectedGraphReader) (nodes []VertexId, hasCycles bool) {
+	return KahnTopologicalSort(gr, LowestIdFirst)
+}
+
+// Topological sort of mixed graph
+//
+// Edges could be oriented in any direction, so the order exists if and only if
+// arcs alone don't form a cycle. Vertexes order is the same as for
+// KahnTopologicalSort with LowestIdFirst tie-breaker applied to arcs only,
+// and each edge is assumed to be oriented from earlier vertex to the later
+// one. See AcyclicOrientation_mixed for details.
+func TopologicalSort_mixed(gr MixedGraphReader) (nodes []VertexId, hasCycles bool) {
+	_, nodes, hasCycles = AcyclicOrientation_mixed(gr, nil)
+	return
+}
+
+// Orient mixed graph edges so that whole graph becomes acyclic
+//
+// Returns directed graph with all vertexes and arcs from original graph and
+// each edge replaced by arc, and topological order of this graph. Ready
+// vertexes are ordered with less (LowestIdFirst if nil), so with it's help
+// edges could be oriented in preferable direction. If there is no such
+// orientation (arcs form a cycle), then hasCycles==true, oriented==nil and
+// nodes==nil in function result. Parallel connections of multigraph become
+// single arc.
+func AcyclicOrientation_mixed(gr MixedGraphReader, less TopologicalTieBreaker) (oriented DirectedGraph, nodes []VertexId, hasCycles bool) {
+	for edge := range EdgesSeqOf(gr) {
+		if edge.Tail==edge.Head {
+			// loop can't be oriented
+			return nil, nil, true
+		}
+	}
+	// mixed graph reader is directed graph reader of it's arcs
+	nodes, hasCycles = KahnTopologicalSort(gr, less)
+	if hasCycles {
+		return nil, nil, true
+	}
+	
+	pos := make(map[VertexId]int, len(nodes))
+	oriented = NewDirectedMap()
+	for i, node := range nodes {
+		pos[node] = i
+		oriented.AddNode(node)
+	}
+	addArc := func(tail, head VertexId) {
+		if !oriented.CheckArc(tail, head) {
+			oriented.AddArc(tail, head)
+		}
+	}
+	for arc := range ArcsSeqOf(gr) {
+		addArc(arc.Tail, arc.Head)
+	}
+	for edge := range EdgesSeqOf(gr) {
+		if pos[edge.Tail]<pos[edge.Head] {
+			addArc(edge.Tail, edge.Head)
+		} else {
+			addArc(edge.Head, edge.Tail)
+		}
+	}
+	return oriented, nodes, false
+}
+
+// Split directed acyclic graph to layers
+//
+// First layer contains all sources, and each next layer contains vertexes,
+// which have all predecessors in previous layers. So all vertexes from one
+// layer are independent and could be processed in parallel right after
+// previous layers. Vertexes in each layer are sorted by id. If graph has
+// cycles, then hasCycles==true and layers==nil in function result.
+func TopologicalLayers(gr DirectedGraphReader) (layers []Vertexes, hasCycles bool) {
+	inDegree := topologicalInDegrees(gr)
+	layer := Vertexes{}
+	for node, degree := range inDegree {
+		if degree==0 {
+			layer = append(layer, node)
+		}
+	}
+
+	processed := 0
+	for len(layer)>0 {
+		sort.Slice(layer, func(i, j int) bool { return layer[i]<layer[j] })
+		layers = append(layers, layer)
+		processed += len(layer)
+		nextLayer := Vertexes{}
+		for _, node := range layer {
+			for next := range VertexesSeqOf(gr.GetAccessors(node)) {
+				inDegree[next]--
+				if inDegree[next]==0 {
+					nextLayer = append(nextLayer, next)
+				}
+			}
+		}
+		layer = nextLayer
+	}
+	if processed!=len(inDegree) {
+		return nil, true
+	}
+	return layers, false
+}
+
+// Iterate over all topological orders of directed graph
+//
+// Orders are generated in lexicographical order. Number of orders grows
+// exponentially (it's n! for graph without arcs), so it's useful only for
+// small graphs or with early stop. Nothing is generated if graph has cycles.
+// Each generated slice is a new one and could be kept by caller.
+func AllTopologicalSortsSeq(gr DirectedGraphReader) iter.Seq[Vertexes] {
+	return func(yield func(Vertexes) bool) {
+		if _, hasCycles := KahnTopologicalSort(gr, nil); hasCycles {
+			return
+		}
+		inDegree := topologicalInDegrees(gr)
+		vertexes := make(Vertexes, 0, len(inDegree))
+		for node, _ := range inDegree {
+			vertexes = append(vertexes, node)
+		}
+		sort.Slice(vertexes, func(i, j int) bool { return vertexes[i]<vertexes[j] })
+
+		order := make(Vertexes, 0, len(vertexes))
+		used := make(map[VertexId]bool, len(vertexes))
+		var search func() bool
+		search = func() bool {
+			if len(order)==len(vertexes) {
+				return yield(append(Vertexes{}, order...))
+			}
+			for _, node := range vertexes {
+				if used[node] || inDegree[node]!=0 {
+					continue
+				}
+				used[node] = true
+				order = append(order, node)
+				for next := range VertexesSeqOf(gr.GetAccessors(node)) {
+					inDegree[next]--
+				}
+
+				ok := search()
+
+				for next := range VertexesSeqOf(gr.GetAccessors(node)) {
+					inDegree[next]++
+				}
+				order = order[:len(order)-1]
+				used[node] = false
+				if !ok {
+					return false
+				}
+			}
+			return true
+		}
+		search()
+	}
+}
+
+// Number of predecessors for each graph vertex
+//
+// Counted with GetAccessors, the same way as they are decremented, so
+// parallel arcs in multigraphs are counted once.
+func topologicalInDegrees(gr DirectedGraphReader) map[VertexId]int {
+	inDegree := make(map[VertexId]int, gr.Order())
+	for node := range VertexesSeqOf(gr) {
+		if _, ok := inDegree[node]; !ok {
+			inDegree[node] = 0
+		}
+		for next := range VertexesSeqOf(gr.GetAccessors(node)) {
+			inDegree[next]++
+		}
+	}
+	return inDegree
+}
+
+// Ready to output vertexes heap for Kahn algorithm
+type readyVertexes struct {
+	nodes Vertexes
+	less TopologicalTieBreaker
+}
+
+func (r *readyVertexes) Len() int {
+	return len(r.nodes)
+}
+
+func (r *readyVertexes) Less(i, j int) bool {
+	return r.less(r.nodes[i], r.nodes[j])
+}
+
+func (r *readyVertexes) Swap(i, j int) {
+	r.nodes[i], r.nodes[j] = r.nodes[j], r.nodes[i]
+}
+
+func (r *readyVertexes) Push(x interface{}) {
+	r.nodes = append(r.nodes, x.(VertexId))
+}
+
+func (r *readyVertexes) Pop() interface{} {
+	last := r.nodes[len(r.nodes)-1]
+	r.nodes = r.nodes[:len(r.nodes)-1]
+	return last
+}
@@ -0,0 +1,241 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Directed acyclic graph, which maintains topological order of it's vertexes.
+//
+// Order is updated incrementally with Pearce-Kelly algorithm: adding arc,
+// which goes forward in current order, costs O(1), otherwise only vertexes
+// between arc's head and tail in current order are visited and reordered.
+// Arcs, which would create a cycle, are rejected with *CycleError.
+//
+// Wrapped graph must be modified only through wrapper, otherwise order
+// becomes inconsistent. Note, that Order() is still vertexes count, use
+// Position and TopologicalOrder to get vertexes order.
+type TopologicalOrderGraph struct {
+	DirectedGraph
+	directedGraphSeqs
+	order Vertexes // order[position] is vertex
+	position map[VertexId]int
+}
+
+// Wrap directed graph to maintain it's topological order.
+//
+// Panic with *CycleError if graph already has cycles.
+func NewTopologicalOrderGraph(gr DirectedGraph) *TopologicalOrderGraph {
+	nodes, hasCycles := KahnTopologicalSort(gr, nil)
+	if hasCycles {
+		cycle := FindCycle(gr)
+		err := newCycleError("create topological order", cycle)
+		erxErr := erx.NewSequentLevel("Create topological order graph.", err, 1)
+		erxErr.AddV("cycle", cycle)
+		panic(erxErr)
+	}
+	g := &TopologicalOrderGraph{
+		DirectedGraph: gr,
+		directedGraphSeqs: directedGraphSeqs{gr},
+		order: nodes,
+		position: make(map[VertexId]int, len(nodes)),
+	}
+	for i, node := range nodes {
+		g.position[node] = i
+	}
+	return g
+}
+
+// Getting vertex position in current topological order.
+func (g *TopologicalOrderGraph) Position(node VertexId) (int, bool) {
+	pos, ok := g.position[node]
+	return pos, ok
+}
+
+// Getting copy of current topological order.
+func (g *TopologicalOrderGraph) TopologicalOrder() Vertexes {
+	return append(Vertexes{}, g.order...)
+}
+
+// Adding single node to graph
+//
+// New node goes last in topological order.
+func (g *TopologicalOrderGraph) AddNode(node VertexId) {
+	if err := g.TryAddNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Add node to graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Adding single node to graph
+//
+// Returns ErrNodeExists if node is already in graph.
+func (g *TopologicalOrderGraph) TryAddNode(node VertexId) error {
+	if _, ok := g.position[node]; ok {
+		return &VertexError{Op: "add node", Node: node, Err: ErrNodeExists}
+	}
+	if err := catchPanic(func() { g.DirectedGraph.AddNode(node) }); err!=nil {
+		return err
+	}
+	g.position[node] = len(g.order)
+	g.order = append(g.order, node)
+	return nil
+}
+
+// Removing node with all it's arcs from graph
+//
+// Positions of all next vertexes in topological order are decreased, so it
+// costs O(V).
+func (g *TopologicalOrderGraph) RemoveNode(node VertexId) {
+	if err := g.TryRemoveNode(node); err!=nil {
+		erxErr := erx.NewSequentLevel("Remove node from graph.", err, 1)
+		erxErr.AddV("node id", node)
+		panic(erxErr)
+	}
+}
+
+// Removing node with all it's arcs from graph
+//
+// Returns ErrNodeNotFound if there is no such node in graph.
+func (g *TopologicalOrderGraph) TryRemoveNode(node VertexId) error {
+	pos, ok := g.position[node]
+	if !ok {
+		return &VertexError{Op: "remove node", Node: node, Err: ErrNodeNotFound}
+	}
+	if err := catchPanic(func() { g.DirectedGraph.RemoveNode(node) }); err!=nil {
+		return err
+	}
+	delete(g.position, node)
+	g.order = append(g.order[:pos], g.order[pos+1:]...)
+	for i:=pos; i<len(g.order); i++ {
+		g.position[g.order[i]] = i
+	}
+	return nil
+}
+
+// Adding arrow to graph.
+//
+// Panic with *CycleError if arc would create a cycle.
+func (g *TopologicalOrderGraph) AddArc(from, to VertexId) {
+	if err := g.TryAddArc(from, to); err!=nil {
+		erxErr := erx.NewSequentLevel("Add arc to graph.", err, 1)
+		erxErr.AddV("tail", from)
+		erxErr.AddV("head", to)
+		panic(erxErr)
+	}
+}
+
+// Adding arrow to graph.
+//
+// Nodes are created if they don't exist. Returns *CycleError (ErrCycle) if
+// arc would create a cycle (including loop) and ErrDuplicateConnection if
+// arc already exists. Graph isn't changed in case of cycle.
+func (g *TopologicalOrderGraph) TryAddArc(from, to VertexId) error {
+	if from==to {
+		return &CycleError{Op: "add arc", Tail: from, Head: to, Cycle: Vertexes{from}}
+	}
+	// new nodes can't be a part of a cycle
+	for _, node := range [...]VertexId{from, to} {
+		if _, ok := g.position[node]; !ok {
+			if err := g.TryAddNode(node); err!=nil {
+				return err
+			}
+		}
+	}
+
+	lower, upper := g.position[to], g.position[from]
+	if lower<upper {
+		// arc goes backward, so vertexes between head and tail must be reordered
+		forward, cycle := g.forwardAffected(to, from, upper)
+		if cycle!=nil {
+			return &CycleError{Op: "add arc", Tail: from, Head: to, Cycle: cycle}
+		}
+		g.reorder(g.backwardAffected(from, lower), forward)
+	}
+
+	if writer, ok := g.DirectedGraph.(DirectedGraphArcsTryWriter); ok {
+		return writer.TryAddArc(from, to)
+	}
+	return catchPanic(func() { g.DirectedGraph.AddArc(from, to) })
+}
+
+// Vertexes, accessible from start and not after upper position in order
+//
+// If target (vertex at upper position) is accessible, then cycle
+// target->start->...->target is returned.
+func (g *TopologicalOrderGraph) forwardAffected(start, target VertexId, upper int) (Vertexes, Vertexes) {
+	parent := map[VertexId]VertexId{start: start}
+	visited := Vertexes{start}
+	stack := Vertexes{start}
+	for len(stack)>0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for next := range VertexesSeqOf(g.GetAccessors(node)) {
+			if _, ok := parent[next]; ok || g.position[next]>upper {
+				continue
+			}
+			parent[next] = node
+			if next==target {
+				cycle := Vertexes{}
+				for cur:=node; ; cur=parent[cur] {
+					cycle = append(cycle, cur)
+					if cur==start {
+						break
+					}
+				}
+				cycle = append(cycle, target)
+				reverseVertexes(cycle)
+				return nil, cycle
+			}
+			visited = append(visited, next)
+			stack = append(stack, next)
+		}
+	}
+	return visited, nil
+}
+
+// Vertexes, from which start is accessible, and which are after lower
+// position in order
+func (g *TopologicalOrderGraph) backwardAffected(start VertexId, lower int) Vertexes {
+	visited := map[VertexId]bool{start: true}
+	result := Vertexes{start}
+	stack := Vertexes{start}
+	for len(stack)>0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for prev := range VertexesSeqOf(g.GetPredecessors(node)) {
+			if visited[prev] || g.position[prev]<=lower {
+				continue
+			}
+			visited[prev] = true
+			result = append(result, prev)
+			stack = append(stack, prev)
+		}
+	}
+	return result
+}
+
+// Move backward affected vertexes before forward affected ones
+//
+// Vertexes keep their relative order inside each group and occupy the same
+// set of positions.
+func (g *TopologicalOrderGraph) reorder(backward, forward Vertexes) {
+	byPosition := func(nodes Vertexes) {
+		sort.Slice(nodes, func(i, j int) bool { return g.position[nodes[i]]<g.position[nodes[j]] })
+	}
+	byPosition(backward)
+	byPosition(forward)
+
+	nodes := append(backward, forward...)
+	positions := make([]int, len(nodes))
+	for i, node := range nodes {
+		positions[i] = g.position[node]
+	}
+	sort.Ints(positions)
+	for i, node := range nodes {
+		g.order[positions[i]] = node
+		g.position[node] = positions[i]
+	}
+}
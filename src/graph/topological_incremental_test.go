@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// Check that order of graph is topological and positions are consistent
+func expectConsistentOrder(t *testing.T, gr *TopologicalOrderGraph) {
+	t.Helper()
+	order := gr.TopologicalOrder()
+	expectTopologicalOrder(t, gr, order)
+	for i, node := range order {
+		pos, ok := gr.Position(node)
+		expectTrue(t, ok, "node has position")
+		expectEquals(t, pos, i)
+	}
+}
+
+func TestTopologicalOrderGraph(t *testing.T) {
+	t.Run("Reordering", func(t *testing.T) {
+		gr := NewTopologicalOrderGraph(generateDirectedGraph1())
+		expectPath(t, gr.TopologicalOrder(), 1, 2, 3, 4, 5, 6)
+
+		gr.AddArc(6, 3)
+		expectConsistentOrder(t, gr)
+		pos3, _ := gr.Position(3)
+		pos6, _ := gr.Position(6)
+		expectTrue(t, pos6<pos3, "6 goes before 3")
+
+		gr.AddArc(7, 1)
+		expectConsistentOrder(t, gr)
+		pos7, _ := gr.Position(7)
+		expectEquals(t, pos7, 0)
+
+		_, ok := gr.Position(8)
+		expectFalse(t, ok, "unknown node has position")
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		gr := NewTopologicalOrderGraph(generateDirectedGraph1())
+		err := gr.TryAddArc(5, 2)
+		expectErrorIs(t, err, ErrCycle)
+		var cycleErr *CycleError
+		expectTrue(t, errors.As(err, &cycleErr), "cycle error")
+		expectPath(t, cycleErr.Cycle[:2], 5, 2)
+		expectCycle(t, cycleErr.Cycle, func(tail, head VertexId) bool {
+			return gr.CheckArc(tail, head) || tail==5 && head==2
+		})
+		expectFalse(t, gr.CheckArc(5, 2), "arc 5->2 is added")
+		expectConsistentOrder(t, gr)
+
+		expectErrorIs(t, gr.TryAddArc(3, 3), ErrCycle)
+		expectErrorIs(t, gr.TryAddArc(1, 2), ErrDuplicateConnection)
+		expectPanic(t, "arc creates cycle", func() { gr.AddArc(6, 1) })
+
+		cyclic := generateDirectedGraph1()
+		cyclic.AddArc(4, 2)
+		expectPanic(t, "graph has cycle", func() { NewTopologicalOrderGraph(cyclic) })
+	})
+
+	t.Run("Nodes", func(t *testing.T) {
+		gr := NewTopologicalOrderGraph(generateDirectedGraph1())
+		gr.AddNode(10)
+		pos, _ := gr.Position(10)
+		expectEquals(t, pos, 6)
+		expectErrorIs(t, gr.TryAddNode(10), ErrNodeExists)
+
+		gr.RemoveNode(3)
+		expectFalse(t, gr.CheckNode(3), "node 3 exists")
+		expectEquals(t, len(gr.TopologicalOrder()), 6)
+		expectConsistentOrder(t, gr)
+		expectErrorIs(t, gr.TryRemoveNode(3), ErrNodeNotFound)
+	})
+
+	t.Run("Multigraph", func(t *testing.T) {
+		multi := NewDirectedMultiMap()
+		multi.AddArc(1, 2)
+		multi.AddArc(1, 2)
+		multi.AddArc(2, 3)
+		gr := NewTopologicalOrderGraph(multi)
+		expectPath(t, gr.TopologicalOrder(), 1, 2, 3)
+		expectEquals(t, gr.TryAddArc(2, 3), nil)
+		expectEquals(t, gr.ArcsCnt(), 4)
+		expectErrorIs(t, gr.TryAddArc(3, 1), ErrCycle)
+		expectConsistentOrder(t, gr)
+	})
+
+	t.Run("Random arcs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		for i:=0; i<50; i++ {
+			gr := NewTopologicalOrderGraph(NewDirectedMap())
+			for j:=0; j<60; j++ {
+				tail, head := VertexId(rnd.Intn(15)), VertexId(rnd.Intn(15))
+				if gr.CheckNode(tail) && gr.CheckNode(head) && gr.CheckArc(tail, head) {
+					continue
+				}
+				createsCycle := tail==head || gr.CheckNode(tail) && gr.CheckNode(head) &&
+					findPath(NewDgraphOutNeighboursExtractor(gr), head, tail)!=nil
+				err := gr.TryAddArc(tail, head)
+				if createsCycle {
+					expectErrorIs(t, err, ErrCycle)
+				} else {
+					expectEquals(t, err, nil)
+				}
+				expectConsistentOrder(t, gr)
+			}
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// Check that each arc goes forward in given order
+func expectTopologicalOrder(t *testing.T, gr DirectedGraphReader, nodes []VertexId) {
+	t.Helper()
+	expectEquals(t, len(nodes), gr.Order())
+	pos := make(map[VertexId]int)
+	for i, node := range nodes {
+		pos[node] = i
+	}
+	for arc := range ArcsSeqOf(gr) {
+		expectTrue(t, pos[arc.Tail]<pos[arc.Head], fmt.Sprintf("arc %v->%v goes forward", arc.Tail, arc.Head))
+	}
+}
+
+func TestKahnTopologicalSort(t *testing.T) {
+	t.Run("Lowest id first", func(t *testing.T) {
+		nodes, hasCycles := KahnTopologicalSort(generateDirectedGraph1(), nil)
+		expectFalse(t, hasCycles, "has cycles")
+		expectPath(t, nodes, 1, 2, 3, 4, 5, 6)
+
+		nodes, _ = LexicographicTopologicalSort(generateDirectedGraph1())
+		expectPath(t, nodes, 1, 2, 3, 4, 5, 6)
+	})
+
+	t.Run("Priority", func(t *testing.T) {
+		priority := map[VertexId]float64{6: -1, 3: 1}
+		nodes, hasCycles := KahnTopologicalSort(generateDirectedGraph1(), PriorityTieBreaker(func(node VertexId) float64 {
+			return priority[node]
+		}))
+		expectFalse(t, hasCycles, "has cycles")
+		expectPath(t, nodes, 1, 2, 6, 3, 4, 5)
+	})
+
+	t.Run("Insertion order", func(t *testing.T) {
+		gr := NewDirectedMap()
+		gr.AddArc(3, 1)
+		gr.AddNode(2)
+		gr.AddNode(4)
+		nodes, hasCycles := KahnTopologicalSort(gr, InsertionOrderTieBreaker(Vertexes{3, 2, 1}))
+		expectFalse(t, hasCycles, "has cycles")
+		expectPath(t, nodes, 3, 2, 1, 4)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		gr := generateDirectedGraph1()
+		gr.AddArc(5, 2)
+		nodes, hasCycles := KahnTopologicalSort(gr, nil)
+		expectTrue(t, hasCycles, "has cycles")
+		expectTrue(t, nodes==nil, "nodes are nil")
+
+		gr = NewDirectedMatrix(2)
+		gr.AddArc(0, 1)
+		gr.AddArc(1, 1)
+		_, hasCycles = KahnTopologicalSort(gr, nil)
+		expectTrue(t, hasCycles, "loop is a cycle")
+	})
+
+	t.Run("Random graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		for i:=0; i<100; i++ {
+			gr := NewDirectedMap()
+			for node:=0; node<20; node++ {
+				gr.AddNode(VertexId(node))
+			}
+			for j:=0; j<40; j++ {
+				tail, head := VertexId(rnd.Intn(20)), VertexId(rnd.Intn(20))
+				if tail<head && !gr.CheckArc(tail, head) {
+					gr.AddArc(tail, head)
+				}
+			}
+			nodes, hasCycles := KahnTopologicalSort(gr, nil)
+			expectFalse(t, hasCycles, "has cycles")
+			expectTopologicalOrder(t, gr, nodes)
+		}
+	})
+}
+
+func TestTopologicalSortMultigraph(t *testing.T) {
+	gr := NewDirectedMultiMap()
+	gr.AddArc(1, 2)
+	gr.AddArc(1, 2)
+	gr.AddArc(2, 3)
+
+	nodes, hasCycles := KahnTopologicalSort(gr, nil)
+	expectFalse(t, hasCycles, "has cycles")
+	expectPath(t, nodes, 1, 2, 3)
+
+	layers, hasCycles := TopologicalLayers(gr)
+	expectFalse(t, hasCycles, "has cycles")
+	expectEquals(t, fmt.Sprint(layers), "[[1] [2] [3]]")
+
+	orders := []string{}
+	for nodes := range AllTopologicalSortsSeq(gr) {
+		orders = append(orders, fmt.Sprint(nodes))
+	}
+	expectEquals(t, fmt.Sprint(orders), "[[1 2 3]]")
+
+	gr.AddArc(3, 2)
+	_, hasCycles = KahnTopologicalSort(gr, nil)
+	expectTrue(t, hasCycles, "has cycles")
+}
+
+func TestTopologicalLayers(t *testing.T) {
+	layers, hasCycles := TopologicalLayers(generateDirectedGraph1())
+	expectFalse(t, hasCycles, "has cycles")
+	expectEquals(t, fmt.Sprint(layers), "[[1] [2] [3 6] [4] [5]]")
+
+	gr := NewDirectedMap()
+	gr.AddArc(1, 3)
+	gr.AddArc(2, 3)
+	gr.AddNode(4)
+	layers, _ = TopologicalLayers(gr)
+	expectEquals(t, fmt.Sprint(layers), "[[1 2 4] [3]]")
+
+	gr.AddArc(3, 1)
+	layers, hasCycles = TopologicalLayers(gr)
+	expectTrue(t, hasCycles, "has cycles")
+	expectTrue(t, layers==nil, "layers are nil")
+}
+
+func TestAllTopologicalSorts(t *testing.T) {
+	t.Run("Small graph", func(t *testing.T) {
+		res := []string{}
+		for nodes := range AllTopologicalSortsSeq(generateDirectedGraph1()) {
+			res = append(res, fmt.Sprint(nodes))
+		}
+		expectEquals(t, fmt.Sprint(res), "[[1 2 3 4 5 6] [1 2 3 4 6 5] [1 2 3 6 4 5] [1 2 6 3 4 5]]")
+	})
+
+	t.Run("Without arcs", func(t *testing.T) {
+		gr := NewDirectedMap()
+		for node:=0; node<5; node++ {
+			gr.AddNode(VertexId(node))
+		}
+		cnt := 0
+		for nodes := range AllTopologicalSortsSeq(gr) {
+			expectTopologicalOrder(t, gr, nodes)
+			cnt++
+		}
+		expectEquals(t, cnt, 120)
+
+		cnt = 0
+		for _ = range AllTopologicalSortsSeq(gr) {
+			cnt++
+			if cnt==3 {
+				break
+			}
+		}
+		expectEquals(t, cnt, 3)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		gr := generateDirectedGraph1()
+		gr.AddArc(4, 1)
+		for _ = range AllTopologicalSortsSeq(gr) {
+			t.Error("order of graph with cycle")
+		}
+	})
+}
+
+func TestAcyclicOrientation_mixed(t *testing.T) {
+	t.Run("Mixed graph", func(t *testing.T) {
+		gr := NewMixedMap()
+		ReadMgraphLine(gr, "3>2-1-3")
+		ReadMgraphLine(gr, "2>4")
+		oriented, nodes, hasCycles := AcyclicOrientation_mixed(gr, nil)
+		expectFalse(t, hasCycles, "has cycles")
+		expectPath(t, nodes, 1, 3, 2, 4)
+		expectEquals(t, oriented.ArcsCnt(), 4)
+		expectTrue(t, oriented.CheckArc(1, 2), "arc 1->2")
+		expectTrue(t, oriented.CheckArc(1, 3), "arc 1->3")
+		expectTrue(t, oriented.CheckArc(3, 2), "arc 3->2")
+		expectTrue(t, oriented.CheckArc(2, 4), "arc 2->4")
+
+		nodes, hasCycles = TopologicalSort_mixed(gr)
+		expectFalse(t, hasCycles, "has cycles")
+		expectPath(t, nodes, 1, 3, 2, 4)
+	})
+
+	t.Run("Tie-breaker", func(t *testing.T) {
+		gr := NewMixedMap()
+		ReadMgraphLine(gr, "1-2")
+		oriented, nodes, _ := AcyclicOrientation_mixed(gr, func(a, b VertexId) bool { return a>b })
+		expectPath(t, nodes, 2, 1)
+		expectTrue(t, oriented.CheckArc(2, 1), "arc 2->1")
+	})
+
+	t.Run("Multigraph", func(t *testing.T) {
+		gr := NewMixedMultiMap()
+		gr.AddEdge(1, 2)
+		gr.AddEdge(1, 2)
+		gr.AddArc(2, 3)
+		gr.AddArc(2, 3)
+		gr.AddArc(1, 3)
+		gr.AddEdge(3, 1)
+		oriented, nodes, hasCycles := AcyclicOrientation_mixed(gr, nil)
+		expectFalse(t, hasCycles, "has cycles")
+		expectPath(t, nodes, 1, 2, 3)
+		expectEquals(t, arcsKey(oriented), "[1>2 1>3 2>3]")
+	})
+
+	t.Run("Cycle of arcs", func(t *testing.T) {
+		gr := NewMixedMap()
+		ReadMgraphLine(gr, "1>2>3>1-4")
+		oriented, nodes, hasCycles := AcyclicOrientation_mixed(gr, nil)
+		expectTrue(t, hasCycles, "has cycles")
+		expectTrue(t, oriented==nil && nodes==nil, "empty result")
+	})
+
+	t.Run("Random graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		for i:=0; i<200; i++ {
+			gr := NewMixedMap()
+			for node:=0; node<8; node++ {
+				gr.AddNode(VertexId(node))
+			}
+			for j:=0; j<12; j++ {
+				tail, head := VertexId(rnd.Intn(8)), VertexId(rnd.Intn(8))
+				if tail==head || gr.CheckEdgeType(tail, head)!=CT_NONE {
+					continue
+				}
+				if rnd.Intn(3)==0 {
+					gr.AddArc(tail, head)
+				} else {
+					gr.AddEdge(tail, head)
+				}
+			}
+			oriented, nodes, hasCycles := AcyclicOrientation_mixed(gr, nil)
+			_, arcsHaveCycles := KahnTopologicalSort(gr, nil)
+			expectEquals(t, hasCycles, arcsHaveCycles)
+			if hasCycles {
+				continue
+			}
+			expectEquals(t, oriented.ArcsCnt(), gr.ConnectionsCnt())
+			for arc := range gr.ArcsSeq() {
+				expectTrue(t, oriented.CheckArc(arc.Tail, arc.Head), "arc is kept")
+			}
+			for edge := range gr.EdgesSeq() {
+				expectTrue(t, oriented.CheckArc(edge.Tail, edge.Head)!=oriented.CheckArc(edge.Head, edge.Tail), "edge is oriented")
+			}
+			expectTopologicalOrder(t, oriented, nodes)
+			expectTrue(t, FindCycle(oriented)==nil, "oriented graph is acyclic")
+		}
+	})
+}
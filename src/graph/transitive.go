@@ -0,0 +1,204 @@
+package graph
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/StepLg/go-graph/src/erx"
+)
+
+// Transitive reduction of directed acyclic graph
+//
+// Result graph contains all vertexes from original graph and arcs i->j, if
+// there is no other path from i to j in original graph. Reduction of DAG is
+// unique and it's the smallest graph with the same reachability.
+//
+// Reachability is stored as bitsets, so it takes O(V*E/64) time and
+// O(V^2/8) bytes of memory. Panic with *CycleError if graph has cycles, use
+// CondensedTransitiveReduction for such graphs.
+func TransitiveReduction(gr DirectedGraphReader) DirectedGraph {
+	res, err := TryTransitiveReduction(gr)
+	if err!=nil {
+		erxErr := erx.NewSequentLevel("Transitive reduction.", err, 1)
+		erxErr.AddV("cycle", err.(*CycleError).Cycle)
+		panic(erxErr)
+	}
+	return res
+}
+
+// Transitive reduction of directed acyclic graph
+//
+// Returns *CycleError (ErrCycle) if graph has cycles. See TransitiveReduction
+// for details.
+func TryTransitiveReduction(gr DirectedGraphReader) (DirectedGraph, error) {
+	nodes, hasCycles := KahnTopologicalSort(gr, nil)
+	if hasCycles {
+		return nil, newCycleError("transitive reduction", FindCycle(gr))
+	}
+
+	_, reduced := dagReachability(gr, nodes)
+	res := NewDirectedMap()
+	for _, node := range nodes {
+		res.AddNode(node)
+	}
+	for tail, heads := range reduced {
+		for _, head := range heads {
+			res.AddArc(nodes[tail], nodes[head])
+		}
+	}
+	return res, nil
+}
+
+// Transitive reduction of any directed graph
+//
+// Reduction is built for condensation of graph: vertexes of each strongly
+// connected component are connected with a simple cycle (in order of
+// CondensedGraph.Vertexes, so these arcs could be absent in original graph)
+// and each arc of reduced condensation is replaced with any original arc
+// between components. Loops are removed. Result has the same reachability
+// as original graph with minimal number of arcs, but it isn't unique for
+// graphs with cycles. For DAG result is the same as with TransitiveReduction.
+func CondensedTransitiveReduction(gr DirectedGraphReader) DirectedGraph {
+	condensed := Condensation(gr)
+	_, reduced := dagReachability(condensed, condensedOrder(condensed))
+	isReduced := make(map[Connection]bool)
+	for tail, heads := range reduced {
+		for _, head := range heads {
+			isReduced[Connection{VertexId(tail), VertexId(head)}] = true
+		}
+	}
+
+	res := NewDirectedMap()
+	for node := range VertexesSeqOf(gr) {
+		res.AddNode(node)
+	}
+	for _, component := range condensed.Components() {
+		if len(component)>1 {
+			for i, tail := range component {
+				res.AddArc(tail, component[(i+1)%len(component)])
+			}
+		}
+	}
+	for arc := range ArcsSeqOf(gr) {
+		tail, _ := condensed.Component(arc.Tail)
+		head, _ := condensed.Component(arc.Head)
+		conn := Connection{tail, head}
+		if isReduced[conn] {
+			res.AddArc(arc.Tail, arc.Head)
+			// only one arc for each pair of components
+			delete(isReduced, conn)
+		}
+	}
+	return res
+}
+
+// Transitive closure of directed graph
+//
+// Result graph contains all vertexes from original graph and arcs i->j, if
+// there is any path from i to j in original graph. Loop i->i is added if i
+// belongs to a cycle (including loop in original graph). Graph may have
+// cycles: closure is built for its condensation.
+func TransitiveClosure(gr DirectedGraphReader) DirectedGraph {
+	condensed := Condensation(gr)
+	reach, _ := dagReachability(condensed, condensedOrder(condensed))
+	components := condensed.Components()
+
+	res := NewDirectedMap()
+	for node := range VertexesSeqOf(gr) {
+		res.AddNode(node)
+	}
+	for i, component := range components {
+		cyclic := len(component)>1 || gr.CheckArc(component[0], component[0])
+		for _, tail := range component {
+			if cyclic {
+				for _, head := range component {
+					res.AddArc(tail, head)
+				}
+			}
+			reach[i].each(func(j int) {
+				for _, head := range components[j] {
+					res.AddArc(tail, head)
+				}
+			})
+		}
+	}
+	return res
+}
+
+// Vertexes of condensed graph in topological order
+func condensedOrder(gr *CondensedGraph) []VertexId {
+	nodes := make([]VertexId, len(gr.Components()))
+	for i, _ := range nodes {
+		nodes[i] = VertexId(i)
+	}
+	return nodes
+}
+
+// Reachability of directed acyclic graph vertexes
+//
+// nodes must be all graph vertexes in topological order, and result is
+// indexed by positions in nodes: reach[i] contains all vertexes, accessible
+// from nodes[i], and reduced[i] contains accessors of nodes[i], which aren't
+// accessible by any other path. Vertexes are processed in reverse topological
+// order, and accessors in topological order, so accessor is redundant if it's
+// already reachable through the previous ones.
+func dagReachability(gr DirectedGraphArcsReader, nodes []VertexId) (reach []bitset, reduced [][]int) {
+	pos := make(map[VertexId]int, len(nodes))
+	for i, node := range nodes {
+		pos[node] = i
+	}
+	reach = make([]bitset, len(nodes))
+	reduced = make([][]int, len(nodes))
+	for i:=len(nodes)-1; i>=0; i-- {
+		reach[i] = newBitset(len(nodes))
+		heads := []int{}
+		for next := range VertexesSeqOf(gr.GetAccessors(nodes[i])) {
+			heads = append(heads, pos[next])
+		}
+		sort.Ints(heads)
+		for _, head := range heads {
+			if reach[i].test(head) {
+				continue
+			}
+			reduced[i] = append(reduced[i], head)
+			reach[i].set(head)
+			reach[i].union(reach[head])
+		}
+	}
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// bitset
+
+// Fixed size set of integers from 0 to size-1
+type bitset []uint64
+
+func newBitset(size int) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1<<uint(i%64)
+}
+
+func (b bitset) test(i int) bool {
+	return b[i/64]&(1<<uint(i%64))!=0
+}
+
+// Add all elements of other set of the same size
+func (b bitset) union(other bitset) {
+	for i, word := range other {
+		b[i] |= word
+	}
+}
+
+// Call function for each element in ascending order
+func (b bitset) each(f func(i int)) {
+	for i, word := range b {
+		for word!=0 {
+			f(i*64 + bits.TrailingZeros64(word))
+			word &= word-1
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// Sorted arcs of directed graph
+func arcsKey(gr DirectedGraphArcsReader) string {
+	arcs := []string{}
+	for arc := range ArcsSeqOf(gr) {
+		arcs = append(arcs, fmt.Sprintf("%v>%v", arc.Tail, arc.Head))
+	}
+	sort.Strings(arcs)
+	return fmt.Sprint(arcs)
+}
+
+// Random directed graph, which is acyclic if arcs go only forward
+func randomDirectedGraph(rnd *rand.Rand, size, arcsCnt int, acyclic bool) DirectedGraph {
+	gr := NewDirectedMatrix(size)
+	for node:=0; node<size; node++ {
+		gr.AddNode(VertexId(node))
+	}
+	for i:=0; i<arcsCnt; i++ {
+		tail, head := VertexId(rnd.Intn(size)), VertexId(rnd.Intn(size))
+		if acyclic && tail>=head || gr.CheckArc(tail, head) {
+			continue
+		}
+		gr.AddArc(tail, head)
+	}
+	return gr
+}
+
+// Transitive closure by breadth-first search from each vertex
+func bruteForceClosure(gr DirectedGraphReader) DirectedGraph {
+	res := NewDirectedMap()
+	for node := range VertexesSeqOf(gr) {
+		res.AddNode(node)
+	}
+	for node := range VertexesSeqOf(gr) {
+		BreadthFirst(NewDgraphOutNeighboursExtractor(gr), CollectVertexes(gr.GetAccessors(node)), Visitor{
+			DiscoverVertex: func(head VertexId) bool {
+				res.AddArc(node, head)
+				return true
+			},
+		})
+	}
+	return res
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	t.Run("Simple graph", func(t *testing.T) {
+		res := TransitiveReduction(generateDirectedGraph1())
+		expectEquals(t, res.Order(), 6)
+		expectEquals(t, arcsKey(res), "[1>2 2>3 2>6 3>4 4>5]")
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		gr := generateDirectedGraph1()
+		gr.AddArc(5, 3)
+		_, err := TryTransitiveReduction(gr)
+		expectErrorIs(t, err, ErrCycle)
+		expectPanic(t, "graph has cycle", func() { TransitiveReduction(gr) })
+	})
+
+	t.Run("Multigraph", func(t *testing.T) {
+		gr := NewDirectedMultiMap()
+		gr.AddArc(1, 2)
+		gr.AddArc(1, 2)
+		gr.AddArc(2, 3)
+		gr.AddArc(1, 3)
+		expectEquals(t, arcsKey(TransitiveReduction(gr)), "[1>2 2>3]")
+		expectEquals(t, arcsKey(TransitiveClosure(gr)), "[1>2 1>3 2>3]")
+	})
+
+	t.Run("ReduceDirectPaths", func(t *testing.T) {
+		gr := NewDirectedMultiMap()
+		gr.AddArc(1, 2)
+		gr.AddArc(1, 2)
+		gr.AddArc(2, 3)
+		gr.AddArc(1, 3)
+		// dijkstra based reduction would add parallel arc 1->2 twice and
+		// panic, so this checks that transitive reduction is used
+		res := NewDirectedMap()
+		ReduceDirectPaths(gr, res, nil)
+		expectEquals(t, arcsKey(res), "[1>2 2>3]")
+
+		// graph with cycle falls back to dijkstra
+		cyclic := generateDirectedGraph1()
+		cyclic.AddArc(5, 4)
+		cyclic.AddArc(5, 1)
+		res = NewDirectedMap()
+		ReduceDirectPaths(cyclic, res, nil)
+		expectFalse(t, res.CheckArc(2, 4), "arc 2->4")
+		expectTrue(t, res.CheckArc(4, 5), "arc 4->5")
+	})
+
+	t.Run("Random graphs", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		for i:=0; i<50; i++ {
+			gr := randomDirectedGraph(rnd, 15, 40, true)
+			slow := NewDirectedMap()
+			ReduceDirectPaths(gr, slow, func(from, to VertexId, weight float64) bool { return false })
+			expectEquals(t, arcsKey(TransitiveReduction(gr)), arcsKey(slow))
+			expectEquals(t, arcsKey(CondensedTransitiveReduction(gr)), arcsKey(slow))
+		}
+	})
+}
+
+func TestCondensedTransitiveReduction(t *testing.T) {
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "1>2>3>1>4>5")
+	ReadDgraphLine(gr, "2>4")
+	ReadDgraphLine(gr, "1>5")
+	expected := TransitiveClosure(gr)
+	gr.AddArc(5, 5)
+	res := CondensedTransitiveReduction(gr)
+	expectEquals(t, res.Order(), 5)
+	expectEquals(t, res.ArcsCnt(), 5)
+	expectTrue(t, res.CheckArc(4, 5), "arc 4->5")
+	expectFalse(t, res.CheckArc(5, 5), "loop 5->5")
+	expectEquals(t, arcsKey(TransitiveClosure(res)), arcsKey(expected))
+
+	rnd := rand.New(rand.NewSource(1))
+	for i:=0; i<50; i++ {
+		gr := randomDirectedGraph(rnd, 12, 20, false)
+		res := CondensedTransitiveReduction(gr)
+		expectEquals(t, arcsKey(bruteForceClosure(res)), arcsKey(bruteForceClosure(withoutLoops(gr))))
+	}
+}
+
+// Copy of directed graph without loops
+func withoutLoops(gr DirectedGraphReader) DirectedGraph {
+	res := NewDirectedMap()
+	for node := range VertexesSeqOf(gr) {
+		res.AddNode(node)
+	}
+	for arc := range ArcsSeqOf(gr) {
+		if arc.Tail!=arc.Head {
+			res.AddArc(arc.Tail, arc.Head)
+		}
+	}
+	return res
+}
+
+func TestTransitiveClosure(t *testing.T) {
+	res := TransitiveClosure(generateDirectedGraph1())
+	expectEquals(t, arcsKey(res), "[1>2 1>3 1>4 1>5 1>6 2>3 2>4 2>5 2>6 3>4 3>5 4>5]")
+
+	gr := NewDirectedMap()
+	ReadDgraphLine(gr, "1>2>1>3")
+	gr.AddArc(3, 3)
+	gr.AddNode(4)
+	res = TransitiveClosure(gr)
+	expectEquals(t, res.Order(), 4)
+	expectEquals(t, arcsKey(res), "[1>1 1>2 1>3 2>1 2>2 2>3 3>3]")
+
+	rnd := rand.New(rand.NewSource(1))
+	for i:=0; i<50; i++ {
+		gr := randomDirectedGraph(rnd, 12, 20, false)
+		expectEquals(t, arcsKey(TransitiveClosure(gr)), arcsKey(bruteForceClosure(gr)))
+	}
+}
@@ -0,0 +1,188 @@
+package graph
+
+// Class of connection in traversal tree.
+type EdgeClass uint8
+
+const (
+	EC_TREE EdgeClass = iota + 1 // connection to undiscovered vertex
+	EC_BACK // connection to ancestor in traversal tree (including loops)
+	EC_FORWARD // connection to already finished descendant
+	EC_CROSS // connection to finished vertex in other subtree or tree
+	// Connection to already discovered vertex in breadth-first traversal,
+	// which doesn't distinguish back and cross connections.
+	EC_NON_TREE
+)
+
+func (c EdgeClass) String() string {
+	switch c {
+		case EC_TREE:
+			return "tree"
+		case EC_BACK:
+			return "back"
+		case EC_FORWARD:
+			return "forward"
+		case EC_CROSS:
+			return "cross"
+		case EC_NON_TREE:
+			return "non-tree"
+	}
+	return "unknown"
+}
+
+// Traversal callbacks
+//
+// All callbacks are optional. Traversal stops as soon as any of them returns
+// false.
+type Visitor struct {
+	// New traversal tree started from source vertex
+	StartVertex func(node VertexId) bool
+	// Vertex is reached first time
+	DiscoverVertex func(node VertexId) bool
+	// Connection is going to be checked. Called before ClassifiedEdge.
+	ExamineEdge func(tail, head VertexId) bool
+	// Connection class in traversal tree is known
+	ClassifiedEdge func(tail, head VertexId, class EdgeClass) bool
+	// All connections from vertex are examined
+	FinishVertex func(node VertexId) bool
+}
+
+func (v *Visitor) start(node VertexId) bool {
+	return v.StartVertex==nil || v.StartVertex(node)
+}
+
+func (v *Visitor) discover(node VertexId) bool {
+	return v.DiscoverVertex==nil || v.DiscoverVertex(node)
+}
+
+func (v *Visitor) edge(tail, head VertexId, class EdgeClass) bool {
+	if v.ExamineEdge!=nil && !v.ExamineEdge(tail, head) {
+		return false
+	}
+	return v.ClassifiedEdge==nil || v.ClassifiedEdge(tail, head, class)
+}
+
+func (v *Visitor) finish(node VertexId) bool {
+	return v.FinishVertex==nil || v.FinishVertex(node)
+}
+
+// Breadth-first traversal
+//
+// Traversal starts from each of sources, which isn't discovered yet, in
+// given order. Non tree connections are classified as EC_NON_TREE. With
+// undirected graph extractors each edge is examined in both directions.
+//
+// Returns false if traversal was stopped by visitor.
+func BreadthFirst(gr OutNeighboursExtractor, sources Vertexes, visitor Visitor) bool {
+	discovered := newVertexesMarks[bool](gr)
+	queue := make(Vertexes, 0)
+	for _, source := range sources {
+		if discovered.has(source) {
+			continue
+		}
+		if !visitor.start(source) || !visitor.discover(source) {
+			return false
+		}
+		discovered.set(source, true)
+		queue = append(queue[:0], source)
+		for len(queue)>0 {
+			node := queue[0]
+			queue = queue[1:]
+			for next := range VertexesSeqOf(gr.GetOutNeighbours(node)) {
+				if discovered.has(next) {
+					if !visitor.edge(node, next, EC_NON_TREE) {
+						return false
+					}
+					continue
+				}
+				if !visitor.edge(node, next, EC_TREE) {
+					return false
+				}
+				if !visitor.discover(next) {
+					return false
+				}
+				discovered.set(next, true)
+				queue = append(queue, next)
+			}
+			if !visitor.finish(node) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Depth-first traversal stack frame.
+type dfsFrame struct {
+	node VertexId
+	discoverTime int
+	neighbours Vertexes
+	pos int
+}
+
+// Depth-first traversal
+//
+// Traversal is iterative, so it's safe for deep graphs. It starts from each
+// of sources, which isn't discovered yet, in given order. With undirected
+// graph extractors each edge is examined in both directions, so connection
+// to parent in traversal tree is classified as EC_BACK.
+//
+// Returns false if traversal was stopped by visitor.
+func DepthFirst(gr OutNeighboursExtractor, sources Vertexes, visitor Visitor) bool {
+	// discovery order of vertexes: vertex is grey while it isn't finished
+	discoverTime := newVertexesMarks[int](gr)
+	discoveredCnt := 0
+	finished := newVertexesMarks[bool](gr)
+	stack := make([]dfsFrame, 0)
+	
+	discover := func(node VertexId) bool {
+		discoverTime.set(node, discoveredCnt)
+		discoveredCnt++
+		if !visitor.discover(node) {
+			return false
+		}
+		stack = append(stack, dfsFrame{node: node, discoverTime: discoveredCnt-1, neighbours: CollectVertexes(gr.GetOutNeighbours(node))})
+		return true
+	}
+	
+	for _, source := range sources {
+		if discoverTime.has(source) {
+			continue
+		}
+		if !visitor.start(source) || !discover(source) {
+			return false
+		}
+		for len(stack)>0 {
+			frame := &stack[len(stack)-1]
+			if frame.pos==len(frame.neighbours) {
+				finished.set(frame.node, true)
+				stack = stack[:len(stack)-1]
+				if !visitor.finish(frame.node) {
+					return false
+				}
+				continue
+			}
+			node, next := frame.node, frame.neighbours[frame.pos]
+			frame.pos++
+			
+			nextTime, isDiscovered := discoverTime.get(next)
+			var class EdgeClass
+			switch {
+				case !isDiscovered:
+					class = EC_TREE
+				case !finished.has(next):
+					class = EC_BACK
+				case frame.discoverTime<nextTime:
+					class = EC_FORWARD
+				default:
+					class = EC_CROSS
+			}
+			if !visitor.edge(node, next, class) {
+				return false
+			}
+			if class==EC_TREE && !discover(next) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// Extractor with neighbours in ascending order to make traversal predictable.
+type sortedExtractor struct {
+	OutNeighboursExtractor
+}
+
+func (e sortedExtractor) GetOutNeighbours(node VertexId) VertexesIterable {
+	neighbours := CollectVertexes(e.OutNeighboursExtractor.GetOutNeighbours(node))
+	sort.Slice(neighbours, func(i, j int) bool { return neighbours[i]<neighbours[j] })
+	return &nodesIterableLambdaHelper{seq: slices.Values(neighbours)}
+}
+
+// Visitor, which logs all events.
+func loggingVisitor(log *[]string) Visitor {
+	return Visitor{
+		StartVertex: func(node VertexId) bool {
+			*log = append(*log, fmt.Sprintf("start %v", node))
+			return true
+		},
+		DiscoverVertex: func(node VertexId) bool {
+			*log = append(*log, fmt.Sprintf("discover %v", node))
+			return true
+		},
+		ClassifiedEdge: func(tail, head VertexId, class EdgeClass) bool {
+			*log = append(*log, fmt.Sprintf("%v %v->%v", class, tail, head))
+			return true
+		},
+		FinishVertex: func(node VertexId) bool {
+			*log = append(*log, fmt.Sprintf("finish %v", node))
+			return true
+		},
+	}
+}
+
+func expectLog(t *testing.T, log []string, expected ...string) {
+	t.Helper()
+	expectEquals(t, fmt.Sprint(log), fmt.Sprint(expected))
+}
+
+func TestDepthFirst(t *testing.T) {
+	gr := generateDirectedGraph1()
+	gr.AddArc(5, 3)
+	gr.AddArc(7, 4)
+	extractor := sortedExtractor{NewDgraphOutNeighboursExtractor(gr)}
+
+	t.Run("edges classification", func(t *testing.T) {
+		log := make([]string, 0)
+		expectTrue(t, DepthFirst(extractor, Vertexes{1, 7, 2}, loggingVisitor(&log)), "traversal completed")
+		expectLog(t, log,
+			"start 1", "discover 1",
+			"tree 1->2", "discover 2",
+			"tree 2->3", "discover 3",
+			"tree 3->4", "discover 4",
+			"tree 4->5", "discover 5",
+			"back 5->3", "finish 5", "finish 4", "finish 3",
+			"forward 2->4",
+			"tree 2->6", "discover 6", "finish 6", "finish 2",
+			"forward 1->6", "finish 1",
+			"start 7", "discover 7", "cross 7->4", "finish 7",
+		)
+	})
+
+	t.Run("early stop", func(t *testing.T) {
+		discovered := 0
+		completed := DepthFirst(extractor, Vertexes{1}, Visitor{
+			DiscoverVertex: func(node VertexId) bool {
+				discovered++
+				return node!=3
+			},
+		})
+		expectFalse(t, completed, "traversal completed")
+		expectEquals(t, discovered, 3)
+
+		examined := 0
+		completed = DepthFirst(extractor, Vertexes{1}, Visitor{
+			ExamineEdge: func(tail, head VertexId) bool {
+				examined++
+				return true
+			},
+			ClassifiedEdge: func(tail, head VertexId, class EdgeClass) bool {
+				return class!=EC_BACK
+			},
+		})
+		expectFalse(t, completed, "traversal completed")
+		expectEquals(t, examined, 5)
+	})
+
+	t.Run("deep graph", func(t *testing.T) {
+		gr := NewDirectedMap()
+		depth := 200000
+		for i:=0; i<depth; i++ {
+			gr.AddArc(VertexId(i), VertexId(i+1))
+		}
+		finished := 0
+		DepthFirst(NewDgraphOutNeighboursExtractor(gr), Vertexes{0}, Visitor{
+			FinishVertex: func(node VertexId) bool {
+				expectEquals(t, node, VertexId(depth-finished))
+				finished++
+				return true
+			},
+		})
+		expectEquals(t, finished, depth+1)
+
+		nodes, hasCycles := TopologicalSort(gr)
+		expectFalse(t, hasCycles, "graph has cycles")
+		expectEquals(t, nodes[depth], VertexId(depth))
+	})
+}
+
+func TestBreadthFirst(t *testing.T) {
+	gr := generateDirectedGraph1()
+	extractor := sortedExtractor{NewDgraphOutNeighboursExtractor(gr)}
+
+	t.Run("order", func(t *testing.T) {
+		log := make([]string, 0)
+		expectTrue(t, BreadthFirst(extractor, Vertexes{2, 1}, loggingVisitor(&log)), "traversal completed")
+		expectLog(t, log,
+			"start 2", "discover 2",
+			"tree 2->3", "discover 3",
+			"tree 2->4", "discover 4",
+			"tree 2->6", "discover 6",
+			"finish 2",
+			"non-tree 3->4", "finish 3",
+			"tree 4->5", "discover 5", "finish 4",
+			"finish 6", "finish 5",
+			"start 1", "discover 1",
+			"non-tree 1->2", "non-tree 1->6", "finish 1",
+		)
+	})
+
+	t.Run("undirected graph", func(t *testing.T) {
+		ugr := NewUndirectedMap()
+		ReadUgraphLine(ugr, "1-2-3-4")
+		levels := make(map[VertexId]int)
+		levels[1] = 0
+		BreadthFirst(NewUgraphOutNeighboursExtractor(ugr), Vertexes{1}, Visitor{
+			ClassifiedEdge: func(tail, head VertexId, class EdgeClass) bool {
+				if class==EC_TREE {
+					levels[head] = levels[tail]+1
+				}
+				return true
+			},
+		})
+		expectEquals(t, levels[4], 3)
+	})
+
+	t.Run("early stop", func(t *testing.T) {
+		finished := 0
+		completed := BreadthFirst(extractor, Vertexes{1}, Visitor{
+			FinishVertex: func(node VertexId) bool {
+				finished++
+				return finished<2
+			},
+		})
+		expectFalse(t, completed, "traversal completed")
+		expectEquals(t, finished, 2)
+	})
+}